@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/budyaya/resty-stress-tester/internal/cluster"
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/internal/reporter"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/budyaya/resty-stress-tester/pkg/version"
+)
+
+// runAgent 启动 agent，等待 controller 下发配置并执行压测
+func runAgent(args []string) types.ExitStatus {
+	fs := flag.NewFlagSet("agent", flag.ContinueOnError)
+	listen := fs.String("listen", ":8080", "Address to listen on for the controller")
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return types.ExitStatus{Reason: types.ExitReasonOK, Code: 0}
+	} else if err != nil {
+		return types.ExitStatus{Reason: types.ExitReasonConfigError, Code: 1}
+	}
+
+	fmt.Printf("Resty-Stress-Tester %s\n", version.Version)
+	fmt.Printf("Agent listening on %s\n", *listen)
+	if err := cluster.NewAgent().ListenAndServe(*listen); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return types.ExitStatus{Reason: types.ExitReasonEngineError, Code: 1}
+	}
+	return types.ExitStatus{Reason: types.ExitReasonOK, Code: 0}
+}
+
+// runControl 向 -agents 下发压测配置，同步启动后合并各 agent 的结果并按一次运行输出报告
+func runControl(args []string) types.ExitStatus {
+	cfg, err := config.LoadFromArgs(args)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		fmt.Printf("\nUsage:\n")
+		printUsage()
+		return types.ExitStatus{Reason: types.ExitReasonConfigError, Code: 1}
+	}
+	agents := cfg.AgentAddresses()
+	if len(agents) == 0 {
+		fmt.Printf("Error: rst control requires -agents\n")
+		return types.ExitStatus{Reason: types.ExitReasonConfigError, Code: 1}
+	}
+
+	fmt.Printf("Resty-Stress-Tester %s\n", version.Version)
+	fmt.Printf("Starting distributed stress test...\n")
+	fmt.Printf("Agents:       %d\n", len(agents))
+	fmt.Printf("Per agent:    %s\n", cfg.GetTestDescription())
+	fmt.Println()
+
+	merged, result, err := cluster.NewController(agents).Run(cfg)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return types.ExitStatus{Reason: types.ExitReasonEngineError, Code: 1}
+	}
+
+	r := reporter.NewReporter(merged)
+	r.ConsoleReport(result)
+	if merged.SavesReport() {
+		if err := r.GenerateReport(result); err != nil {
+			fmt.Printf("Error generating report: %v\n", err)
+		} else if merged.OutputFile != "" {
+			fmt.Printf("Report saved to: %s\n", merged.OutputFile)
+		}
+	}
+
+	slo := result.EvaluateSLOs(merged.SLOThresholds())
+	printSLOReport(slo)
+	return result.ExitStatus(slo)
+}
@@ -1,52 +1,191 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/budyaya/resty-stress-tester/internal/config"
 	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
 	"github.com/budyaya/resty-stress-tester/pkg/version"
 )
 
+// interruptGrace 收到中断信号后等待进行中的请求完成的时间
+const interruptGrace = 5 * time.Second
+
 func main() {
+	// os.Exit 不会执行 defer，因此把主流程放在 run 中，确保资源清理后再退出
+	status := run()
+
+	// 最后一行输出机器可读的退出原因，便于 CI 解析
+	line, _ := json.Marshal(status)
+	fmt.Println(string(line))
+
+	os.Exit(status.Code)
+}
+
+// run 执行压测并返回进程退出状态
+func run() types.ExitStatus {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "merge":
+			return runMerge(os.Args[2:])
+		case "agent":
+			return runAgent(os.Args[2:])
+		case "control":
+			return runControl(os.Args[2:])
+		}
+	}
+
 	// 加载配置
 	cfg, err := config.LoadFromFlags()
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		fmt.Printf("\nUsage:\n")
 		printUsage()
-		os.Exit(1)
+		return types.ExitStatus{Reason: types.ExitReasonConfigError, Code: 1}
+	}
+	if cfg.Agents != "" {
+		fmt.Printf("Error: -agents requires rst control\n")
+		return types.ExitStatus{Reason: types.ExitReasonConfigError, Code: 1}
 	}
 
 	// 显示测试信息
 	fmt.Printf("Resty-Stress-Tester %s\n", version.Version)
 	fmt.Printf("Starting stress test...\n")
-	fmt.Printf("URL:          %s\n", cfg.URL)
+	if len(cfg.Targets) > 0 {
+		fmt.Printf("Targets:      %d\n", len(cfg.Targets))
+	} else {
+		fmt.Printf("URL:          %s\n", cfg.URL)
+	}
 	fmt.Printf("Method:       %s\n", cfg.Method)
 	fmt.Printf("Concurrency:  %d\n", cfg.Concurrency)
+	if cfg.Runs() > 1 {
+		fmt.Printf("Runs:         %d\n", cfg.Runs())
+	}
 
 	if cfg.IsDurationBased() {
 		fmt.Printf("Duration:     %v\n", cfg.Duration)
+	} else if cfg.Successes > 0 {
+		fmt.Printf("Successes:    %d (max %d attempts)\n", cfg.Successes, cfg.AttemptLimit())
 	} else {
 		fmt.Printf("Total:        %d\n", cfg.TotalRequests)
 	}
 
+	if cfg.RateSchedule != "" {
+		fmt.Printf("Rate Plan:    %s\n", cfg.RateSchedule)
+	} else if cfg.Spike {
+		fmt.Printf("Rate Plan:    spike %.1f -> %.1f req/sec\n", cfg.SpikeBaselineRate, cfg.SpikeRate)
+	}
+
 	if cfg.CSVFile != "" {
 		fmt.Printf("CSV File:     %s\n", cfg.CSVFile)
 	}
 
+	if cfg.OutputDir != "" {
+		fmt.Printf("Output Dir:   %s\n", cfg.OutputDir)
+	}
+
 	if cfg.OutputFile != "" {
 		fmt.Printf("Output:       %s\n", cfg.OutputFile)
 	}
+
+	if cfg.MetricsAddr != "" {
+		fmt.Printf("Metrics:      http://%s/metrics\n", cfg.MetricsAddr)
+	}
 	fmt.Println()
 
+	// 重复运行时每次使用新的引擎，退出状态以第一个未通过门限的运行为准
+	runs := cfg.Runs()
+	results := make([]*types.StressResult, 0, runs)
+	var failed []types.SLOReport
+	var failFast *types.RequestResult
+	for run := 1; run <= runs; run++ {
+		if runs > 1 {
+			fmt.Printf("\n=== Run %d/%d ===\n", run, runs)
+		}
+
+		result, slo, err := runOnce(cfg)
+		if err != nil {
+			fmt.Printf("Error creating stress tester: %v\n", err)
+			return types.ExitStatus{Reason: types.ExitReasonEngineError, Code: 1}
+		}
+		results = append(results, result)
+
+		if !slo.Passed {
+			failed = append(failed, slo)
+		}
+
+		// 被中断时不再执行剩余的运行，退出状态按已收集的结果判定
+		if result.Interrupted {
+			fmt.Printf("\n⚠️  Test interrupted, results are partial\n")
+			break
+		}
+
+		// 快速失败时不再执行剩余的运行
+		if failFast = result.GetFailFast(); failFast != nil {
+			break
+		}
+	}
+
+	if len(results) > 1 {
+		printRepeatSummary(types.CombineRuns(results))
+	}
+
+	if failFast != nil {
+		fmt.Printf("\n❌ Test stopped on first failure: %s\n", failFast.Error)
+		return types.ExitStatus{Reason: types.ExitReasonFailFast, Code: 1}
+	}
+
+	// 没有完成任何请求时门限检查没有意义（错误率为 0 会被视为通过）
+	for run, result := range results {
+		if result.TotalRequests > 0 {
+			continue
+		}
+		if runs > 1 {
+			fmt.Printf("\n❌ Test failed: no requests completed in run %d\n", run+1)
+		} else {
+			fmt.Printf("\n❌ Test failed: no requests completed\n")
+		}
+		return result.ExitStatus(types.SLOReport{})
+	}
+
+	if len(failed) == 0 {
+		fmt.Printf("\n✅ Test completed successfully\n")
+		return types.ExitStatus{Reason: types.ExitReasonOK, Code: 0}
+	}
+
+	if runs > 1 {
+		fmt.Printf("\n❌ Test failed: SLO gates failed in %d of %d runs: %s\n", len(failed), runs, describeFailedGates(failed[0]))
+	} else {
+		fmt.Printf("\n❌ Test failed: %d of %d SLO gates failed: %s\n", len(failed[0].FailedGates()), len(failed[0].Gates), describeFailedGates(failed[0]))
+	}
+	return failed[0].ExitStatus()
+}
+
+// runOnce 创建引擎执行一次压测，输出报告并检查 SLO 门限
+func runOnce(cfg *config.Config) (*types.StressResult, types.SLOReport, error) {
 	// 创建压测引擎
 	tester, err := engine.NewStressEngine(cfg)
 	if err != nil {
-		fmt.Printf("Error creating stress tester: %v\n", err)
-		os.Exit(1)
+		return nil, types.SLOReport{}, err
 	}
+	defer tester.Cleanup()
+
+	// 第一次中断时排空进行中的请求后照常输出报告，第二次中断立即退出
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	defer func() {
+		signal.Stop(signals)
+		close(done)
+	}()
+	go handleInterrupt(tester, signals, done)
 
 	// 运行压测
 	result := tester.Run()
@@ -54,30 +193,112 @@ func main() {
 	// 生成报告
 	tester.PrintReport()
 
-	// 保存详细报告（如果指定了输出文件）
-	if cfg.OutputFile != "" && cfg.ReportFormat != "console" {
+	// 保存详细报告（指定了输出文件或报告模板时）
+	if cfg.SavesReport() {
 		if err := tester.GenerateReport(); err != nil {
 			fmt.Printf("Error generating report: %v\n", err)
-		} else {
+		} else if cfg.OutputFile != "" {
 			fmt.Printf("Report saved to: %s\n", cfg.OutputFile)
 		}
 	}
 
-	// 根据错误率决定退出码
-	if result.ShouldFail() {
-		fmt.Printf("\n❌ Test failed: High error rate detected (%.1f%%)\n",
-			100-result.GetSuccessRate())
-		os.Exit(1)
-	} else {
-		fmt.Printf("\n✅ Test completed successfully\n")
+	// 导出时间序列
+	if cfg.TimeSeriesOut != "" {
+		if err := tester.ExportTimeSeries(); err != nil {
+			fmt.Printf("Error exporting time series: %v\n", err)
+		} else {
+			fmt.Printf("Time series saved to: %s\n", cfg.TimeSeriesOut)
+		}
 	}
+
+	// 导出 HdrHistogram 直方图
+	if cfg.HistogramOut != "" {
+		if err := tester.ExportHistogram(); err != nil {
+			fmt.Printf("Error exporting histogram: %v\n", err)
+		} else {
+			fmt.Printf("Histogram saved to: %s\n", cfg.HistogramOut)
+		}
+	}
+
+	// 导出响应时间累积分布
+	if cfg.CDFOut != "" {
+		if err := tester.ExportCDF(); err != nil {
+			fmt.Printf("Error exporting CDF: %v\n", err)
+		} else {
+			fmt.Printf("CDF saved to: %s\n", cfg.CDFOut)
+		}
+	}
+
+	// 根据 SLO 门限决定退出码
+	slo := result.EvaluateSLOs(cfg.SLOThresholds())
+	if result.TotalRequests > 0 {
+		printSLOReport(slo)
+	}
+	return result, slo, nil
+}
+
+// handleInterrupt 收到第一个中断信号时排空引擎，Run 随后返回已收集的结果；
+// 排空或输出报告期间再次收到信号时立即退出。done 关闭表示本次运行已结束
+func handleInterrupt(tester *engine.StressEngine, signals <-chan os.Signal, done <-chan struct{}) {
+	select {
+	case <-signals:
+	case <-done:
+		return
+	}
+
+	fmt.Printf("\nInterrupted, waiting up to %v for in-flight requests (press Ctrl-C again to exit now)\n", interruptGrace)
+	tester.Drain(interruptGrace)
+
+	select {
+	case <-signals:
+		fmt.Println("\nForced exit")
+		os.Exit(130)
+	case <-done:
+	}
+}
+
+// printRepeatSummary 打印每次运行的分位数以及合并所有样本后的分位数
+func printRepeatSummary(summary *types.RepeatSummary) {
+	fmt.Printf("\nRepeated Runs:\n")
+	fmt.Printf("  %-5s %10s %10s %12s %12s %12s\n", "Run", "Requests", "Success", "P50", "P90", "P99")
+	for _, run := range summary.Runs {
+		fmt.Printf("  %-5d %10d %10d %12v %12v %12v\n", run.Run, run.TotalRequests, run.SuccessfulRequests,
+			run.P50ResponseTime, run.P90ResponseTime, run.P99ResponseTime)
+	}
+	fmt.Printf("  %-5s %10d %10d %12v %12v %12v\n", "All", summary.TotalRequests, summary.SuccessfulRequests,
+		summary.P50ResponseTime, summary.P90ResponseTime, summary.P99ResponseTime)
+	fmt.Printf("  Combined P99: %v (mean of per-run P99: %v)\n", summary.P99ResponseTime, summary.MeanP99)
+}
+
+// printSLOReport 打印每个 SLO 门限的实际值与阈值
+func printSLOReport(slo types.SLOReport) {
+	fmt.Printf("\nSLO Gates:\n")
+	for _, gate := range slo.Gates {
+		status := "PASS"
+		if !gate.Passed {
+			status = "FAIL"
+		}
+		fmt.Printf("  [%s] %-18s actual: %-12s threshold: %s\n", status, gate.Name, gate.Actual, gate.Threshold)
+	}
+}
+
+// describeFailedGates 列出未通过的门限及其实际值，如 "p99 response time 312ms (threshold <= 250ms)"
+func describeFailedGates(slo types.SLOReport) string {
+	var parts []string
+	for _, gate := range slo.FailedGates() {
+		parts = append(parts, fmt.Sprintf("%s %s (threshold %s)", gate.Name, gate.Actual, gate.Threshold))
+	}
+	return strings.Join(parts, "; ")
 }
 
 // printUsage 打印使用说明
 func printUsage() {
-	fmt.Println(`
+	fmt.Print(`
 Usage:
   rst [flags]
+  rst merge [flags] report.json report.json...   (see "rst merge -h")
+  rst agent [-listen :8080]
+  rst control -agents host1:8080,host2:8080 [flags]
 
 Required Flags:
   -url string        Target URL
@@ -86,21 +307,145 @@ Basic Flags:
   -n, -requests int        Total number of requests (default 1000)
   -c, -concurrency int     Number of concurrent workers (default 10)
   -d, -duration duration   Test duration (e.g., 30s, 5m)
+  -stop-on string          Combine -d and -n: stop at whichever comes first (first) or after both (both)
+  -successes int           Keep sending until N requests succeed (failures don't count)
+  -max-attempts int        Give up -successes after this many requests (default 10x the goal)
   -method string           HTTP method (default "GET")
+  -fail-fast               Stop on the first failed request and exit non-zero
+  -repeat int              Run N times and report per-run and combined percentiles
+  -exclude-shutdown-canceled
+                           Leave requests canceled when the test stops out of totals and failure rate
+  -rate int                Cap the aggregate request rate across all workers (req/sec, 0 = unlimited)
+  -rate-schedule string    File of "<duration> <rate>" lines stepping the request rate
+  -spike                   Run baseline -> spike -> recovery (see -spike-* flags)
+  -spike-baseline-rate float
+                           Baseline and recovery rate for -spike in req/sec (default 10)
+  -spike-rate float        Peak rate for -spike in req/sec (default 100)
+  -spike-baseline-duration duration
+                           Baseline phase length for -spike (default 30s)
+  -spike-duration duration Spike phase length for -spike (default 10s)
+  -spike-recovery-duration duration
+                           Recovery phase length for -spike (default 30s)
+  -arrival string          Poisson arrivals at a mean rate: poisson:<req/sec>
+  -open-model              Send at the -rate/-arrival times without waiting for responses; -c caps requests in flight
+  -seed int                Random seed for -arrival, -start-jitter and -csv-mode (0 = pick one and print it)
+  -think-time string       Pause after each request: 500ms, or a random 100ms-500ms range
+  -ramp-up duration        Start workers evenly over this window, scaling linearly up to -c
+  -start-jitter duration   Delay each worker's first request by a random amount up to this long
+  -burst int               Release N requests at once, one per worker, and report the burst
+  -interactive             Adjust concurrency and rate from stdin during the run
+  -dashboard               Show a live dashboard refreshed every second (progress lines when not a terminal)
+  -targets-file string     JSON or YAML file with a weighted targets list (same format as the config file)
+  -target-pools            Give each config-file target its own workers, allocated by weight
+  -compare-endpoints string
+                           A/B test two URLs ("urlA,urlB") and report a significance test
+  -compare-split int       Percentage of traffic sent to the first URL (default 50)
+
+CRUD Flags:
+  -crud                    POST to -url, then run the remaining methods against <url>/<id>
+  -crud-methods string     Method sequence per iteration (default "POST,GET,DELETE")
+  -crud-id-path string     JSON path of the created id in the POST response (default "id")
 
 Request Flags:
   -b, -body string         Request body
+  -body-file string        Read the request body from a file
+  -binary                  Send the body as raw bytes, no templating (default Content-Type application/octet-stream)
+  -form string             Multipart form field as key=value, may use CSV columns (repeatable)
+  -form-file string        Multipart file field as field=@path, read once and reused (repeatable)
+  -extract string          Save a JSON response field as a per-worker {{name}} variable, as name=path (repeatable)
+  -graphql string          POST the GraphQL query in this file; responses with errors count as failed
+  -graphql-variables string
+                           JSON object template for the GraphQL variables (e.g., '{"id": "{{user_id}}"}')
   -H, -headers string      Request headers (JSON format)
   -t, -timeout duration    Request timeout (default 30s)
+  -timeout-as string       Count timeouts toward the error rate gate (fail) or only warn (warn) (default "fail")
   -keep-alive              Enable keep-alive connections (default true)
+  -disable-keepalive-reuse New connection and full TLS handshake for every request
+  -follow-redirects        Follow HTTP redirects (default true)
+  -max-redirects int       Max redirects before failing with redirect_loop (default 10)
+  -retry-count int         Retry transport errors and 5xx responses up to N times
+  -retry-on-status string  Only retry these status codes (e.g., 502,503,504)
+  -retry-wait duration     Initial backoff between retries (default 100ms)
+  -retry-max-wait duration
+                           Maximum backoff between retries (default 2s)
+  -max-requests-per-connection int
+                           Reconnect after N requests per worker (0 = unlimited)
+  -idle-timeout duration   Close idle keep-alive connections after this long (default 90s)
+  -max-idle-conns int      Max idle keep-alive connections kept (default 2x concurrency)
+  -max-conns-per-host int  Max connections per host, extra requests wait (0 = unlimited)
+  -keepalive-probe         Report connection health: resets, drops, server-closed keep-alives
+  -pool-stats              Report how often requests waited for a pooled connection
+  -latency-breakdown       Report DNS, connect, TLS and time-to-first-byte latency per request
+  -proxy string            Send requests through an http://, https:// or socks5:// proxy
+  -dns-servers string      Resolve hosts through these DNS servers and report lookup times (e.g., 8.8.8.8:53)
+  -resolve string          Connect to addr for host:port, keeping Host and SNI, as host:port:addr (repeatable)
+  -tls-min-version string  Minimum TLS version: 1.0, 1.1, 1.2, 1.3
+  -tls-max-version string  Maximum TLS version: 1.0, 1.1, 1.2, 1.3
+  -cipher-suites string    Comma-separated TLS 1.0-1.2 cipher suites (Go crypto/tls names)
+  -client-cert string      PEM client certificate for mutual TLS (requires -client-key)
+  -client-key string       PEM private key for -client-cert
+  -ca-cert string          PEM root CA bundle used instead of the system roots
+  -insecure                Skip server certificate verification
+  -min-tls-latency string  Warn when TLS handshakes take this share of latency (e.g., 30%)
+  -basic-auth string       HTTP basic auth credentials as user:pass
+  -bearer string           Bearer token sent in the Authorization header
+  -token-command string    Command whose stdout is used as the bearer token
+  -token-refresh-interval duration
+                           Re-run the token command periodically (e.g., 10m)
+  -cookies                 Keep response cookies in a jar shared by all workers
+  -cookie string           Cookie sent with every request as name=value (repeatable)
+
+Signing Flags:
+  -aws-sigv4 string        Sign with AWS SigV4 as region:service (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY)
+  -hmac string             Sign with HMAC into this header (secret from RST_HMAC_SECRET)
+  -hmac-algorithm string   HMAC hash: sha1, sha256, sha512 (default "sha256")
+  -hmac-template string    String to sign: {method} {path} {query} {timestamp} {body} {body_sha256}
+                           (default "{method}\n{path}\n{timestamp}\n{body}")
+  -hmac-encoding string    Signature encoding: hex, base64 (default "hex")
+  -hmac-timestamp-header string
+                           Header carrying {timestamp} (default "X-Timestamp")
 
 Parameterization Flags:
   -csv string              CSV file for parameterization
+  -csv-mode string         How CSV rows are picked: sequential, random or shuffle-once (default "sequential")
+  -csv-coverage            Report used, unused and reused CSV rows
+  -batch-size int          Send N CSV rows per request as a JSON array of -body elements
+  -body-dir string         Directory of request body files, used in turn
 
 Output Flags:
   -o, -output string       Output file for detailed logs
-  -report string           Report format: console, json, html (default "console")
+  -output-dir string       Directory for all artifacts, supports {timestamp}
+  -report string           Report format: console, json, html, template (default "console")
+  -report-template string  Render the report through a Go text/template file (see docs)
+  -time-unit string        Emit JSON report durations as numbers in ns, us, ms or s
+  -timeseries-out string   Write a per-second two-column CSV for plotting
+  -metrics-addr string     Serve live metrics during the run (/metrics Prometheus, /metrics.json)
+  -timeseries-metric string
+                           Column for -timeseries-out: p50, p90, p99, rps, errors (default "p99")
+  -histogram-out string    Write the latency histogram as an HdrHistogram log (.hlog)
+  -cdf-out string          Write the latency CDF as latency_ms,cumulative_fraction CSV
+  -raw-log string          Stream every request result to this file as JSON lines (can be very large)
+  -warn-slow-start float   Warn when first-3s latency is N times steady state (default 2, 0 = off)
+  -extract-metric string   JSON path of a numeric response field to aggregate
+  -percentiles string      Latency percentiles to report, in order (e.g., 50,95,99,99.9)
+  -trim string             Also report avg/stddev without the fastest and slowest N% (e.g., 1%)
+  -forbid-body-contains string
+                           Fail requests whose body contains this string (repeatable)
+  -redact string           Regexp of extra header/query names to mask with *** in reports (repeatable)
+  -read-body-on string     Read bodies of all, error (status >= 400) or none of the responses (default "all")
   -v, -verbose             Enable verbose logging
+  -log-max-backups int     Max compressed rotated log files to keep (0 = unlimited)
+
+SLO Flags:
+  -fail-threshold float    Fail the run if the error rate exceeds this percent (default 10)
+  -max-avg duration        Fail the run if average response time exceeds this
+  -max-p50 duration        Fail the run if p50 response time exceeds this
+  -max-p90 duration        Fail the run if p90 response time exceeds this
+  -max-p99 duration        Fail the run if p99 response time exceeds this (e.g., 250ms)
+  -sla duration            Report per-second compliance with this latency SLA and the worst second
+
+Distributed Flags:
+  -agents string           Agents for rst control; each runs the full test (e.g., host1:8080,host2:8080)
 
 Other Flags:
   -config string           Config file (JSON or YAML)
@@ -122,5 +467,8 @@ Examples:
 
   # Save JSON report
   rst -url https://api.example.com/users -n 1000 -c 10 -o results.json -report json
+
+  # Collect all artifacts under a timestamped directory
+  rst -url https://api.example.com/users -n 1000 -c 10 -report json -output-dir ./results/{timestamp}
 `)
 }
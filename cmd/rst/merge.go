@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/budyaya/resty-stress-tester/internal/reporter"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// runMerge 合并多台机器生成的 JSON 报告，按一次运行输出汇总报告并检查 SLO 门限
+func runMerge(args []string) types.ExitStatus {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	var output, format string
+	fs.StringVar(&output, "o", "", "Output file for the combined report (shorthand)")
+	fs.StringVar(&output, "output", "", "Output file for the combined report")
+	fs.StringVar(&format, "report", "json", "Combined report format: json, html")
+	fs.Usage = printMergeUsage
+
+	// 允许标志出现在报告文件之后，如 rst merge a.json b.json -o combined.json
+	var files []string
+	for {
+		if err := fs.Parse(args); err == flag.ErrHelp {
+			return types.ExitStatus{Reason: types.ExitReasonOK, Code: 0}
+		} else if err != nil {
+			return types.ExitStatus{Reason: types.ExitReasonConfigError, Code: 1}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		files = append(files, args[0])
+		args = args[1:]
+	}
+
+	if len(files) < 2 {
+		fmt.Printf("Error: merge needs at least two reports\n")
+		printMergeUsage()
+		return types.ExitStatus{Reason: types.ExitReasonConfigError, Code: 1}
+	}
+	if format != "json" && format != "html" {
+		fmt.Printf("Error: invalid report format %q for merge (must be json or html)\n", format)
+		return types.ExitStatus{Reason: types.ExitReasonConfigError, Code: 1}
+	}
+
+	cfg, result, err := reporter.MergeReports(files)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return types.ExitStatus{Reason: types.ExitReasonConfigError, Code: 1}
+	}
+	cfg.OutputFile = output
+	cfg.ReportFormat = format
+
+	fmt.Printf("Merged %d reports\n", len(files))
+	r := reporter.NewReporter(cfg)
+	r.ConsoleReport(result)
+
+	if output != "" {
+		if err := r.GenerateReport(result); err != nil {
+			fmt.Printf("Error generating report: %v\n", err)
+		} else {
+			fmt.Printf("Report saved to: %s\n", output)
+		}
+	}
+
+	slo := result.EvaluateSLOs(cfg.SLOThresholds())
+	printSLOReport(slo)
+	return result.ExitStatus(slo)
+}
+
+// printMergeUsage 打印 merge 子命令的使用说明
+func printMergeUsage() {
+	fmt.Print(`
+Usage:
+  rst merge [flags] report.json report.json...
+
+Combines JSON reports (-report json) from several machines into one report,
+as if they were a single run: counters and status/error counts are summed and
+percentiles are recomputed from the merged samples.
+
+Flags:
+  -o, -output string       Output file for the combined report
+  -report string           Combined report format: json, html (default "json")
+`)
+}
@@ -3,9 +3,12 @@ module github.com/budyaya/resty-stress-tester
 go 1.25.1
 
 require (
+	github.com/HdrHistogram/hdrhistogram-go v1.1.2
 	github.com/go-resty/resty/v2 v2.16.5
 	github.com/spf13/viper v1.21.0
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/term v0.28.0
+	golang.org/x/time v0.6.0
 )
 
 require (
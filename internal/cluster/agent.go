@@ -0,0 +1,188 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/internal/reporter"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// Agent 接收 controller 下发的配置并执行压测，同一时间只执行一个压测
+type Agent struct {
+	mu     sync.Mutex
+	state  string
+	tester *engine.StressEngine
+	cfg    *config.Config
+	report []byte
+	err    string
+	out    io.Writer
+}
+
+// NewAgent 创建 agent
+func NewAgent() *Agent {
+	return &Agent{
+		state: stateIdle,
+		out:   os.Stdout,
+	}
+}
+
+// SetOutput 设置 agent 状态信息的输出
+func (a *Agent) SetOutput(w io.Writer) {
+	a.out = w
+}
+
+// Handler 返回 agent 的 HTTP 接口
+func (a *Agent) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+configPath, a.handleConfig)
+	mux.HandleFunc("POST "+startPath, a.handleStart)
+	mux.HandleFunc("GET "+resultPath, a.handleResult)
+	return mux
+}
+
+// ListenAndServe 在指定地址上提供 agent 接口
+func (a *Agent) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, a.Handler())
+}
+
+// handleConfig 校验配置并创建压测引擎，替换之前尚未启动的配置
+func (a *Agent) handleConfig(w http.ResponseWriter, req *http.Request) {
+	sc := &types.StressConfig{}
+	if err := json.NewDecoder(req.Body).Decode(sc); err != nil {
+		writeStatus(w, http.StatusBadRequest, agentStatus{Error: fmt.Sprintf("invalid config: %v", err)})
+		return
+	}
+
+	// agent 不执行 controller 指定的命令，也不写入 controller 指定的路径或监听指定的地址，结果通过 /result 返回
+	if sc.TokenCommand != "" {
+		writeStatus(w, http.StatusBadRequest, agentStatus{Error: "token-command is not allowed on agents"})
+		return
+	}
+	sc.Agents = ""
+	sc.OutputFile = ""
+	sc.OutputDir = ""
+	sc.LogFile = ""
+	sc.TimeSeriesOut = ""
+	sc.TimeSeriesMetric = ""
+	sc.HistogramOut = ""
+	sc.CDFOut = ""
+	sc.RawLog = ""
+	sc.MetricsAddr = ""
+	sc.TimeUnit = ""
+	sc.ReportTemplate = ""
+	sc.ReportFormat = "json"
+
+	cfg := &config.Config{StressConfig: sc}
+	if err := cfg.Validate(); err != nil {
+		writeStatus(w, http.StatusBadRequest, agentStatus{Error: err.Error()})
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.state == stateRunning {
+		writeStatus(w, http.StatusConflict, agentStatus{Error: "a test is already running"})
+		return
+	}
+
+	tester, err := engine.NewStressEngine(cfg)
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, agentStatus{Error: err.Error()})
+		return
+	}
+	if a.tester != nil && a.state == stateReady {
+		a.tester.Cleanup()
+	}
+
+	a.tester = tester
+	a.cfg = cfg
+	a.report = nil
+	a.err = ""
+	a.state = stateReady
+	fmt.Fprintf(a.out, "Received config: %s\n", cfg.GetTestDescription())
+	writeStatus(w, http.StatusOK, agentStatus{Status: a.state})
+}
+
+// handleStart 在指定时间启动已就绪的压测
+func (a *Agent) handleStart(w http.ResponseWriter, req *http.Request) {
+	var start startRequest
+	if err := json.NewDecoder(req.Body).Decode(&start); err != nil {
+		writeStatus(w, http.StatusBadRequest, agentStatus{Error: fmt.Sprintf("invalid start request: %v", err)})
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.state != stateReady {
+		writeStatus(w, http.StatusConflict, agentStatus{Error: fmt.Sprintf("cannot start while %s; POST %s first", a.state, configPath)})
+		return
+	}
+
+	a.state = stateRunning
+	fmt.Fprintf(a.out, "Starting at %s\n", start.StartAt.Format(time.RFC3339Nano))
+	go a.run(a.tester, a.cfg, start.StartAt)
+	writeStatus(w, http.StatusAccepted, agentStatus{Status: a.state})
+}
+
+// run 等待到启动时间后执行压测，并保存 JSON 报告
+func (a *Agent) run(tester *engine.StressEngine, cfg *config.Config, startAt time.Time) {
+	if wait := time.Until(startAt); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	result := tester.Run()
+	tester.PrintReport()
+	tester.Cleanup()
+
+	var buf bytes.Buffer
+	r := reporter.NewReporter(cfg)
+	r.SetOutput(&buf)
+	err := r.GenerateReport(result)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tester = nil
+	if err != nil {
+		a.state = stateFailed
+		a.err = fmt.Sprintf("failed to generate report: %v", err)
+		return
+	}
+	a.state = stateDone
+	a.report = buf.Bytes()
+	fmt.Fprintf(a.out, "Finished: %d requests\n", result.TotalRequests)
+}
+
+// handleResult 返回压测报告，压测进行中返回 202
+func (a *Agent) handleResult(w http.ResponseWriter, req *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch a.state {
+	case stateRunning:
+		writeStatus(w, http.StatusAccepted, agentStatus{Status: a.state})
+	case stateDone:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(a.report)
+	case stateFailed:
+		writeStatus(w, http.StatusInternalServerError, agentStatus{Status: a.state, Error: a.err})
+	default:
+		writeStatus(w, http.StatusConflict, agentStatus{Status: a.state, Error: "no test has been started"})
+	}
+}
+
+// writeStatus 写入 JSON 状态响应
+func writeStatus(w http.ResponseWriter, code int, status agentStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
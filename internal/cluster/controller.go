@@ -0,0 +1,164 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/internal/reporter"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/go-resty/resty/v2"
+)
+
+// Controller 向多个 agent 下发相同的配置，同步启动并合并结果。
+// 每个 agent 按配置执行完整的压测，总负载为单机配置的 agent 数量倍
+type Controller struct {
+	agents []string
+	client *resty.Client
+	out    io.Writer
+
+	// StartDelay 启动命令中的开始时间距下发时刻的间隔
+	StartDelay time.Duration
+	// PollInterval 轮询 agent 结果的间隔
+	PollInterval time.Duration
+}
+
+// NewController 创建 controller，agent 地址为 host:port 或完整的 URL
+func NewController(agents []string) *Controller {
+	return &Controller{
+		agents:       agents,
+		client:       resty.New().SetTimeout(10 * time.Second),
+		out:          os.Stdout,
+		StartDelay:   DefaultStartDelay,
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// SetOutput 设置 controller 进度信息的输出
+func (c *Controller) SetOutput(w io.Writer) {
+	c.out = w
+}
+
+// Run 执行分布式压测，返回合并后的配置和结果。
+// 合并后的配置基于 cfg，并发数和请求总数为各 agent 之和；分位数由各 agent 报告中记录全部请求的直方图合并计算，
+// 按各 agent 的请求数加权，不受每份报告只保留 10000 条详细记录的影响
+func (c *Controller) Run(cfg *config.Config) (*config.Config, *types.StressResult, error) {
+	if len(c.agents) == 0 {
+		return nil, nil, fmt.Errorf("no agents specified")
+	}
+
+	// 先向所有 agent 下发配置，全部就绪后再启动，任一 agent 拒绝配置时不启动任何压测
+	for _, agent := range c.agents {
+		if err := c.post(agent, configPath, cfg.StressConfig); err != nil {
+			return nil, nil, fmt.Errorf("agent %s rejected config: %v", agent, err)
+		}
+		fmt.Fprintf(c.out, "Agent %s: ready\n", agent)
+	}
+
+	startAt := time.Now().Add(c.StartDelay)
+	for _, agent := range c.agents {
+		if err := c.post(agent, startPath, startRequest{StartAt: startAt}); err != nil {
+			return nil, nil, fmt.Errorf("failed to start agent %s: %v", agent, err)
+		}
+	}
+	fmt.Fprintf(c.out, "Starting %d agents at %s\n", len(c.agents), startAt.Format("15:04:05.000"))
+
+	reports, err := c.collect()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configs := make([]*config.Config, 0, len(reports))
+	results := make([]*types.StressResult, 0, len(reports))
+	for i, data := range reports {
+		agentCfg, result, err := reporter.ParseJSONReport(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("agent %s: %v", c.agents[i], err)
+		}
+		configs = append(configs, agentCfg)
+		results = append(results, result)
+	}
+
+	merged, result := reporter.CombineReports(configs, results)
+	total := *cfg.StressConfig
+	total.Concurrency = merged.Concurrency
+	total.TotalRequests = merged.TotalRequests
+	return &config.Config{StressConfig: &total}, result, nil
+}
+
+// collect 轮询所有 agent 直到全部完成，返回各 agent 的 JSON 报告
+func (c *Controller) collect() ([][]byte, error) {
+	reports := make([][]byte, len(c.agents))
+	pending := len(c.agents)
+	for pending > 0 {
+		time.Sleep(c.PollInterval)
+		for i, agent := range c.agents {
+			if reports[i] != nil {
+				continue
+			}
+			data, done, err := c.fetchResult(agent)
+			if err != nil {
+				return nil, fmt.Errorf("agent %s: %v", agent, err)
+			}
+			if done {
+				reports[i] = data
+				pending--
+				fmt.Fprintf(c.out, "Agent %s: finished (%d of %d)\n", agent, len(c.agents)-pending, len(c.agents))
+			}
+		}
+	}
+	return reports, nil
+}
+
+// fetchResult 获取 agent 的报告，压测仍在进行时 done 为 false
+func (c *Controller) fetchResult(agent string) ([]byte, bool, error) {
+	resp, err := c.client.R().Get(agentURL(agent, resultPath))
+	if err != nil {
+		return nil, false, err
+	}
+	switch {
+	case resp.StatusCode() == http.StatusAccepted:
+		return nil, false, nil
+	case resp.IsError():
+		return nil, false, agentError(resp)
+	default:
+		return resp.Body(), true, nil
+	}
+}
+
+// post 向 agent 发送 JSON 请求
+func (c *Controller) post(agent, path string, body interface{}) error {
+	resp, err := c.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		Post(agentURL(agent, path))
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return agentError(resp)
+	}
+	return nil
+}
+
+// agentError 从 agent 的错误响应中提取错误信息
+func agentError(resp *resty.Response) error {
+	var status agentStatus
+	if err := json.Unmarshal(resp.Body(), &status); err == nil && status.Error != "" {
+		return fmt.Errorf("%s", status.Error)
+	}
+	return fmt.Errorf("unexpected response: %s", resp.Status())
+}
+
+// agentURL 拼接 agent 接口的 URL，未指定协议时使用 http
+func agentURL(agent, path string) string {
+	if !strings.Contains(agent, "://") {
+		agent = "http://" + agent
+	}
+	return strings.TrimSuffix(agent, "/") + path
+}
@@ -0,0 +1,47 @@
+// Package cluster 实现分布式压测：controller 向多个 agent 下发配置、同步启动并收集结果。
+//
+// agent 提供以下 HTTP 接口：
+//
+//	POST /config  请求体为压测配置（types.StressConfig 的 JSON），agent 校验后准备就绪
+//	POST /start   请求体为 {"start_at": 时间}，agent 在该时间开始压测
+//	GET  /result  压测进行中返回 202，完成后返回 200 及 -report json 格式的报告
+//
+// 出错时返回非 2xx 状态码和 {"error": "..."}。
+package cluster
+
+import "time"
+
+// agent 接口路径
+const (
+	configPath = "/config"
+	startPath  = "/start"
+	resultPath = "/result"
+)
+
+// 默认的协调参数
+const (
+	// DefaultStartDelay 下发启动命令后留给所有 agent 收到命令的时间
+	DefaultStartDelay = 2 * time.Second
+	// DefaultPollInterval 轮询 agent 结果的间隔
+	DefaultPollInterval = time.Second
+)
+
+// startRequest 启动命令，各 agent 在同一时刻开始压测
+type startRequest struct {
+	StartAt time.Time `json:"start_at"`
+}
+
+// agentStatus agent 的状态响应
+type agentStatus struct {
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// agent 状态
+const (
+	stateIdle    = "idle"
+	stateReady   = "ready"
+	stateRunning = "running"
+	stateDone    = "done"
+	stateFailed  = "failed"
+)
@@ -2,11 +2,20 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/budyaya/resty-stress-tester/internal/parser"
 	"github.com/budyaya/resty-stress-tester/pkg/types"
 	"github.com/budyaya/resty-stress-tester/pkg/version"
 	"github.com/spf13/viper"
@@ -16,10 +25,31 @@ import (
 type Config struct {
 	*types.StressConfig
 	configFile string
+	// 是否显式指定了请求总数（命令行或配置文件）
+	requestsSet bool
+}
+
+// stringList 可重复指定的字符串标志
+type stringList []string
+
+// String 实现 flag.Value
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set 实现 flag.Value，每次出现追加一个值
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
 }
 
 // LoadFromFlags 从命令行标志加载配置
 func LoadFromFlags() (*Config, error) {
+	return LoadFromArgs(os.Args[1:])
+}
+
+// LoadFromArgs 从指定的命令行参数加载配置，用于子命令跳过子命令名后复用压测标志
+func LoadFromArgs(args []string) (*Config, error) {
 	cfg := &Config{
 		StressConfig: types.DefaultConfig(),
 	}
@@ -29,25 +59,129 @@ func LoadFromFlags() (*Config, error) {
 	flag.StringVar(&cfg.Method, "method", cfg.Method, "HTTP method (GET, POST, PUT, DELETE, PATCH)")
 	flag.IntVar(&cfg.TotalRequests, "n", cfg.TotalRequests, "Total number of requests (shorthand)")
 	flag.IntVar(&cfg.TotalRequests, "requests", cfg.TotalRequests, "Total number of requests")
+	flag.StringVar(&cfg.StopOn, "stop-on", cfg.StopOn, "Combine -d and -n: stop when the first of them is reached (first) or only after both are (both)")
 	flag.IntVar(&cfg.Concurrency, "c", cfg.Concurrency, "Number of concurrent workers (shorthand)")
 	flag.IntVar(&cfg.Concurrency, "concurrency", cfg.Concurrency, "Number of concurrent workers")
 	flag.DurationVar(&cfg.Duration, "d", cfg.Duration, "Test duration (e.g., 30s, 5m) (shorthand)")
 	flag.DurationVar(&cfg.Duration, "duration", cfg.Duration, "Test duration (e.g., 30s, 5m)")
+	flag.IntVar(&cfg.Successes, "successes", cfg.Successes, "Keep sending until N requests succeed; failures don't count toward the goal")
+	flag.IntVar(&cfg.MaxAttempts, "max-attempts", cfg.MaxAttempts, "Give up -successes after this many requests (default 10x the goal)")
+	flag.IntVar(&cfg.RateLimit, "rate", cfg.RateLimit, "Cap the aggregate request rate across all workers (req/sec, 0 = unlimited)")
+	flag.StringVar(&cfg.RateSchedule, "rate-schedule", cfg.RateSchedule, "File of \"<duration> <rate>\" lines stepping the request rate")
+	flag.StringVar(&cfg.Arrival, "arrival", cfg.Arrival, "Arrival process: poisson:<rate> sends requests with exponentially distributed gaps at the given mean req/sec")
+	flag.BoolVar(&cfg.OpenModel, "open-model", cfg.OpenModel, "Send at the -rate, rate schedule or -arrival times without waiting for earlier responses; -c caps requests in flight")
+	flag.Int64Var(&cfg.Seed, "seed", cfg.Seed, "Random seed for -arrival, -start-jitter and -csv-mode (0 picks one and prints it)")
+	flag.StringVar(&cfg.ThinkTime, "think-time", cfg.ThinkTime, "Pause after each request, fixed (500ms) or uniformly random in a range (100ms-500ms)")
+	flag.DurationVar(&cfg.RampUp, "ramp-up", cfg.RampUp, "Start workers evenly over this window, scaling linearly up to -c")
+	flag.DurationVar(&cfg.StartJitter, "start-jitter", cfg.StartJitter, "Delay each worker's first request by a random amount up to this long")
+	flag.IntVar(&cfg.Burst, "burst", cfg.Burst, "Release N requests at once (one per worker) and report how the server absorbs the burst")
+	flag.BoolVar(&cfg.FailFast, "fail-fast", cfg.FailFast, "Stop the test on the first failed request and exit non-zero")
+	flag.BoolVar(&cfg.ExcludeShutdownCanceled, "exclude-shutdown-canceled", cfg.ExcludeShutdownCanceled, "Leave requests canceled when the test stops out of the totals and failure rate")
+	flag.IntVar(&cfg.Repeat, "repeat", cfg.Repeat, "Run the test N times and report per-run and combined percentiles")
+	flag.StringVar(&cfg.Agents, "agents", cfg.Agents, "Comma-separated agent addresses for rst control (e.g., host1:8080,host2:8080)")
+	flag.BoolVar(&cfg.Interactive, "interactive", cfg.Interactive, "Adjust concurrency and rate from stdin while the test runs")
+	flag.BoolVar(&cfg.Dashboard, "dashboard", cfg.Dashboard, "Show a live dashboard refreshed every second (falls back to progress lines when not a terminal)")
+	flag.BoolVar(&cfg.Spike, "spike", cfg.Spike, "Run a spike test: baseline rate, short high-rate spike, then baseline again")
+	flag.Float64Var(&cfg.SpikeBaselineRate, "spike-baseline-rate", cfg.SpikeBaselineRate, "Baseline request rate (req/sec) for -spike")
+	flag.Float64Var(&cfg.SpikeRate, "spike-rate", cfg.SpikeRate, "Request rate (req/sec) during the spike")
+	flag.DurationVar(&cfg.SpikeBaselineDuration, "spike-baseline-duration", cfg.SpikeBaselineDuration, "Baseline phase duration before the spike")
+	flag.DurationVar(&cfg.SpikeDuration, "spike-duration", cfg.SpikeDuration, "Spike phase duration")
+	flag.DurationVar(&cfg.SpikeRecoveryDuration, "spike-recovery-duration", cfg.SpikeRecoveryDuration, "Recovery phase duration after the spike")
+	flag.StringVar(&cfg.CompareEndpoints, "compare-endpoints", cfg.CompareEndpoints, "A/B test two URLs under identical load: \"urlA,urlB\"")
+	flag.IntVar(&cfg.CompareSplit, "compare-split", cfg.CompareSplit, "Percentage of traffic sent to the first -compare-endpoints URL")
+	flag.StringVar(&cfg.TargetsFile, "targets-file", cfg.TargetsFile, "JSON or YAML file with a targets list of {name, method, url, weight, body} entries")
+	flag.BoolVar(&cfg.TargetPools, "target-pools", cfg.TargetPools, "Give each target its own workers, allocated by weight, instead of sharing workers")
+	flag.BoolVar(&cfg.CRUD, "crud", cfg.CRUD, "Each iteration creates a resource with POST, then runs the remaining -crud-methods against <url>/<id>")
+	flag.StringVar(&cfg.CRUDMethods, "crud-methods", cfg.CRUDMethods, "Comma-separated method sequence for -crud, must start with POST")
+	flag.StringVar(&cfg.CRUDIDPath, "crud-id-path", cfg.CRUDIDPath, "JSON path of the created resource id in the POST response")
 	flag.StringVar(&cfg.Body, "b", cfg.Body, "Request body (shorthand)")
 	flag.StringVar(&cfg.Body, "body", cfg.Body, "Request body")
 	flag.StringVar(&cfg.CSVFile, "csv", cfg.CSVFile, "CSV file for parameterization")
+	flag.IntVar(&cfg.BatchSize, "batch-size", cfg.BatchSize, "Send N CSV rows per request as a JSON array, rendering -body once per row")
+	flag.StringVar(&cfg.CSVMode, "csv-mode", cfg.CSVMode, "How CSV rows are picked: sequential, random or shuffle-once")
+	flag.BoolVar(&cfg.CSVCoverage, "csv-coverage", cfg.CSVCoverage, "Report how many CSV rows were used, unused and reused")
+	flag.StringVar(&cfg.BodyDir, "body-dir", cfg.BodyDir, "Directory of request body files, used in turn")
+	flag.StringVar(&cfg.BodyFile, "body-file", cfg.BodyFile, "Read the request body from this file")
+	flag.BoolVar(&cfg.Binary, "binary", cfg.Binary, "Send the request body as raw bytes without template processing (default Content-Type application/octet-stream)")
+	flag.StringVar(&cfg.GraphQL, "graphql", cfg.GraphQL, "POST the GraphQL query in this file and fail responses whose errors array is not empty")
+	flag.Var((*stringList)(&cfg.FormFields), "form", "Send a multipart/form-data body with this field as key=value; may use CSV columns (repeatable)")
+	flag.Var((*stringList)(&cfg.FormFiles), "form-file", "Upload this file in the multipart/form-data body as field=@path (repeatable)")
+	flag.StringVar(&cfg.GraphQLVars, "graphql-variables", cfg.GraphQLVars, "JSON object template for the GraphQL variables; may use CSV columns (e.g., '{\"id\": \"{{user_id}}\"}')")
 	flag.StringVar(&cfg.OutputFile, "o", cfg.OutputFile, "Output file for detailed logs (shorthand)")
 	flag.StringVar(&cfg.OutputFile, "output", cfg.OutputFile, "Output file for detailed logs")
+	flag.StringVar(&cfg.OutputDir, "output-dir", cfg.OutputDir, "Directory for all output artifacts, supports {timestamp} (e.g., ./results/{timestamp})")
 	flag.DurationVar(&cfg.Timeout, "t", cfg.Timeout, "Request timeout (shorthand)")
 	flag.DurationVar(&cfg.Timeout, "timeout", cfg.Timeout, "Request timeout")
+	flag.StringVar(&cfg.TimeoutAs, "timeout-as", cfg.TimeoutAs, "Count timeouts toward the error rate gate (fail) or only warn about them (warn)")
 	flag.BoolVar(&cfg.KeepAlive, "keep-alive", cfg.KeepAlive, "Enable keep-alive connections")
+	flag.BoolVar(&cfg.DisableKeepAliveReuse, "disable-keepalive-reuse", cfg.DisableKeepAliveReuse, "Open a new connection with a full TLS handshake for every request (no pooling or session resumption)")
+	flag.BoolVar(&cfg.FollowRedirects, "follow-redirects", cfg.FollowRedirects, "Follow HTTP redirects")
+	flag.IntVar(&cfg.MaxRedirects, "max-redirects", cfg.MaxRedirects, "Max redirects to follow before failing with redirect_loop")
+	flag.IntVar(&cfg.RetryCount, "retry-count", cfg.RetryCount, "Retry requests that fail with a transport error or a retryable status up to N times")
+	flag.StringVar(&cfg.RetryOnStatus, "retry-on-status", cfg.RetryOnStatus, "Comma-separated status codes that trigger a retry (default all 5xx, e.g., 502,503,504)")
+	flag.DurationVar(&cfg.RetryWait, "retry-wait", cfg.RetryWait, "Initial backoff between retries (default 100ms)")
+	flag.DurationVar(&cfg.RetryMaxWait, "retry-max-wait", cfg.RetryMaxWait, "Maximum backoff between retries (default 2s)")
+	flag.IntVar(&cfg.MaxRequestsPerConnection, "max-requests-per-connection", cfg.MaxRequestsPerConnection, "Close and reopen a worker's connection after N requests (0 = unlimited)")
+	flag.DurationVar(&cfg.IdleTimeout, "idle-timeout", cfg.IdleTimeout, "How long idle keep-alive connections are kept before closing")
+	flag.IntVar(&cfg.MaxIdleConns, "max-idle-conns", cfg.MaxIdleConns, "Max idle keep-alive connections kept across all hosts (0 = 2x concurrency)")
+	flag.IntVar(&cfg.MaxConnsPerHost, "max-conns-per-host", cfg.MaxConnsPerHost, "Max connections per host including in-use ones; extra requests wait (0 = unlimited)")
+	flag.BoolVar(&cfg.KeepAliveProbe, "keepalive-probe", cfg.KeepAliveProbe, "Trace connections and report resets, drops and server-closed keep-alive connections")
+	flag.BoolVar(&cfg.PoolStats, "pool-stats", cfg.PoolStats, "Report how often and how long requests waited for a connection from the pool")
+	flag.BoolVar(&cfg.LatencyBreakdown, "latency-breakdown", cfg.LatencyBreakdown, "Report where request time goes: DNS lookup, TCP connect, TLS handshake and time to first byte")
+	flag.StringVar(&cfg.Proxy, "proxy", cfg.Proxy, "Send requests through this proxy (http://, https:// or socks5://, e.g., http://proxy.corp:3128)")
+	flag.StringVar(&cfg.DNSServers, "dns-servers", cfg.DNSServers, "Comma-separated DNS servers to resolve hosts with instead of the system resolver (e.g., 8.8.8.8:53)")
+	flag.Var((*stringList)(&cfg.Resolve), "resolve", "Connect to addr for host:port while keeping the Host header and SNI, as host:port:addr (repeatable)")
+	flag.StringVar(&cfg.TLSMinVersion, "tls-min-version", cfg.TLSMinVersion, "Minimum TLS version (1.0, 1.1, 1.2, 1.3)")
+	flag.StringVar(&cfg.TLSMaxVersion, "tls-max-version", cfg.TLSMaxVersion, "Maximum TLS version (1.0, 1.1, 1.2, 1.3)")
+	flag.StringVar(&cfg.MinTLSLatency, "min-tls-latency", cfg.MinTLSLatency, "Warn when TLS handshakes take at least this percent of the average response time (e.g., 30%)")
+	flag.StringVar(&cfg.ClientCert, "client-cert", cfg.ClientCert, "PEM client certificate for mutual TLS (requires -client-key)")
+	flag.StringVar(&cfg.ClientKey, "client-key", cfg.ClientKey, "PEM private key for -client-cert")
+	flag.StringVar(&cfg.CACert, "ca-cert", cfg.CACert, "PEM root CA bundle used to verify the server certificate instead of the system roots")
+	flag.BoolVar(&cfg.Insecure, "insecure", cfg.Insecure, "Skip server certificate verification")
+	flag.StringVar(&cfg.CipherSuites, "cipher-suites", cfg.CipherSuites, "Comma-separated TLS 1.0-1.2 cipher suites (e.g., TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)")
+	flag.StringVar(&cfg.BearerToken, "bearer", cfg.BearerToken, "Bearer token sent in the Authorization header")
+	flag.StringVar(&cfg.TokenCommand, "token-command", cfg.TokenCommand, "Command whose stdout is used as the bearer token")
+	flag.DurationVar(&cfg.TokenRefreshInterval, "token-refresh-interval", cfg.TokenRefreshInterval, "Re-run the token command at this interval (e.g., 10m)")
+	flag.BoolVar(&cfg.Cookies, "cookies", cfg.Cookies, "Keep cookies set by responses in a jar shared by all workers and send them on later requests")
+	flag.Var((*stringList)(&cfg.Cookie), "cookie", "Cookie sent with every request as name=value (repeatable)")
+	flag.StringVar(&cfg.AWSSigV4, "aws-sigv4", cfg.AWSSigV4, "Sign requests with AWS SigV4 as region:service (credentials from AWS_* env)")
+	flag.StringVar(&cfg.HMACHeader, "hmac", cfg.HMACHeader, "Sign requests with HMAC into this header (secret from RST_HMAC_SECRET)")
+	flag.StringVar(&cfg.HMACAlgorithm, "hmac-algorithm", cfg.HMACAlgorithm, "HMAC hash algorithm (sha1, sha256, sha512)")
+	flag.StringVar(&cfg.HMACTemplate, "hmac-template", cfg.HMACTemplate, "HMAC string-to-sign template ({method}, {path}, {query}, {timestamp}, {body}, {body_sha256})")
+	flag.StringVar(&cfg.HMACEncoding, "hmac-encoding", cfg.HMACEncoding, "HMAC signature encoding (hex, base64)")
+	flag.StringVar(&cfg.HMACTimestampHeader, "hmac-timestamp-header", cfg.HMACTimestampHeader, "Header carrying the {timestamp} used in the HMAC")
 	flag.BoolVar(&cfg.Verbose, "v", cfg.Verbose, "Enable verbose logging (shorthand)")
 	flag.BoolVar(&cfg.Verbose, "verbose", cfg.Verbose, "Enable verbose logging")
-	flag.StringVar(&cfg.ReportFormat, "report", cfg.ReportFormat, "Report format (console, json, html)")
+	flag.StringVar(&cfg.ReportFormat, "report", cfg.ReportFormat, "Report format (console, json, html, template)")
+	flag.StringVar(&cfg.ReportTemplate, "report-template", cfg.ReportTemplate, "Render the report through this Go text/template file (implies -report template)")
+	flag.Float64Var(&cfg.WarnSlowStart, "warn-slow-start", cfg.WarnSlowStart, "Warn when first-seconds latency is this many times the steady state (0 = off)")
+	flag.Float64Var(&cfg.FailureThreshold, "fail-threshold", cfg.FailureThreshold, "Fail the run if the error rate exceeds this percent (0 = default 10)")
+	flag.DurationVar(&cfg.MaxAvg, "max-avg", cfg.MaxAvg, "Fail the run if average response time exceeds this (e.g., 80ms)")
+	flag.DurationVar(&cfg.MaxP50, "max-p50", cfg.MaxP50, "Fail the run if p50 response time exceeds this (e.g., 100ms)")
+	flag.DurationVar(&cfg.MaxP90, "max-p90", cfg.MaxP90, "Fail the run if p90 response time exceeds this")
+	flag.DurationVar(&cfg.MaxP99, "max-p99", cfg.MaxP99, "Fail the run if p99 response time exceeds this (e.g., 250ms)")
+	flag.DurationVar(&cfg.LatencySLA, "sla", cfg.LatencySLA, "Latency SLA per request: report the share of each second's requests within it and the worst second (e.g., 200ms)")
+	flag.StringVar(&cfg.TimeUnit, "time-unit", cfg.TimeUnit, "Emit JSON report durations as plain numbers in this unit (ns, us, ms, s)")
+	flag.StringVar(&cfg.MetricsAddr, "metrics-addr", cfg.MetricsAddr, "Serve live metrics over HTTP on this address during the run (e.g., :9090)")
+	flag.StringVar(&cfg.TimeSeriesOut, "timeseries-out", cfg.TimeSeriesOut, "Write a per-second two-column CSV for plotting (e.g., timeseries.csv)")
+	flag.StringVar(&cfg.HistogramOut, "histogram-out", cfg.HistogramOut, "Write the latency histogram as an HdrHistogram log (e.g., latency.hlog)")
+	flag.StringVar(&cfg.RawLog, "raw-log", cfg.RawLog, "Stream every request result to this file as JSON lines (can grow very large)")
+	flag.StringVar(&cfg.CDFOut, "cdf-out", cfg.CDFOut, "Write the latency CDF as latency_ms,cumulative_fraction CSV points (e.g., cdf.csv)")
+	flag.StringVar(&cfg.TimeSeriesMetric, "timeseries-metric", cfg.TimeSeriesMetric, "Column written by -timeseries-out (p50, p90, p99, rps, errors)")
+	flag.Var((*stringList)(&cfg.Extract), "extract", "Store a JSON response field as a per-worker template variable, as name=path (e.g., token=$.data.token; repeatable)")
+	flag.StringVar(&cfg.ExtractMetric, "extract-metric", cfg.ExtractMetric, "JSON path of a numeric response field to aggregate (e.g., data.queue_depth)")
+	flag.StringVar(&cfg.Percentiles, "percentiles", cfg.Percentiles, "Comma-separated latency percentiles to report (e.g., 50,95,99,99.9)")
+	flag.StringVar(&cfg.Trim, "trim", cfg.Trim, "Also report average/stddev excluding this percent of the fastest and slowest responses (e.g., 1%)")
+	flag.StringVar(&cfg.ReadBodyOn, "read-body-on", cfg.ReadBodyOn, "Read response bodies for all, error (status >= 400) or none of the responses; others are drained and discarded")
+	flag.Var((*stringList)(&cfg.ForbidBodyContains), "forbid-body-contains", "Fail requests whose response body contains this string (repeatable)")
+	flag.Var((*stringList)(&cfg.Redact), "redact", "Regexp of extra header or query parameter names to replace with *** in reports (repeatable)")
+	flag.IntVar(&cfg.LogMaxBackups, "log-max-backups", cfg.LogMaxBackups, "Max number of compressed rotated log files to keep (0 = unlimited)")
 
 	var headers string
 	flag.StringVar(&headers, "H", "", "Request headers (JSON format) (shorthand)")
 	flag.StringVar(&headers, "headers", "", "Request headers (JSON format)")
+	var basicAuth string
+	flag.StringVar(&basicAuth, "basic-auth", "", "HTTP basic auth credentials as user:pass")
 	flag.StringVar(&cfg.configFile, "config", "", "Config file (JSON or YAML)")
 
 	// 添加版本标志
@@ -55,7 +189,19 @@ func LoadFromFlags() (*Config, error) {
 	flag.BoolVar(&showVersion, "version", false, "Show version information")
 	flag.BoolVar(&showVersion, "V", false, "Show version information (shorthand)")
 
-	flag.Parse()
+	if err := flag.CommandLine.Parse(args); err != nil {
+		return nil, err
+	}
+
+	methodSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "n" || f.Name == "requests" {
+			cfg.requestsSet = true
+		}
+		if f.Name == "method" {
+			methodSet = true
+		}
+	})
 
 	// 显示版本信息
 	if showVersion {
@@ -71,21 +217,149 @@ func LoadFromFlags() (*Config, error) {
 		}
 	}
 
+	// 从单独的文件加载目标列表
+	if cfg.TargetsFile != "" {
+		if err := cfg.LoadTargetsFile(); err != nil {
+			return nil, fmt.Errorf("failed to load targets file: %v", err)
+		}
+	}
+
+	// 指定时长、速率计划或成功目标而未显式指定请求总数时，不按请求总数运行
+	if !cfg.requestsSet && (cfg.Duration > 0 || cfg.HasRateSchedule() || cfg.Successes > 0) {
+		cfg.TotalRequests = 0
+	}
+
+	// 指定报告模板即使用模板格式
+	if cfg.ReportTemplate != "" && cfg.ReportFormat == "console" {
+		cfg.ReportFormat = ReportFormatTemplate
+	}
+
+	if err := cfg.ApplyCompareEndpoints(); err != nil {
+		return nil, err
+	}
+
+	// GraphQL 查询和 multipart 表单以 POST 请求发送
+	if (cfg.GraphQL != "" || cfg.HasForm()) && !methodSet {
+		cfg.Method = http.MethodPost
+	}
+
+	// 突发模式下每个工作协程只发送一个请求
+	if cfg.Burst > 0 && !cfg.requestsSet {
+		cfg.Concurrency = cfg.Burst
+		cfg.TotalRequests = cfg.Burst
+	}
+
 	// 解析 headers
 	if headers != "" {
-		if err := json.Unmarshal([]byte(headers), &cfg.Headers); err != nil {
-			return nil, fmt.Errorf("error parsing headers: %v", err)
+		parsed, err := ParseHeaders(headers)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Headers = parsed
+	}
+
+	// 解析 basic auth 凭据，密码中可以包含冒号
+	if basicAuth != "" {
+		user, pass, ok := strings.Cut(basicAuth, ":")
+		if !ok || user == "" {
+			return nil, fmt.Errorf("invalid -basic-auth value (expected user:pass)")
 		}
+		cfg.BasicAuthUser = user
+		cfg.BasicAuthPass = pass
+	}
+
+	// 展开 URL、headers 和请求体中的环境变量
+	if err := cfg.ExpandEnv(); err != nil {
+		return nil, err
 	}
 
 	// 验证配置
-	if err := cfg.validate(); err != nil {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 未指定时长时，由速率计划决定测试时长
+	if cfg.HasRateSchedule() && cfg.Duration == 0 {
+		steps, err := cfg.RateSteps()
+		if err != nil {
+			return nil, err
+		}
+		cfg.Duration = types.TotalScheduleDuration(steps)
+	}
+
+	// 准备输出目录
+	if err := cfg.ApplyOutputDir(); err != nil {
 		return nil, err
 	}
 
 	return cfg, nil
 }
 
+// ApplyCompareEndpoints 将 A/B 对比的两个 URL 转换为按 CompareSplit 分配权重的两个目标，
+// 目标使用全局的方法、请求体和 headers
+func (c *Config) ApplyCompareEndpoints() error {
+	if c.CompareEndpoints == "" {
+		return nil
+	}
+	if len(c.Targets) > 0 {
+		return fmt.Errorf("cannot use -compare-endpoints with targets")
+	}
+
+	urls := strings.Split(c.CompareEndpoints, ",")
+	if len(urls) != 2 || strings.TrimSpace(urls[0]) == "" || strings.TrimSpace(urls[1]) == "" {
+		return fmt.Errorf("compare-endpoints requires exactly two URLs separated by a comma")
+	}
+	if c.CompareSplit < 1 || c.CompareSplit > 99 {
+		return fmt.Errorf("compare-split must be between 1 and 99")
+	}
+
+	c.Targets = []types.TargetSpec{
+		{Name: types.CompareTargetA, URL: strings.TrimSpace(urls[0]), Weight: c.CompareSplit},
+		{Name: types.CompareTargetB, URL: strings.TrimSpace(urls[1]), Weight: 100 - c.CompareSplit},
+	}
+	return nil
+}
+
+// ApplyOutputDir 展开输出目录中的 {timestamp} 并创建目录，
+// 未显式指定路径的输出文件使用目录下的默认文件名，显式指定的相对路径也放到目录下
+func (c *Config) ApplyOutputDir() error {
+	if c.OutputDir == "" {
+		return nil
+	}
+
+	c.OutputDir = strings.ReplaceAll(c.OutputDir, "{timestamp}", time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(c.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	// 显式指定的相对路径相对于输出目录，绝对路径保持不变
+	for _, path := range []*string{&c.OutputFile, &c.LogFile, &c.HistogramOut, &c.CDFOut, &c.TimeSeriesOut, &c.RawLog} {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = c.ArtifactPath(*path)
+		}
+	}
+
+	if c.OutputFile == "" && c.ReportFormat == ReportFormatTemplate {
+		// 模板 report.md.tmpl 输出为 report.md
+		c.OutputFile = c.ArtifactPath(strings.TrimSuffix(filepath.Base(c.ReportTemplate), ".tmpl"))
+	} else if c.OutputFile == "" && c.ReportFormat != "console" {
+		c.OutputFile = c.ArtifactPath("report." + c.ReportFormat)
+	}
+	if c.LogFile == "" {
+		c.LogFile = c.ArtifactPath("stress.log")
+	}
+
+	return nil
+}
+
+// ArtifactPath 返回输出目录下指定文件名的路径，未设置输出目录时返回空字符串
+func (c *Config) ArtifactPath(name string) string {
+	if c.OutputDir == "" {
+		return ""
+	}
+	return filepath.Join(c.OutputDir, name)
+}
+
 // loadFromFile 从配置文件加载
 func (c *Config) loadFromFile() error {
 	viper.SetConfigFile(c.configFile)
@@ -94,46 +368,1045 @@ func (c *Config) loadFromFile() error {
 		return err
 	}
 
+	if viper.IsSet("total_requests") {
+		c.requestsSet = true
+	}
+
 	return viper.Unmarshal(c.StressConfig)
 }
 
-// validate 验证配置
-func (c *Config) validate() error {
-	if c.URL == "" {
+// LoadTargetsFile 从 -targets-file 加载目标列表，不能与配置文件中的 targets 同时使用。
+// LoadFromArgs 会自动调用，嵌入使用时需在 Validate 之前调用
+func (c *Config) LoadTargetsFile() error {
+	if len(c.Targets) > 0 {
+		return fmt.Errorf("cannot specify both -targets-file and targets in the config file")
+	}
+
+	v := viper.New()
+	v.SetConfigFile(c.TargetsFile)
+	if err := v.ReadInConfig(); err != nil {
+		return err
+	}
+	if err := v.UnmarshalKey("targets", &c.Targets); err != nil {
+		return err
+	}
+	if len(c.Targets) == 0 {
+		return fmt.Errorf("%s has no targets list", c.TargetsFile)
+	}
+	return nil
+}
+
+// Validate 验证配置，互相矛盾的标志组合直接报错而不是让其中一个静默生效
+func (c *Config) Validate() error {
+	if c.URL == "" && len(c.Targets) == 0 {
 		return fmt.Errorf("URL is required")
 	}
 
+	if err := c.validateTargets(); err != nil {
+		return err
+	}
+
+	if err := c.validateConflicts(); err != nil {
+		return err
+	}
+
 	if c.Concurrency <= 0 {
 		return fmt.Errorf("concurrency must be positive")
 	}
 
-	if c.Duration == 0 && c.TotalRequests <= 0 {
+	if c.Duration == 0 && c.TotalRequests <= 0 && !c.HasRateSchedule() && c.Successes <= 0 {
 		return fmt.Errorf("either duration or total requests must be specified")
 	}
 
-	if c.Duration > 0 && c.TotalRequests > 0 {
-		return fmt.Errorf("cannot specify both duration and total requests")
+	if err := c.validateSuccesses(); err != nil {
+		return err
+	}
+
+	if c.Repeat < 0 {
+		return fmt.Errorf("repeat cannot be negative")
+	}
+
+	if c.StartJitter < 0 {
+		return fmt.Errorf("start jitter cannot be negative")
+	}
+
+	if c.RampUp < 0 {
+		return fmt.Errorf("ramp-up cannot be negative")
+	}
+
+	if c.RampUp > 0 && c.Duration > 0 && c.RampUp >= c.Duration {
+		return fmt.Errorf("-ramp-up must be shorter than the test duration")
+	}
+
+	if c.RateLimit < 0 {
+		return fmt.Errorf("rate cannot be negative")
+	}
+
+	if _, _, err := c.ThinkTimeRange(); err != nil {
+		return err
+	}
+
+	if err := c.validateCRUD(); err != nil {
+		return err
+	}
+
+	if err := c.validateBurst(); err != nil {
+		return err
+	}
+
+	if err := c.validateInteractive(); err != nil {
+		return err
+	}
+
+	if err := c.validateArrival(); err != nil {
+		return err
+	}
+
+	if err := c.validateOpenModel(); err != nil {
+		return err
+	}
+
+	if c.HasRateSchedule() && c.TotalRequests > 0 {
+		return fmt.Errorf("cannot specify both rate schedule and total requests")
+	}
+
+	if c.Spike {
+		if c.RateSchedule != "" {
+			return fmt.Errorf("cannot specify both -spike and -rate-schedule")
+		}
+		if c.SpikeBaselineRate <= 0 || c.SpikeRate <= 0 {
+			return fmt.Errorf("spike rates must be positive")
+		}
+		if c.SpikeBaselineDuration <= 0 || c.SpikeDuration <= 0 || c.SpikeRecoveryDuration <= 0 {
+			return fmt.Errorf("spike phase durations must be positive")
+		}
+	}
+
+	if err := c.validateStopOn(); err != nil {
+		return err
+	}
+
+	if c.CSVCoverage && c.CSVFile == "" {
+		return fmt.Errorf("-csv-coverage requires -csv")
+	}
+
+	if err := c.validateCSVMode(); err != nil {
+		return err
+	}
+
+	if c.BatchSize < 0 {
+		return fmt.Errorf("batch-size cannot be negative")
+	}
+
+	if c.BodyDir != "" && c.Body != "" {
+		return fmt.Errorf("cannot specify both -body and -body-dir")
+	}
+
+	if c.BodyFile != "" && (c.Body != "" || c.BodyDir != "") {
+		return fmt.Errorf("cannot specify -body-file with -body or -body-dir")
+	}
+
+	if _, err := c.TrimPercent(); err != nil {
+		return err
+	}
+
+	if _, err := c.PercentileList(); err != nil {
+		return err
+	}
+
+	for _, forbidden := range c.ForbidBodyContains {
+		if forbidden == "" {
+			return fmt.Errorf("forbid-body-contains cannot be empty")
+		}
+	}
+
+	if err := c.validateReadBodyOn(); err != nil {
+		return err
+	}
+
+	if err := c.validateRedact(); err != nil {
+		return err
+	}
+
+	switch c.TimeoutAs {
+	case "", types.TimeoutAsFail, types.TimeoutAsWarn:
+	default:
+		return fmt.Errorf("invalid timeout-as: %s (expected fail or warn)", c.TimeoutAs)
+	}
+
+	if c.WarnSlowStart < 0 {
+		return fmt.Errorf("warn-slow-start cannot be negative")
+	}
+
+	if c.FailureThreshold < 0 || c.FailureThreshold > 100 {
+		return fmt.Errorf("fail-threshold must be between 0 and 100")
+	}
+
+	if c.MaxAvg < 0 || c.MaxP50 < 0 || c.MaxP90 < 0 || c.MaxP99 < 0 || c.LatencySLA < 0 {
+		return fmt.Errorf("latency thresholds cannot be negative")
+	}
+
+	if c.MaxRedirects < 0 {
+		return fmt.Errorf("max-redirects cannot be negative")
+	}
+
+	if c.RetryCount < 0 {
+		return fmt.Errorf("retry-count cannot be negative")
+	}
+
+	if c.RetryWait < 0 || c.RetryMaxWait < 0 {
+		return fmt.Errorf("retry-wait and retry-max-wait cannot be negative")
+	}
+
+	if c.RetryWait > 0 && c.RetryMaxWait > 0 && c.RetryWait > c.RetryMaxWait {
+		return fmt.Errorf("retry-wait (%v) cannot exceed retry-max-wait (%v)", c.RetryWait, c.RetryMaxWait)
+	}
+
+	if _, err := c.RetryStatuses(); err != nil {
+		return err
+	}
+
+	if _, err := c.InitialCookies(); err != nil {
+		return err
+	}
+
+	if _, err := c.FormFieldList(); err != nil {
+		return err
+	}
+	if _, err := c.FormFileList(); err != nil {
+		return err
+	}
+
+	if _, err := c.Extractors(); err != nil {
+		return err
+	}
+
+	if _, err := c.DNSServerAddrs(); err != nil {
+		return err
+	}
+	if _, err := c.ResolveOverrides(); err != nil {
+		return err
+	}
+
+	if _, err := c.ProxyURL(); err != nil {
+		return err
+	}
+
+	if err := c.validateTLS(); err != nil {
+		return err
+	}
+
+	if c.IdleTimeout < 0 {
+		return fmt.Errorf("idle-timeout cannot be negative")
+	}
+
+	if c.MaxRequestsPerConnection < 0 {
+		return fmt.Errorf("max-requests-per-connection cannot be negative")
+	}
+
+	if c.MaxIdleConns < 0 {
+		return fmt.Errorf("max-idle-conns cannot be negative")
+	}
+
+	if c.MaxConnsPerHost < 0 {
+		return fmt.Errorf("max-conns-per-host cannot be negative")
+	}
+
+	if c.TokenRefreshInterval < 0 {
+		return fmt.Errorf("token-refresh-interval cannot be negative")
+	}
+
+	if c.TokenRefreshInterval > 0 && c.TokenCommand == "" {
+		return fmt.Errorf("token-refresh-interval requires token-command")
+	}
+
+	if err := c.validateAuth(); err != nil {
+		return err
+	}
+
+	if err := c.validateSigning(); err != nil {
+		return err
+	}
+
+	if c.LogMaxBackups < 0 {
+		return fmt.Errorf("log-max-backups cannot be negative")
+	}
+
+	if c.TimeUnit != "" {
+		if _, ok := timeUnits[c.TimeUnit]; !ok {
+			return fmt.Errorf("invalid time unit: %s (expected ns, us, ms or s)", c.TimeUnit)
+		}
+	}
+
+	if c.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddr); err != nil {
+			return fmt.Errorf("invalid metrics-addr %q: %v", c.MetricsAddr, err)
+		}
+	}
+
+	if c.TimeSeriesOut != "" && !validTimeSeriesMetrics[c.TimeSeriesMetric] {
+		return fmt.Errorf("invalid time series metric: %s (expected p50, p90, p99, rps or errors)", c.TimeSeriesMetric)
 	}
 
 	// 验证 HTTP 方法
-	method := strings.ToUpper(c.Method)
-	validMethods := map[string]bool{
-		"GET":     true,
-		"POST":    true,
-		"PUT":     true,
-		"DELETE":  true,
-		"PATCH":   true,
-		"HEAD":    true,
-		"OPTIONS": true,
-	}
-
-	if !validMethods[method] {
+	if !validMethods[strings.ToUpper(c.Method)] {
 		return fmt.Errorf("invalid HTTP method: %s", c.Method)
 	}
 
 	return nil
 }
 
+// ReportFormatTemplate 使用 -report-template 渲染报告的格式名
+const ReportFormatTemplate = "template"
+
+// validMethods 支持的 HTTP 方法
+var validMethods = map[string]bool{
+	"GET":     true,
+	"POST":    true,
+	"PUT":     true,
+	"DELETE":  true,
+	"PATCH":   true,
+	"HEAD":    true,
+	"OPTIONS": true,
+}
+
+// timeUnits 报告支持的时间单位
+var timeUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+}
+
+// validTimeSeriesMetrics 时间序列导出支持的指标
+var validTimeSeriesMetrics = map[string]bool{
+	"p50":    true,
+	"p90":    true,
+	"p99":    true,
+	"rps":    true,
+	"errors": true,
+}
+
+// TimeUnitDuration 返回 -time-unit 对应的时间单位，未设置时返回 0
+func (c *Config) TimeUnitDuration() time.Duration {
+	return timeUnits[c.TimeUnit]
+}
+
+// validateConflicts 检查互相矛盾的标志组合，错误信息指明冲突的两个标志。
+// 只有依赖项的值不为零且不等于默认值时才视为显式指定
+func (c *Config) validateConflicts() error {
+	conflicts := []struct {
+		when bool
+		msg  string
+	}{
+		{c.CompareEndpoints != "" && c.URL != "",
+			"-url cannot be used with -compare-endpoints: requests go only to the two compared URLs"},
+		{c.CompareEndpoints == "" && c.CompareSplit != 0 && c.CompareSplit != types.DefaultCompareSplit,
+			"-compare-split requires -compare-endpoints"},
+		{!c.KeepAlive && c.MaxRequestsPerConnection > 0,
+			"-max-requests-per-connection cannot be used with -keep-alive=false: every request already opens a new connection"},
+		{!c.KeepAlive && c.IdleTimeout > 0 && c.IdleTimeout != types.DefaultIdleTimeout,
+			"-idle-timeout cannot be used with -keep-alive=false: no connections are kept idle"},
+		{!c.KeepAlive && c.MaxIdleConns > 0,
+			"-max-idle-conns cannot be used with -keep-alive=false: no connections are kept idle"},
+		{c.DisableKeepAliveReuse && (c.MaxRequestsPerConnection > 0 || c.MaxIdleConns > 0 ||
+			c.IdleTimeout > 0 && c.IdleTimeout != types.DefaultIdleTimeout),
+			"-disable-keepalive-reuse cannot be used with -max-requests-per-connection, -idle-timeout or -max-idle-conns: connections are never reused"},
+		{!c.FollowRedirects && c.MaxRedirects != 0 && c.MaxRedirects != types.DefaultMaxRedirects,
+			"-max-redirects cannot be used with -follow-redirects=false: redirects are never followed"},
+		{!c.Spike && c.changedSpikeSettings(),
+			"-spike-* settings require -spike"},
+		{!c.CRUD && c.CRUDMethods != "" && c.CRUDMethods != types.DefaultCRUDMethods,
+			"-crud-methods requires -crud"},
+		{!c.CRUD && c.CRUDIDPath != "" && c.CRUDIDPath != types.DefaultCRUDIDPath,
+			"-crud-id-path requires -crud"},
+		{c.TimeSeriesOut == "" && c.TimeSeriesMetric != "" && c.TimeSeriesMetric != types.DefaultTimeSeriesMetric,
+			"-timeseries-metric requires -timeseries-out"},
+		{c.HMACHeader == "" && c.changedHMACSettings(),
+			"-hmac-* settings require -hmac"},
+		{c.StartJitter > 0 && c.Burst > 0,
+			"-start-jitter cannot be used with -burst: burst workers are released at the same moment"},
+		{c.RateLimit > 0 && c.HasRateSchedule(),
+			"-rate cannot be used with -rate-schedule or -spike"},
+		{c.RateLimit > 0 && (c.Arrival != "" || c.Burst > 0 || c.Successes > 0 || c.TargetPools),
+			"-rate cannot be used with -arrival, -burst, -successes or -target-pools"},
+		{c.RateLimit > 0 && c.Interactive,
+			"-rate cannot be used with -interactive: use the r command to set the rate"},
+		{c.RampUp > 0 && (c.Burst > 0 || c.Interactive),
+			"-ramp-up cannot be used with -burst or -interactive"},
+		{c.Interactive && c.Repeat > 1,
+			"-repeat cannot be used with -interactive: the run ends only when you quit"},
+		{c.Dashboard && c.Interactive,
+			"-dashboard cannot be used with -interactive: interactive mode shows its own status line"},
+		{c.ReportTemplate != "" && c.ReportFormat != ReportFormatTemplate,
+			"-report-template cannot be used with -report " + c.ReportFormat},
+		{c.ReportTemplate == "" && c.ReportFormat == ReportFormatTemplate,
+			"-report template requires -report-template"},
+		{c.BatchSize > 0 && c.CSVFile == "",
+			"-batch-size requires -csv: each array element is rendered from a CSV row"},
+		{c.BatchSize > 0 && c.Body == "" && c.BodyFile == "" && !c.targetsHaveBody(),
+			"-batch-size requires -body as the template for each array element"},
+		{c.BatchSize > 0 && c.BodyDir != "",
+			"-batch-size cannot be used with -body-dir"},
+		{c.BatchSize > 0 && c.CRUD,
+			"-batch-size cannot be used with -crud"},
+		{c.BodyFile != "" && c.CRUD,
+			"-body-file cannot be used with -crud: use -body-dir for CRUD request bodies"},
+		{c.Binary && c.Body == "" && c.BodyFile == "" && c.BodyDir == "" && !c.targetsHaveBody(),
+			"-binary requires a request body from -body-file, -body-dir or -body"},
+		{c.Binary && c.BatchSize > 0,
+			"-binary cannot be used with -batch-size: batch bodies are rendered as JSON"},
+		{c.Binary && c.CRUD,
+			"-binary cannot be used with -crud"},
+		{c.GraphQLVars != "" && c.GraphQL == "",
+			"-graphql-variables requires -graphql"},
+		{c.HasForm() && (c.Body != "" || c.BodyFile != "" || c.BodyDir != "" || c.targetsHaveBody() || c.GraphQL != ""),
+			"-form and -form-file cannot be used with -body, -body-file, -body-dir, -graphql or target bodies: the body is built from the form"},
+		{c.HasForm() && (c.BatchSize > 0 || c.Binary || c.CRUD),
+			"-form and -form-file cannot be used with -batch-size, -binary or -crud"},
+		{len(c.Extract) > 0 && (c.CRUD || c.Binary),
+			"-extract cannot be used with -crud or -binary"},
+		{c.GraphQL != "" && (c.Body != "" || c.BodyFile != "" || c.BodyDir != "" || c.targetsHaveBody()),
+			"-graphql cannot be used with -body, -body-file, -body-dir or target bodies: the body is built from the query"},
+		{c.GraphQL != "" && (c.BatchSize > 0 || c.Binary || c.CRUD),
+			"-graphql cannot be used with -batch-size, -binary or -crud"},
+		{c.GraphQL != "" && !strings.EqualFold(c.Method, http.MethodPost),
+			"-graphql sends queries as POST requests and cannot be used with -method " + c.Method},
+		{c.RetryOnStatus != "" && c.RetryCount == 0,
+			"-retry-on-status requires -retry-count"},
+		{(c.RetryWait > 0 || c.RetryMaxWait > 0) && c.RetryCount == 0,
+			"-retry-wait and -retry-max-wait require -retry-count"},
+		{c.Agents != "" && c.Interactive,
+			"-interactive cannot be used with -agents: agents run unattended"},
+		{c.Agents != "" && c.Repeat > 1,
+			"-repeat cannot be used with -agents"},
+		{c.Agents != "" && c.TokenCommand != "",
+			"-token-command cannot be used with -agents: agents do not run commands from the controller"},
+	}
+
+	for _, conflict := range conflicts {
+		if conflict.when {
+			return fmt.Errorf("%s", conflict.msg)
+		}
+	}
+	return nil
+}
+
+// targetsHaveBody 判断是否有目标设置了自己的请求体
+func (c *Config) targetsHaveBody() bool {
+	for _, target := range c.Targets {
+		if target.Body != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// changedSpikeSettings 判断是否显式修改了突刺测试的参数
+func (c *Config) changedSpikeSettings() bool {
+	changed := func(v, def float64) bool { return v != 0 && v != def }
+	return changed(c.SpikeBaselineRate, types.DefaultSpikeBaselineRate) ||
+		changed(c.SpikeRate, types.DefaultSpikeRate) ||
+		changed(float64(c.SpikeBaselineDuration), float64(types.DefaultSpikeBaselineDuration)) ||
+		changed(float64(c.SpikeDuration), float64(types.DefaultSpikeDuration)) ||
+		changed(float64(c.SpikeRecoveryDuration), float64(types.DefaultSpikeRecoveryDuration))
+}
+
+// changedHMACSettings 判断是否显式修改了 HMAC 签名的参数
+func (c *Config) changedHMACSettings() bool {
+	changed := func(v, def string) bool { return v != "" && v != def }
+	return changed(c.HMACAlgorithm, types.DefaultHMACAlgorithm) ||
+		changed(c.HMACTemplate, types.DefaultHMACTemplate) ||
+		changed(c.HMACEncoding, types.DefaultHMACEncoding) ||
+		changed(c.HMACTimestampHeader, types.DefaultHMACTimestampHeader)
+}
+
+// validateTargets 验证多目标配置
+func (c *Config) validateTargets() error {
+	for i, target := range c.Targets {
+		if target.URL == "" {
+			return fmt.Errorf("target %d: URL is required", i+1)
+		}
+		if target.Method != "" && !validMethods[strings.ToUpper(target.Method)] {
+			return fmt.Errorf("target %d: invalid HTTP method: %s", i+1, target.Method)
+		}
+		if target.Weight < 0 {
+			return fmt.Errorf("target %d: weight cannot be negative", i+1)
+		}
+		for _, code := range target.ExpectStatus {
+			if code < 100 || code > 599 {
+				return fmt.Errorf("target %d: invalid expected status code: %d", i+1, code)
+			}
+		}
+	}
+
+	if c.TargetPools {
+		if len(c.Targets) == 0 {
+			return fmt.Errorf("target-pools requires targets")
+		}
+		if c.Concurrency < len(c.Targets) {
+			return fmt.Errorf("target-pools requires concurrency of at least %d (one worker per target)", len(c.Targets))
+		}
+		if c.HasRateSchedule() {
+			return fmt.Errorf("target-pools cannot be used with a rate schedule")
+		}
+	}
+	return nil
+}
+
+// validateBurst 验证突发模式配置
+func (c *Config) validateBurst() error {
+	if c.Burst < 0 {
+		return fmt.Errorf("burst cannot be negative")
+	}
+	if c.Burst == 0 {
+		return nil
+	}
+	if c.requestsSet {
+		return fmt.Errorf("cannot specify both -burst and total requests")
+	}
+	if c.Duration > 0 || c.HasRateSchedule() {
+		return fmt.Errorf("-burst cannot be used with duration or a rate schedule")
+	}
+	if c.TargetPools {
+		return fmt.Errorf("-burst cannot be used with target-pools")
+	}
+	return nil
+}
+
+// validateSuccesses 验证成功目标模式配置，该模式自行决定发送的请求数
+func (c *Config) validateSuccesses() error {
+	if c.Successes < 0 {
+		return fmt.Errorf("successes cannot be negative")
+	}
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("max-attempts cannot be negative")
+	}
+	if c.Successes == 0 {
+		if c.MaxAttempts > 0 {
+			return fmt.Errorf("-max-attempts requires -successes")
+		}
+		return nil
+	}
+	if c.TotalRequests > 0 {
+		return fmt.Errorf("cannot specify both -successes and total requests")
+	}
+	if c.Duration > 0 || c.HasRateSchedule() {
+		return fmt.Errorf("-successes cannot be used with duration or a rate schedule")
+	}
+	if c.Burst > 0 || c.Interactive || c.CRUD || c.TargetPools {
+		return fmt.Errorf("-successes cannot be used with -burst, -interactive, -crud or target-pools")
+	}
+	if c.MaxAttempts > 0 && c.MaxAttempts < c.Successes {
+		return fmt.Errorf("max-attempts (%d) cannot be less than successes (%d)", c.MaxAttempts, c.Successes)
+	}
+	return nil
+}
+
+// AttemptLimit 返回 -successes 模式下的请求数上限
+func (c *Config) AttemptLimit() int {
+	if c.MaxAttempts > 0 {
+		return c.MaxAttempts
+	}
+	return c.Successes * types.DefaultMaxAttemptsFactor
+}
+
+// validateInteractive 验证交互模式配置，交互模式自行控制并发数和速率
+func (c *Config) validateInteractive() error {
+	if !c.Interactive {
+		return nil
+	}
+	if c.HasRateSchedule() {
+		return fmt.Errorf("-interactive cannot be used with a rate schedule")
+	}
+	if c.Burst > 0 || c.TargetPools {
+		return fmt.Errorf("-interactive cannot be used with -burst or -target-pools")
+	}
+	return nil
+}
+
+// validateStopOn 验证 -stop-on：默认时长和请求数互斥，指定 -stop-on 时两者组合使用且都必须指定
+func (c *Config) validateStopOn() error {
+	switch c.StopOn {
+	case "":
+		if c.Duration > 0 && c.TotalRequests > 0 {
+			return fmt.Errorf("cannot specify both duration and total requests (use -stop-on first or -stop-on both to combine them)")
+		}
+		return nil
+	case types.StopOnFirst, types.StopOnBoth:
+	default:
+		return fmt.Errorf("invalid stop-on: %s (expected first or both)", c.StopOn)
+	}
+
+	if c.Duration <= 0 || c.TotalRequests <= 0 {
+		return fmt.Errorf("-stop-on requires both -d and -n")
+	}
+	return nil
+}
+
+// validateCSVMode 验证 -csv-mode，非顺序模式需要 -csv
+func (c *Config) validateCSVMode() error {
+	switch c.CSVMode {
+	case "", types.CSVModeSequential:
+		return nil
+	case types.CSVModeRandom, types.CSVModeShuffleOnce:
+		if c.CSVFile == "" {
+			return fmt.Errorf("-csv-mode %s requires -csv", c.CSVMode)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid csv-mode: %s (expected sequential, random or shuffle-once)", c.CSVMode)
+}
+
+// RandomCSVMode 判断 CSV 行是否按随机方式选取，此时使用 -seed 作为随机数种子
+func (c *Config) RandomCSVMode() bool {
+	return c.CSVMode == types.CSVModeRandom || c.CSVMode == types.CSVModeShuffleOnce
+}
+
+// validateArrival 验证到达过程配置，到达过程自行控制发送节奏
+func (c *Config) validateArrival() error {
+	if c.Arrival == "" {
+		if c.Seed != 0 && c.StartJitter == 0 && !c.RandomCSVMode() {
+			return fmt.Errorf("-seed requires -arrival, -start-jitter or -csv-mode random/shuffle-once")
+		}
+		return nil
+	}
+	if _, err := c.ArrivalRate(); err != nil {
+		return err
+	}
+	if c.HasRateSchedule() {
+		return fmt.Errorf("-arrival cannot be used with a rate schedule")
+	}
+	if c.Burst > 0 || c.Successes > 0 || c.Interactive || c.TargetPools {
+		return fmt.Errorf("-arrival cannot be used with -burst, -successes, -interactive or -target-pools")
+	}
+	return nil
+}
+
+// validateOpenModel 验证开放模型配置：发送时间由速率或到达过程决定，工作协程不能再自行等待
+func (c *Config) validateOpenModel() error {
+	if !c.OpenModel {
+		return nil
+	}
+	if c.RateLimit <= 0 && !c.HasRateSchedule() && c.Arrival == "" {
+		return fmt.Errorf("-open-model requires -rate, -rate-schedule, -spike or -arrival")
+	}
+	if c.Burst > 0 || c.Successes > 0 || c.Interactive || c.TargetPools {
+		return fmt.Errorf("-open-model cannot be used with -burst, -successes, -interactive or -target-pools")
+	}
+	if c.CRUD || c.ThinkTime != "" || c.RampUp > 0 || c.StartJitter > 0 {
+		return fmt.Errorf("-open-model cannot be used with -crud, -think-time, -ramp-up or -start-jitter: requests are sent at their scheduled times")
+	}
+	return nil
+}
+
+// ArrivalRate 解析 -arrival 的平均速率（请求/秒），未设置时返回 0
+func (c *Config) ArrivalRate() (float64, error) {
+	if c.Arrival == "" {
+		return 0, nil
+	}
+
+	kind, value, ok := strings.Cut(strings.TrimSpace(c.Arrival), ":")
+	if !ok || !strings.EqualFold(kind, "poisson") {
+		return 0, fmt.Errorf("invalid arrival: %s (expected poisson:<rate>)", c.Arrival)
+	}
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || rate <= 0 {
+		return 0, fmt.Errorf("invalid arrival rate: %s (expected a positive number of requests per second)", value)
+	}
+	return rate, nil
+}
+
+// ThinkTimeRange 解析 -think-time 的等待时间范围，固定时长时 min 与 max 相等，未设置时都为 0
+func (c *Config) ThinkTimeRange() (min, max time.Duration, err error) {
+	value := strings.TrimSpace(c.ThinkTime)
+	if value == "" {
+		return 0, 0, nil
+	}
+
+	low, high, isRange := strings.Cut(value, "-")
+	min, err = time.ParseDuration(strings.TrimSpace(low))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid think-time: %s (expected a duration like 500ms or a range like 100ms-500ms)", c.ThinkTime)
+	}
+	max = min
+	if isRange {
+		max, err = time.ParseDuration(strings.TrimSpace(high))
+		if err != nil || max < min {
+			return 0, 0, fmt.Errorf("invalid think-time: %s (expected a duration like 500ms or a range like 100ms-500ms)", c.ThinkTime)
+		}
+	}
+	return min, max, nil
+}
+
+// validateReadBodyOn 验证 -read-body-on，需要检查成功响应体的选项要求读取所有响应体
+func (c *Config) validateReadBodyOn() error {
+	switch c.ReadBodyOn {
+	case "", types.ReadBodyAll:
+		return nil
+	case types.ReadBodyError, types.ReadBodyNone:
+	default:
+		return fmt.Errorf("invalid read-body-on: %s (expected all, error or none)", c.ReadBodyOn)
+	}
+
+	if c.ExtractMetric != "" || len(c.Extract) > 0 || len(c.ForbidBodyContains) > 0 || c.CRUD || c.GraphQL != "" {
+		return fmt.Errorf("-read-body-on %s cannot be used with -extract-metric, -extract, -forbid-body-contains, -crud or -graphql: they inspect every response body", c.ReadBodyOn)
+	}
+	if c.RetryCount > 0 {
+		return fmt.Errorf("-read-body-on %s cannot be used with -retry-count: bodies of retried responses would not be drained", c.ReadBodyOn)
+	}
+	for _, target := range c.Targets {
+		if target.ExpectBodyContains != "" {
+			return fmt.Errorf("-read-body-on %s cannot be used with expect_body_contains on target %s", c.ReadBodyOn, target.DisplayName())
+		}
+	}
+	return nil
+}
+
+// ReadsBody 判断是否读取指定状态码的响应体
+func (c *Config) ReadsBody(statusCode int) bool {
+	switch c.ReadBodyOn {
+	case types.ReadBodyError:
+		return statusCode >= 400
+	case types.ReadBodyNone:
+		return false
+	default:
+		return true
+	}
+}
+
+// validateCRUD 验证 CRUD 模式配置
+func (c *Config) validateCRUD() error {
+	if !c.CRUD {
+		return nil
+	}
+	if len(c.Targets) > 0 {
+		return fmt.Errorf("cannot use -crud with multiple targets")
+	}
+	if c.CRUDIDPath == "" {
+		return fmt.Errorf("crud-id-path is required for -crud")
+	}
+
+	methods := c.CRUDSequence()
+	if len(methods) < 2 || methods[0] != "POST" {
+		return fmt.Errorf("crud-methods must start with POST followed by at least one method")
+	}
+	for _, method := range methods {
+		if !validMethods[method] {
+			return fmt.Errorf("invalid HTTP method in crud-methods: %s", method)
+		}
+	}
+	return nil
+}
+
+// CRUDSequence 返回 CRUD 模式下每次迭代依次执行的 HTTP 方法
+func (c *Config) CRUDSequence() []string {
+	var methods []string
+	for _, method := range strings.Split(c.CRUDMethods, ",") {
+		if method = strings.ToUpper(strings.TrimSpace(method)); method != "" {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// HasRateSchedule 检查是否配置了速率计划（计划文件或突刺预设）
+func (c *Config) HasRateSchedule() bool {
+	return c.RateSchedule != "" || c.Spike
+}
+
+// RateSteps 返回速率计划的各个阶段，未配置速率计划时返回 nil
+func (c *Config) RateSteps() ([]types.RateStep, error) {
+	if c.Spike {
+		return types.SpikeSteps(c.SpikeBaselineRate, c.SpikeRate,
+			c.SpikeBaselineDuration, c.SpikeDuration, c.SpikeRecoveryDuration), nil
+	}
+	if c.RateSchedule != "" {
+		return parser.ParseRateSchedule(c.RateSchedule)
+	}
+	return nil, nil
+}
+
+// headersExample 错误信息中给出的 -H 正确写法
+const headersExample = `headers must be a JSON object like '{"Key":"Value"}'`
+
+// ParseHeaders 解析 -H 指定的 JSON 格式 headers，格式错误时指出常见的错误写法
+func ParseHeaders(s string) (map[string]string, error) {
+	trimmed := strings.TrimSpace(s)
+
+	// curl 风格的 "Key: Value"
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		if key, value, ok := strings.Cut(trimmed, ":"); ok && key != "" && !strings.ContainsAny(key, " \t\"'") {
+			return nil, fmt.Errorf("invalid headers: %s; for %q use '{\"%s\":\"%s\"}'", headersExample, trimmed, key, strings.TrimSpace(value))
+		}
+		return nil, fmt.Errorf("invalid headers: %s", headersExample)
+	}
+
+	var headers map[string]string
+	err := json.Unmarshal([]byte(trimmed), &headers)
+	if err == nil {
+		return headers, nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return nil, fmt.Errorf("invalid headers: value of %q is a JSON %s, header values must be strings (e.g. \"%s\":\"1\")", typeErr.Field, typeErr.Value, typeErr.Field)
+	case errors.As(err, &typeErr):
+		return nil, fmt.Errorf("invalid headers: got a JSON %s, %s", typeErr.Value, headersExample)
+	case errors.As(err, &syntaxErr):
+		return nil, fmt.Errorf("invalid headers: %v at offset %d; %s", err, syntaxErr.Offset, headersSyntaxHint(trimmed, int(syntaxErr.Offset)))
+	default:
+		return nil, fmt.Errorf("invalid headers: %v; %s", err, headersExample)
+	}
+}
+
+// headersSyntaxHint 根据出错位置的字符推测常见的错误写法
+func headersSyntaxHint(s string, offset int) string {
+	if offset < 1 || offset > len(s) {
+		return headersExample
+	}
+
+	switch c := s[offset-1]; {
+	case c == '\'':
+		return "use double quotes, not single quotes, around keys and values"
+	case c == '}' && strings.HasSuffix(strings.TrimSpace(s[:offset-1]), ","):
+		return "remove the trailing comma"
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		// 未加引号的键或值，通常是 shell 去掉了参数中的双引号
+		return `keys and values must be double-quoted; wrap the whole argument in single quotes so the shell keeps them: -H '{"Key":"Value"}'`
+	default:
+		return headersExample
+	}
+}
+
+// TrimPercent 解析截尾统计的百分比，支持 "1%" 和 "1" 两种写法，未设置时返回 0
+func (c *Config) TrimPercent() (float64, error) {
+	if c.Trim == "" {
+		return 0, nil
+	}
+
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(c.Trim), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid trim value: %s (expected a percentage such as 1%%)", c.Trim)
+	}
+	if percent < 0 || percent >= 50 {
+		return 0, fmt.Errorf("trim must be between 0%% and 50%%")
+	}
+	return percent, nil
+}
+
+// PercentileList 按给定顺序解析 -percentiles，每个值须在 (0,100] 内，未设置时返回 nil
+func (c *Config) PercentileList() ([]float64, error) {
+	if c.Percentiles == "" {
+		return nil, nil
+	}
+
+	var percentiles []float64
+	for _, field := range strings.Split(c.Percentiles, ",") {
+		percentile, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(field), "p"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid percentile: %q", strings.TrimSpace(field))
+		}
+		if percentile <= 0 || percentile > 100 {
+			return nil, fmt.Errorf("percentile must be in (0,100]: %g", percentile)
+		}
+		percentiles = append(percentiles, percentile)
+	}
+	return percentiles, nil
+}
+
+// FormField multipart 表单中的一个字段
+type FormField struct {
+	Name  string
+	Value string
+}
+
+// HasForm 判断是否以 multipart/form-data 发送请求体
+func (c *Config) HasForm() bool {
+	return len(c.FormFields) > 0 || len(c.FormFiles) > 0
+}
+
+// FormFieldList 解析 -form 指定的表单字段，值中可以包含等号，只按第一个等号分隔
+func (c *Config) FormFieldList() ([]FormField, error) {
+	fields := make([]FormField, 0, len(c.FormFields))
+	for _, value := range c.FormFields {
+		name, val, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid form field: %s (expected key=value)", value)
+		}
+		fields = append(fields, FormField{Name: name, Value: val})
+	}
+	return fields, nil
+}
+
+// FormFileList 解析 -form-file 指定的文件字段，Value 为文件路径，路径前的 @ 可以省略
+func (c *Config) FormFileList() ([]FormField, error) {
+	files := make([]FormField, 0, len(c.FormFiles))
+	for _, value := range c.FormFiles {
+		name, path, ok := strings.Cut(value, "=")
+		path = strings.TrimPrefix(path, "@")
+		if !ok || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid form file: %s (expected field=@path)", value)
+		}
+		files = append(files, FormField{Name: name, Value: path})
+	}
+	return files, nil
+}
+
+// extractNamePattern -extract 的变量名，与 CSV 列名一样以 {{name}} 引用
+var extractNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// Extractor -extract 指定的响应变量：从 JSON 响应的 Path 处取值，保存为模板变量 Name
+type Extractor struct {
+	Name string
+	Path string
+}
+
+// Extractors 解析 -extract 指定的响应变量，路径中可以包含等号，只按第一个等号分隔
+func (c *Config) Extractors() ([]Extractor, error) {
+	extractors := make([]Extractor, 0, len(c.Extract))
+	for _, value := range c.Extract {
+		name, path, ok := strings.Cut(value, "=")
+		if !ok || path == "" || !extractNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid extract: %s (expected name=path, e.g. token=$.data.token)", value)
+		}
+		extractors = append(extractors, Extractor{Name: name, Path: path})
+	}
+	return extractors, nil
+}
+
+// InitialCookies 解析 -cookie 指定的初始 cookie，值中可以包含等号，只按第一个等号分隔
+func (c *Config) InitialCookies() ([]*http.Cookie, error) {
+	cookies := make([]*http.Cookie, 0, len(c.Cookie))
+	for _, value := range c.Cookie {
+		name, val, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid cookie: %s (expected name=value)", value)
+		}
+		cookie := &http.Cookie{Name: name, Value: strings.TrimSpace(val)}
+		if err := cookie.Valid(); err != nil {
+			return nil, fmt.Errorf("invalid cookie: %s (%v)", value, err)
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies, nil
+}
+
+// RetryStatuses 解析触发重试的状态码列表，未设置时返回 nil 表示重试所有 5xx
+func (c *Config) RetryStatuses() ([]int, error) {
+	if c.RetryOnStatus == "" {
+		return nil, nil
+	}
+
+	var statuses []int
+	for _, value := range strings.Split(c.RetryOnStatus, ",") {
+		status, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("invalid retry-on-status: %s (expected comma-separated status codes such as 502,503,504)", c.RetryOnStatus)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// DNSServerAddrs 解析 -dns-servers 的服务器地址，未指定端口时使用 53，未设置时返回 nil
+func (c *Config) DNSServerAddrs() ([]string, error) {
+	if c.DNSServers == "" {
+		return nil, nil
+	}
+
+	var addrs []string
+	for _, value := range strings.Split(c.DNSServers, ",") {
+		value = strings.TrimSpace(value)
+		if net.ParseIP(value) != nil {
+			addrs = append(addrs, net.JoinHostPort(value, "53"))
+			continue
+		}
+		host, port, err := net.SplitHostPort(value)
+		n, _ := strconv.Atoi(port)
+		if err != nil || net.ParseIP(host) == nil || n <= 0 || n > 65535 {
+			return nil, fmt.Errorf("invalid DNS server: %s (expected ip or ip:port, e.g., 8.8.8.8:53)", value)
+		}
+		addrs = append(addrs, value)
+	}
+	return addrs, nil
+}
+
+// ResolveOverrides 解析 -resolve 的地址覆盖，返回 host:port 到 addr:port 的映射，未设置时返回 nil。
+// 与 curl 一致，IPv6 地址可以写在方括号中（如 example.com:443:[::1]），同一 host:port 以最后一项为准
+func (c *Config) ResolveOverrides() (map[string]string, error) {
+	if len(c.Resolve) == 0 {
+		return nil, nil
+	}
+
+	overrides := make(map[string]string, len(c.Resolve))
+	for _, value := range c.Resolve {
+		parts := strings.SplitN(value, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid resolve: %s (expected host:port:addr, e.g., api.example.com:443:10.0.0.5)", value)
+		}
+		host, port := strings.ToLower(parts[0]), parts[1]
+		addr := strings.TrimSuffix(strings.TrimPrefix(parts[2], "["), "]")
+		n, err := strconv.Atoi(port)
+		if host == "" || err != nil || n <= 0 || n > 65535 || net.ParseIP(addr) == nil {
+			return nil, fmt.Errorf("invalid resolve: %s (expected host:port:addr, e.g., api.example.com:443:10.0.0.5)", value)
+		}
+		overrides[net.JoinHostPort(host, port)] = net.JoinHostPort(addr, port)
+	}
+	return overrides, nil
+}
+
+// proxySchemes 支持的代理协议
+var proxySchemes = map[string]bool{"http": true, "https": true, "socks5": true}
+
+// ProxyURL 解析 -proxy 的代理地址，未设置时返回 nil
+func (c *Config) ProxyURL() (*url.URL, error) {
+	if c.Proxy == "" {
+		return nil, nil
+	}
+
+	proxy, err := url.Parse(c.Proxy)
+	if err != nil || !proxySchemes[proxy.Scheme] || proxy.Host == "" {
+		return nil, fmt.Errorf("invalid proxy: %s (expected http://, https:// or socks5:// with a host, e.g., http://proxy.corp:3128)", c.Proxy)
+	}
+	return proxy, nil
+}
+
+// SLOThresholds 返回压测通过条件
+func (c *Config) SLOThresholds() types.SLOThresholds {
+	return types.SLOThresholds{
+		MaxErrorRate: c.FailureThreshold,
+		MaxAvg:       c.MaxAvg,
+		MaxP50:       c.MaxP50,
+		MaxP90:       c.MaxP90,
+		MaxP99:       c.MaxP99,
+	}
+}
+
+// Runs 返回压测的运行次数，未设置 -repeat 时运行一次
+func (c *Config) Runs() int {
+	return max(c.Repeat, 1)
+}
+
+// SavesReport 判断是否需要在控制台报告之外生成报告：指定了输出文件，或使用模板（未指定输出文件时输出到控制台）
+func (c *Config) SavesReport() bool {
+	return c.ReportFormat != "console" && (c.OutputFile != "" || c.ReportFormat == ReportFormatTemplate)
+}
+
+// AgentAddresses 返回 -agents 中的 agent 地址列表
+func (c *Config) AgentAddresses() []string {
+	var agents []string
+	for _, agent := range strings.Split(c.Agents, ",") {
+		if agent = strings.TrimSpace(agent); agent != "" {
+			agents = append(agents, agent)
+		}
+	}
+	return agents
+}
+
 // IsDurationBased 检查是否基于时长测试
 func (c *Config) IsDurationBased() bool {
 	return c.Duration > 0
@@ -141,10 +1414,85 @@ func (c *Config) IsDurationBased() bool {
 
 // GetTestDescription 获取测试描述
 func (c *Config) GetTestDescription() string {
+	if c.StopOn == types.StopOnFirst {
+		return fmt.Sprintf("%s for %v or %d requests, whichever comes first, with %d concurrent workers",
+			c.Method, c.Duration, c.TotalRequests, c.Concurrency)
+	}
+	if c.StopOn == types.StopOnBoth {
+		return fmt.Sprintf("%s for at least %v and %d requests with %d concurrent workers",
+			c.Method, c.Duration, c.TotalRequests, c.Concurrency)
+	}
 	if c.IsDurationBased() {
 		return fmt.Sprintf("%s for %v with %d concurrent workers",
 			c.Method, c.Duration, c.Concurrency)
 	}
+	if c.Successes > 0 {
+		return fmt.Sprintf("%s until %d successes with %d concurrent workers",
+			c.Method, c.Successes, c.Concurrency)
+	}
 	return fmt.Sprintf("%s %d requests with %d concurrent workers",
 		c.Method, c.TotalRequests, c.Concurrency)
 }
+
+// validateAuth 校验 basic auth 和 Bearer 令牌配置，两者都写入 Authorization 请求头，
+// 因此互斥，也不能与令牌命令或 SigV4 签名同时使用
+func (c *Config) validateAuth() error {
+	basic := c.BasicAuthUser != "" || c.BasicAuthPass != ""
+	if !basic && c.BearerToken == "" {
+		return nil
+	}
+
+	if basic && c.BasicAuthUser == "" {
+		return fmt.Errorf("basic auth requires a user name")
+	}
+	if basic && c.BearerToken != "" {
+		return fmt.Errorf("-basic-auth and -bearer cannot be used together")
+	}
+	if c.TokenCommand != "" {
+		return fmt.Errorf("-basic-auth and -bearer cannot be used with -token-command")
+	}
+	if c.AWSSigV4 != "" {
+		return fmt.Errorf("-basic-auth and -bearer cannot be used with -aws-sigv4")
+	}
+	return nil
+}
+
+// validateSigning 校验请求签名配置，签名会写入 Authorization 请求头，因此不能与令牌命令同时使用
+func (c *Config) validateSigning() error {
+	if c.AWSSigV4 == "" && c.HMACHeader == "" {
+		return nil
+	}
+
+	if c.AWSSigV4 != "" && c.HMACHeader != "" {
+		return fmt.Errorf("aws-sigv4 and hmac cannot be used together")
+	}
+
+	if c.AWSSigV4 != "" {
+		if c.TokenCommand != "" {
+			return fmt.Errorf("aws-sigv4 cannot be used with token-command")
+		}
+		region, service, ok := strings.Cut(c.AWSSigV4, ":")
+		if !ok || region == "" || service == "" {
+			return fmt.Errorf("invalid aws-sigv4 value %q (expected region:service)", c.AWSSigV4)
+		}
+		return nil
+	}
+
+	switch strings.ToLower(c.HMACAlgorithm) {
+	case "sha1", "sha256", "sha512":
+	default:
+		return fmt.Errorf("invalid hmac algorithm: %s (expected sha1, sha256 or sha512)", c.HMACAlgorithm)
+	}
+
+	switch strings.ToLower(c.HMACEncoding) {
+	case "hex", "base64":
+	default:
+		return fmt.Errorf("invalid hmac encoding: %s (expected hex or base64)", c.HMACEncoding)
+	}
+
+	if c.HMACTemplate == "" {
+		return fmt.Errorf("hmac-template cannot be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// envVarPattern 匹配 ${ENV_VAR} 形式的环境变量引用，不处理 $ENV_VAR，以免误伤请求体中的 $ 字符
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandEnv 在启动时展开 URL、headers 和请求体（包括多目标模式下各目标的对应字段）中的 ${ENV_VAR}，
+// 引用了未设置的环境变量时报错，而不是发送空字符串
+func (c *Config) ExpandEnv() error {
+	var err error
+	expand := func(field, s string) string {
+		if err != nil {
+			return s
+		}
+		var expanded string
+		expanded, err = expandEnv(s)
+		if err != nil {
+			err = fmt.Errorf("%s: %v", field, err)
+		}
+		return expanded
+	}
+
+	c.URL = expand("url", c.URL)
+	c.Body = expand("body", c.Body)
+	c.Headers = expandHeaders("header", c.Headers, expand)
+	for i := range c.Targets {
+		t := &c.Targets[i]
+		field := fmt.Sprintf("target %s", t.DisplayName())
+		t.URL = expand(field+" url", t.URL)
+		t.Body = expand(field+" body", t.Body)
+		t.Headers = expandHeaders(field+" header", t.Headers, expand)
+	}
+	return err
+}
+
+// expandHeaders 展开 headers 的值，按名称顺序处理以保证报错信息稳定
+func expandHeaders(field string, headers map[string]string, expand func(field, s string) string) map[string]string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		headers[name] = expand(fmt.Sprintf("%s %s", field, name), headers[name])
+	}
+	return headers
+}
+
+// expandEnv 展开字符串中的 ${ENV_VAR}，已设置但为空的变量展开为空字符串
+func expandEnv(s string) (string, error) {
+	var missing string
+	expanded := envVarPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envVarPattern.FindStringSubmatch(ref)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			if missing == "" {
+				missing = name
+			}
+			return ref
+		}
+		return value
+	})
+	if missing != "" {
+		return s, fmt.Errorf("environment variable %s is not set", missing)
+	}
+	return expanded, nil
+}
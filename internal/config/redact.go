@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// defaultRedactPattern 默认视为敏感信息的请求头和查询参数名
+const defaultRedactPattern = `(?i)auth|token|secret|passw|api[-_]?key|cookie|session|signature`
+
+// redactedValue 替换敏感信息的占位符
+const redactedValue = "***"
+
+// validateRedact 验证 -redact 指定的正则表达式
+func (c *Config) validateRedact() error {
+	for _, pattern := range c.Redact {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid redact pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Redacted 返回用于写入报告的配置副本：名称匹配敏感模式（默认模式及 -redact 指定的模式）的请求头和
+// 查询参数、Bearer/Basic 凭据、-basic-auth 密码、-bearer 令牌、-cookie 的值以及 URL 和代理地址中的用户信息都被替换为 ***，原配置不受影响
+func (c *Config) Redacted() *Config {
+	r := c.redactor()
+
+	redacted := *c.StressConfig
+	redacted.URL = r.url(c.URL)
+	if c.Proxy != "" {
+		redacted.Proxy = r.url(c.Proxy)
+	}
+	redacted.Headers = r.headers(c.Headers)
+	if c.BasicAuthPass != "" {
+		redacted.BasicAuthPass = redactedValue
+	}
+	if c.BearerToken != "" {
+		redacted.BearerToken = redactedValue
+	}
+	if c.Cookie != nil {
+		redacted.Cookie = make([]string, len(c.Cookie))
+		for i, cookie := range c.Cookie {
+			name, _, _ := strings.Cut(cookie, "=")
+			redacted.Cookie[i] = name + "=" + redactedValue
+		}
+	}
+	if c.CompareEndpoints != "" {
+		endpoints := strings.Split(c.CompareEndpoints, ",")
+		for i, endpoint := range endpoints {
+			endpoints[i] = r.url(endpoint)
+		}
+		redacted.CompareEndpoints = strings.Join(endpoints, ",")
+	}
+	if c.Targets != nil {
+		redacted.Targets = make([]types.TargetSpec, len(c.Targets))
+		for i, target := range c.Targets {
+			target.URL = r.url(target.URL)
+			target.Headers = r.headers(target.Headers)
+			redacted.Targets[i] = target
+		}
+	}
+
+	return &Config{StressConfig: &redacted, configFile: c.configFile, requestsSet: c.requestsSet}
+}
+
+// URLRedactor 返回按与 Redacted 相同的规则替换 URL 中用户信息和敏感查询参数的函数，
+// 用于报告中每个请求记录的 URL
+func (c *Config) URLRedactor() func(string) string {
+	return c.redactor().url
+}
+
+// redactor 返回使用默认模式及 -redact 指定模式的 redactor
+func (c *Config) redactor() redactor {
+	patterns := []*regexp.Regexp{regexp.MustCompile(defaultRedactPattern)}
+	for _, pattern := range c.Redact {
+		// 已通过配置验证，无效的模式忽略
+		if re, err := regexp.Compile(pattern); err == nil {
+			patterns = append(patterns, re)
+		}
+	}
+	return redactor{patterns: patterns}
+}
+
+// redactor 按名称模式替换敏感信息
+type redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// sensitive 判断请求头或查询参数名是否匹配敏感模式
+func (r redactor) sensitive(name string) bool {
+	for _, re := range r.patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// headers 返回替换敏感值后的请求头副本，任何请求头中的 Bearer/Basic 凭据都会被替换
+func (r redactor) headers(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+
+	redacted := make(map[string]string, len(headers))
+	for key, value := range headers {
+		if r.sensitive(key) {
+			value = redactedValue
+		} else if scheme, _, ok := strings.Cut(value, " "); ok && (strings.EqualFold(scheme, "Bearer") || strings.EqualFold(scheme, "Basic")) {
+			value = scheme + " " + redactedValue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// url 替换 URL 中的用户信息和敏感查询参数。按字符串处理而不重新编码，保留其余部分原样（包括模板变量）
+func (r redactor) url(raw string) string {
+	rest, fragment, hasFragment := strings.Cut(raw, "#")
+	rest, query, hasQuery := strings.Cut(rest, "?")
+
+	if scheme, remainder, ok := strings.Cut(rest, "://"); ok {
+		authority, path := remainder, ""
+		if i := strings.Index(remainder, "/"); i >= 0 {
+			authority, path = remainder[:i], remainder[i:]
+		}
+		if i := strings.LastIndex(authority, "@"); i >= 0 {
+			// 只有用户名时通常是放在用户名位置的令牌
+			userinfo := redactedValue
+			if username, _, ok := strings.Cut(authority[:i], ":"); ok {
+				userinfo = username + ":" + redactedValue
+			}
+			authority = userinfo + authority[i:]
+		}
+		rest = scheme + "://" + authority + path
+	}
+
+	if hasQuery {
+		params := strings.Split(query, "&")
+		for i, param := range params {
+			name, _, ok := strings.Cut(param, "=")
+			if !ok {
+				continue
+			}
+			if unescaped, err := url.QueryUnescape(name); err == nil {
+				name = unescaped
+			}
+			if r.sensitive(name) {
+				params[i] = param[:strings.Index(param, "=")+1] + redactedValue
+			}
+		}
+		rest += "?" + strings.Join(params, "&")
+	}
+
+	if hasFragment {
+		rest += "#" + fragment
+	}
+	return rest
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tlsVersions 支持的 TLS 版本名称
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSVersions 解析 TLS 最低和最高版本，未设置的一端返回 0 表示使用 Go 的默认值
+func (c *Config) TLSVersions() (uint16, uint16, error) {
+	minVersion, err := parseTLSVersion("tls-min-version", c.TLSMinVersion)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxVersion, err := parseTLSVersion("tls-max-version", c.TLSMaxVersion)
+	if err != nil {
+		return 0, 0, err
+	}
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return 0, 0, fmt.Errorf("-tls-min-version %s is higher than -tls-max-version %s", c.TLSMinVersion, c.TLSMaxVersion)
+	}
+	return minVersion, maxVersion, nil
+}
+
+// parseTLSVersion 解析 "1.2" 或 "TLS1.2" 形式的版本名称
+func parseTLSVersion(flag, name string) (uint16, error) {
+	if name == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(name)), "TLS")]
+	if !ok {
+		return 0, fmt.Errorf("invalid %s: %s (expected 1.0, 1.1, 1.2 or 1.3)", flag, name)
+	}
+	return version, nil
+}
+
+// CipherSuiteIDs 解析逗号分隔的密码套件名称，名称与 Go crypto/tls 中的常量名一致。
+// TLS 1.3 的密码套件不可配置，指定时返回错误
+func (c *Config) CipherSuiteIDs() ([]uint16, error) {
+	if c.CipherSuites == "" {
+		return nil, nil
+	}
+
+	suites := make(map[string]*tls.CipherSuite)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		suites[suite.Name] = suite
+	}
+
+	var ids []uint16
+	for _, name := range strings.Split(c.CipherSuites, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		suite, ok := suites[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite: %s", name)
+		}
+		if len(suite.SupportedVersions) == 1 && suite.SupportedVersions[0] == tls.VersionTLS13 {
+			return nil, fmt.Errorf("cipher suite %s is TLS 1.3 only and cannot be configured", name)
+		}
+		ids = append(ids, suite.ID)
+	}
+	return ids, nil
+}
+
+// validateTLS 验证 TLS 配置
+func (c *Config) validateTLS() error {
+	minVersion, _, err := c.TLSVersions()
+	if err != nil {
+		return err
+	}
+	if _, err := c.CipherSuiteIDs(); err != nil {
+		return err
+	}
+	if c.CipherSuites != "" && minVersion == tls.VersionTLS13 {
+		return fmt.Errorf("-cipher-suites cannot be used with -tls-min-version 1.3: TLS 1.3 cipher suites are not configurable")
+	}
+	if _, err := c.TLSLatencyPercent(); err != nil {
+		return err
+	}
+	if (c.ClientCert == "") != (c.ClientKey == "") {
+		return fmt.Errorf("-client-cert and -client-key must be provided together")
+	}
+	if c.Insecure && c.CACert != "" {
+		return fmt.Errorf("-insecure cannot be used with -ca-cert: server certificates are not verified")
+	}
+	return nil
+}
+
+// TLSLatencyPercent 解析 TLS 握手耗时提示的百分比阈值，支持 "30%" 和 "30" 两种写法，未设置时返回 0
+func (c *Config) TLSLatencyPercent() (float64, error) {
+	if c.MinTLSLatency == "" {
+		return 0, nil
+	}
+
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(c.MinTLSLatency), "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid min-tls-latency value: %s (expected a percentage such as 30%%)", c.MinTLSLatency)
+	}
+	if percent <= 0 || percent > 100 {
+		return 0, fmt.Errorf("min-tls-latency must be greater than 0%% and at most 100%%")
+	}
+	return percent, nil
+}
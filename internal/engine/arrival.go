@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// poissonArrivals 生成泊松过程的到达时间：相邻到达的间隔服从均值为 1/rate 的指数分布
+type poissonArrivals struct {
+	rng  *rand.Rand
+	rate float64
+}
+
+// newPoissonArrivals 创建泊松到达过程，相同的种子产生相同的到达间隔序列
+func newPoissonArrivals(rate float64, seed int64) *poissonArrivals {
+	return &poissonArrivals{
+		rng:  rand.New(rand.NewPCG(uint64(seed), uint64(seed))),
+		rate: rate,
+	}
+}
+
+// next 返回到下一次到达的间隔
+func (p *poissonArrivals) next() time.Duration {
+	return time.Duration(p.rng.ExpFloat64() / p.rate * float64(time.Second))
+}
+
+// sendArrivals 按泊松过程发送请求任务。到达时间按绝对时间推进，不受服务端响应快慢影响：
+// 工作协程全部繁忙导致发送延迟时，之后的到达会立即补发，保持计划的平均速率
+func (e *StressEngine) sendArrivals(requests chan<- struct{}, total int) {
+	stop := e.newStopCondition(total)
+	timed, cancel := stop.context(e.ctx)
+	defer cancel()
+
+	next := time.Now()
+	for sent := 0; !stop.reached(sent); sent++ {
+		ctx := stop.waitContext(timed, e.ctx, sent)
+		next = next.Add(e.arrivals.next())
+		if wait := time.Until(next); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case requests <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
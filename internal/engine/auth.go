@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/util"
+	"github.com/go-resty/resty/v2"
+)
+
+// tokenSource 通过外部命令获取并缓存 Bearer 令牌
+type tokenSource struct {
+	command string
+	mu      sync.RWMutex
+	token   string
+}
+
+// newTokenSource 创建令牌源并立即获取一次令牌
+func newTokenSource(command string) (*tokenSource, error) {
+	s := &tokenSource{command: command}
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Token 获取当前令牌
+func (s *tokenSource) Token() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.token
+}
+
+// Refresh 重新执行令牌命令并更新令牌，失败时保留旧令牌
+func (s *tokenSource) Refresh() error {
+	token, err := fetchToken(s.command)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.token = token
+	s.mu.Unlock()
+	return nil
+}
+
+// middleware 返回为每个请求设置当前令牌的 resty 中间件
+func (s *tokenSource) middleware() resty.RequestMiddleware {
+	return func(_ *resty.Client, req *resty.Request) error {
+		req.SetAuthToken(s.Token())
+		return nil
+	}
+}
+
+// refreshLoop 按固定间隔刷新令牌，直到 ctx 结束
+func (s *tokenSource) refreshLoop(ctx context.Context, interval time.Duration, logger *util.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Refresh(); err != nil {
+				logger.Error("Failed to refresh token: %v", err)
+			} else {
+				logger.Debug("Token refreshed")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// fetchToken 执行令牌命令并返回其标准输出作为 Bearer 令牌
+func fetchToken(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg != "" {
+			return "", fmt.Errorf("token command failed: %v: %s", err, msg)
+		}
+		return "", fmt.Errorf("token command failed: %v", err)
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("token command produced no output")
+	}
+
+	return token, nil
+}
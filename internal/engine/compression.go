@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// compressionKey 在请求上下文中保存响应压缩统计的键
+type compressionKey struct{}
+
+// compressionStats 单个请求的响应压缩情况，由 decompressingTransport 填写
+type compressionStats struct {
+	compressed bool
+	wireSize   int64
+	decompress time.Duration
+}
+
+// decompressingTransport 代替 http.Transport 的透明 gzip 解压。先读取完整的压缩响应体再解压，
+// 以便分别统计传输大小和解压耗时。被包装的 Transport 需设置 DisableCompression
+type decompressingTransport struct {
+	next *http.Transport
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *decompressingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// 与 http.Transport 一致，调用方未指定编码且不是 Range 或 HEAD 请求时请求 gzip
+	if req.Header.Get("Accept-Encoding") == "" && req.Header.Get("Range") == "" && req.Method != http.MethodHead {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") || resp.ContentLength == 0 {
+		return resp, err
+	}
+
+	wire, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded []byte
+	start := time.Now()
+	if len(wire) > 0 {
+		reader, err := gzip.NewReader(bytes.NewReader(wire))
+		if err != nil {
+			return nil, err
+		}
+		if decoded, err = io.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	}
+	elapsed := time.Since(start)
+
+	// 响应体已解压，避免 resty 再次解压
+	resp.Body = io.NopCloser(bytes.NewReader(decoded))
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = int64(len(decoded))
+	resp.Uncompressed = true
+
+	if stats, ok := req.Context().Value(compressionKey{}).(*compressionStats); ok && len(wire) > 0 {
+		stats.compressed = true
+		stats.wireSize = int64(len(wire))
+		stats.decompress = elapsed
+	}
+	return resp, nil
+}
+
+// CloseIdleConnections 关闭被包装 Transport 的空闲连接
+func (t *decompressingTransport) CloseIdleConnections() {
+	t.next.CloseIdleConnections()
+}
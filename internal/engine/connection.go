@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// classifyConnectionError 将连接层面的错误归类，非连接错误返回空字符串
+func classifyConnectionError(err error) string {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, syscall.ECONNRESET) || strings.Contains(msg, "connection reset by peer"):
+		return types.ErrorCategoryConnectionReset
+	case strings.Contains(msg, "server closed idle connection"):
+		return types.ErrorCategoryIdleConnClosed
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.EPIPE) ||
+		strings.HasSuffix(msg, ": EOF") || strings.Contains(msg, "broken pipe"):
+		return types.ErrorCategoryConnectionDropped
+	}
+	return ""
+}
+
+// isTLSError 判断请求是否因证书校验失败或 TLS 握手失败而失败，如证书过期、不受信任或与主机名不匹配
+func isTLSError(err error) bool {
+	var (
+		verifyErr *tls.CertificateVerificationError
+		alertErr  tls.AlertError
+		recordErr tls.RecordHeaderError
+	)
+	return errors.As(err, &verifyErr) || errors.As(err, &alertErr) || errors.As(err, &recordErr)
+}
+
+// isTimeout 判断请求是否因超过 -timeout 而失败，压测停止导致的取消需在此之前排除
+func isTimeout(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// trackReconnect 判断本次请求是否因已有的保持连接被关闭而重新建立了连接，
+// 由单连接请求上限主动触发的重连不计入
+func (w *Worker) trackReconnect(result *types.RequestResult) {
+	if !w.config.KeepAliveProbe || !keepAlive(w.config) {
+		return
+	}
+
+	if result.NewConnection && w.connected && !w.closeRequested {
+		result.Reconnect = true
+	}
+	w.connected = w.connected || result.NewConnection
+}
@@ -0,0 +1,28 @@
+package engine
+
+import "context"
+
+// RequestInfo 传给 RequestContextFunc 的当前请求信息
+type RequestInfo struct {
+	Target  string            // 多目标模式下选中的目标名称，否则为空
+	CSVData map[string]string // 当前请求使用的 CSV 行，未使用 CSV 时为 nil
+}
+
+// RequestContextFunc 在每个请求发送前由工作协程调用，返回的上下文作为该请求的上下文，
+// 中间件通过 req.Context() 读取其中的值。返回的上下文必须派生自 ctx，否则压测停止时请求不会被取消
+type RequestContextFunc func(ctx context.Context, info RequestInfo) context.Context
+
+// SetRequestContext 设置每个请求的上下文，用于向自定义中间件传递租户、场景等按请求变化的元数据。
+// 会被所有工作协程并发调用。需在 Run 之前调用
+func (e *StressEngine) SetRequestContext(fn RequestContextFunc) *StressEngine {
+	e.reqContext = fn
+	return e
+}
+
+// requestContext 返回当前请求的上下文，未设置 RequestContextFunc 时返回 ctx
+func (w *Worker) requestContext(ctx context.Context, csvData map[string]string, target string) context.Context {
+	if w.contextFunc == nil {
+		return ctx
+	}
+	return w.contextFunc(ctx, RequestInfo{Target: target, CSVData: csvData})
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/parser"
+)
+
+// makeCRUDIteration 执行一次 CRUD 迭代：先 POST 创建资源，
+// 再从响应中提取资源 ID，对 <url>/<id> 依次执行其余方法
+func (w *Worker) makeCRUDIteration() {
+	csvData := w.nextCSVRow()
+	if !w.waitDelay(csvData) {
+		return
+	}
+	baseURL := strings.TrimRight(w.tmplParser.ProcessURL(w.config.URL, csvData), "/")
+
+	var resourceURL string
+	for i, method := range w.crud {
+		startTime := time.Now()
+		req := w.prepareRequest(csvData, nil)
+
+		// 创建和更新请求携带请求体
+		if (w.config.Body != "" || w.bodyDir != nil) && (method == "POST" || method == "PUT" || method == "PATCH") {
+			bodyTemplate := w.config.Body
+			if w.bodyDir != nil {
+				bodyTemplate = w.bodyDir.Next()
+			}
+			body, err := w.tmplParser.ProcessJSON(bodyTemplate, csvData)
+			if err != nil {
+				w.recordError(startTime, method, fmt.Sprintf("Failed to process body template: %v", err), csvData, nil)
+				return
+			}
+			req.SetBody(body)
+		}
+
+		target := baseURL
+		if i > 0 {
+			target = resourceURL
+		}
+
+		resp, err := w.execute(req, method, target)
+		result := w.newResult(resp, err, time.Since(startTime), method, csvData, nil)
+
+		// 创建成功但无法获取资源 ID 时，后续步骤无法执行，将创建请求记为失败
+		if i == 0 && result.Success {
+			id, idErr := parser.ExtractJSONString(resp.Body(), w.config.CRUDIDPath)
+			if idErr != nil || id == "" {
+				result.Success = false
+				result.Error = fmt.Sprintf("crud: resource id not found at %q in create response", w.config.CRUDIDPath)
+			} else {
+				resourceURL = baseURL + "/" + url.PathEscape(id)
+			}
+		}
+
+		w.addResult(result)
+
+		// 任一步骤失败时放弃本次迭代的剩余步骤
+		if !result.Success {
+			return
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/util"
+)
+
+// dashboardLines 生成 -dashboard 实时面板的内容，done/total 为当前进度，remaining 为按时长测试的剩余时间
+func (e *StressEngine) dashboardLines(now time.Time, done, total int64, remaining time.Duration, instantRPS float64) []string {
+	live := e.result.Snapshot(now)
+	format := util.NewFormatter().FormatDuration
+
+	progress := fmt.Sprintf("Elapsed: %v", live.Elapsed.Round(time.Second))
+	if total > 0 {
+		progress += fmt.Sprintf("  Progress: %d/%d (%.1f%%)", done, total, float64(done)/float64(total)*100)
+	}
+	if remaining > 0 {
+		progress += fmt.Sprintf("  Remaining: %v", remaining.Round(time.Second))
+	}
+
+	var successRate float64
+	if live.TotalRequests > 0 {
+		successRate = float64(live.SuccessfulRequests) / float64(live.TotalRequests) * 100
+	}
+
+	lines := []string{
+		"Live Dashboard",
+		strings.Repeat("-", 60),
+		progress,
+		fmt.Sprintf("Requests:   %d total, %d failed (%.2f%% success)", live.TotalRequests, live.FailedRequests, successRate),
+		fmt.Sprintf("RPS:        %.1f current, %.1f average", instantRPS, live.RequestsPerSecond),
+		fmt.Sprintf("Latency:    P50 %s  P90 %s  P99 %s  Max %s",
+			format(live.P50ResponseTime), format(live.P90ResponseTime), format(live.P99ResponseTime), format(live.MaxResponseTime)),
+		"Status Codes:" + formatCounts(e.result.GetResponseStatusCounts()),
+	}
+	if len(live.ErrorCategories) > 0 {
+		lines = append(lines, "Errors:"+formatErrorCounts(live.ErrorCategories))
+	}
+	return lines
+}
+
+// formatCounts 按状态码升序格式化计数
+func formatCounts(counts map[int]int64) string {
+	if len(counts) == 0 {
+		return " -"
+	}
+	codes := make([]int, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	var b strings.Builder
+	for _, code := range codes {
+		fmt.Fprintf(&b, "  %d: %d", code, counts[code])
+	}
+	return b.String()
+}
+
+// formatErrorCounts 按数量降序格式化错误分类计数
+func formatErrorCounts(counts map[string]int64) string {
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if counts[categories[i]] != counts[categories[j]] {
+			return counts[categories[i]] > counts[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+
+	var b strings.Builder
+	for _, category := range categories {
+		fmt.Fprintf(&b, "  %s: %d", category, counts[category])
+	}
+	return b.String()
+}
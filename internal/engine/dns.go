@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// dnsDialTimeout 连接 DNS 服务器的超时时间
+const dnsDialTimeout = 5 * time.Second
+
+// newResolver 创建只向指定 DNS 服务器查询的解析器，多个服务器按查询轮流使用
+func newResolver(servers []string) *net.Resolver {
+	var next uint32
+	dialer := &net.Dialer{Timeout: dnsDialTimeout}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			server := servers[(atomic.AddUint32(&next, 1)-1)%uint32(len(servers))]
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// dialFunc http.Transport 建立连接使用的函数
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// resolveOverrides 按 -resolve 将到 host:port 的连接改为连接指定地址，其余连接交给 dial。
+// 只替换建立连接的地址，请求的 Host 头和 TLS SNI 仍使用 URL 中的主机名
+func resolveOverrides(overrides map[string]string, dial dialFunc) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err == nil {
+			if target, ok := overrides[net.JoinHostPort(strings.ToLower(host), port)]; ok {
+				addr = target
+			}
+		}
+		return dial(ctx, network, addr)
+	}
+}
+
+// isDNSError 判断请求是否因解析主机名失败而失败
+func isDNSError(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
@@ -0,0 +1,28 @@
+package engine
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Drain 停止发出新请求，等待进行中的请求在 grace 内完成，超时后取消剩余请求。
+// 不阻塞，Run 在所有工作协程退出后照常汇总已收集的结果返回，结果标记为 Interrupted。可重复调用
+func (e *StressEngine) Drain(grace time.Duration) {
+	if !atomic.CompareAndSwapInt32(&e.draining, 0, 1) {
+		return
+	}
+
+	e.logger.Info("Draining in-flight requests (up to %v)...", grace)
+	close(e.drain)
+	time.AfterFunc(grace, e.Stop)
+}
+
+// draining 检查引擎是否已开始排空，排空后工作协程不再领取新的请求任务
+func (w *Worker) draining() bool {
+	select {
+	case <-w.drain:
+		return true
+	default:
+		return false
+	}
+}
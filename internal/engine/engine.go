@@ -3,8 +3,14 @@ package engine
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"os"
+	"regexp"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,6 +21,7 @@ import (
 	"github.com/budyaya/resty-stress-tester/internal/util"
 	"github.com/budyaya/resty-stress-tester/pkg/types"
 	"github.com/go-resty/resty/v2"
+	"golang.org/x/time/rate"
 )
 
 // StressEngine 压测引擎
@@ -22,44 +29,174 @@ type StressEngine struct {
 	config     *config.Config
 	client     *resty.Client
 	csvParser  *parser.CSVParser
+	bodyDir    *parser.BodyDir
+	fileBody   string
+	graphQL    string
+	form       *multipartForm
+	extractors []config.Extractor
 	tmplParser *parser.TemplateParser
+	tokens     *tokenSource
+	limiter    *rate.Limiter
+	rateSteps  []types.RateStep
+	arrivals   *poissonArrivals
+	jitter     *startJitter
+	forbidden  *regexp.Regexp
 	reporter   *reporter.StressReporter
 	logger     *util.Logger
 	result     *types.StressResult
+	metrics    *metricsServer
+	rawLog     *rawLog
 	workers    []*Worker
+	pool       *workerPool
+	input      io.Reader
+	reqContext RequestContextFunc
 	ctx        context.Context
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
 	startTime  time.Time
 	stopped    int32
+	drain      chan struct{}
+	draining   int32
 }
 
-// NewStressEngine 创建压测引擎
-func NewStressEngine(cfg *config.Config) (*StressEngine, error) {
+// NewStressEngine 创建压测引擎，opts 用于注入自定义客户端等可选项
+func NewStressEngine(cfg *config.Config, opts ...Option) (*StressEngine, error) {
+	options := newEngineOptions(opts)
+
 	// 创建 HTTP 客户端
-	client := resty.New()
+	client := options.client
+	if client == nil {
+		client = resty.New()
+	}
 	client.SetTimeout(cfg.Timeout)
 
-	if !cfg.KeepAlive {
+	if !keepAlive(cfg) {
 		client.SetCloseConnection(true)
 	}
 
-	// 配置 TLS
-	client.SetTLSClientConfig(&tls.Config{
-		InsecureSkipVerify: true,
-	})
+	// 配置 TLS，传输层在下方替换，因此 TLS 配置直接设置到新的传输层上
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	// 设置重试策略
-	client.SetRetryCount(0)
+	// 设置重试策略，默认不重试
+	client.SetRetryCount(cfg.RetryCount)
+	if cfg.RetryCount > 0 {
+		statuses, err := cfg.RetryStatuses()
+		if err != nil {
+			return nil, err
+		}
+		client.AddRetryCondition(retryCondition(statuses))
+		if cfg.RetryWait > 0 {
+			client.SetRetryWaitTime(cfg.RetryWait)
+		}
+		if cfg.RetryMaxWait > 0 {
+			client.SetRetryMaxWaitTime(cfg.RetryMaxWait)
+		}
+		// 每次重试的结果已计入报告，不再由 resty 逐条输出日志
+		client.SetLogger(discardLogger{})
+	}
 
-	// 优化连接池
-	client.SetTransport(&http.Transport{
-		MaxIdleConns:        cfg.Concurrency * 2,
-		MaxIdleConnsPerHost: cfg.Concurrency,
-		IdleConnTimeout:     90 * time.Second,
-		DisableCompression:  false,
-		DisableKeepAlives:   !cfg.KeepAlive,
-	})
+	// 固定的认证凭据，与令牌命令互斥
+	if cfg.BasicAuthUser != "" {
+		client.SetBasicAuth(cfg.BasicAuthUser, cfg.BasicAuthPass)
+	}
+	if cfg.BearerToken != "" {
+		client.SetAuthToken(cfg.BearerToken)
+	}
+
+	// resty 默认带有共享的 cookie jar，只在 -cookies 时保留，否则各请求互不影响
+	if !cfg.Cookies {
+		client.SetCookieJar(nil)
+	}
+	cookies, err := cfg.InitialCookies()
+	if err != nil {
+		return nil, err
+	}
+	client.SetCookies(cookies)
+
+	// 通过外部命令获取认证令牌，令牌可能在运行中刷新，因此按请求设置
+	var tokens *tokenSource
+	if cfg.TokenCommand != "" {
+		var err error
+		tokens, err = newTokenSource(cfg.TokenCommand)
+		if err != nil {
+			return nil, err
+		}
+		client.OnBeforeRequest(tokens.middleware())
+	}
+
+	// 请求签名，在 worker 展开模板后按请求计算
+	signer, err := newRequestSigner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if signer != nil {
+		client.OnBeforeRequest(signingMiddleware(signer))
+	}
+
+	// 统计请求体发送进度，用于识别上传中途失败
+	client.SetPreRequestHook(trackUpload)
+
+	// 重定向策略
+	client.SetRedirectPolicy(redirectPolicy(cfg.FollowRedirects, cfg.MaxRedirects))
+
+	// 连接追踪
+	if traceEnabled(cfg) {
+		client.EnableTrace()
+	}
+
+	// 优化连接池，gzip 响应由 decompressingTransport 解压以统计压缩情况
+	transport := &http.Transport{
+		MaxIdleConns:        maxIdleConns(cfg),
+		MaxIdleConnsPerHost: min(cfg.Concurrency, maxIdleConns(cfg)),
+		MaxConnsPerHost:     cfg.MaxConnsPerHost,
+		IdleConnTimeout:     idleTimeout(cfg),
+		DisableCompression:  true,
+		DisableKeepAlives:   !keepAlive(cfg),
+		TLSClientConfig:     tlsConfig,
+	}
+
+	// 不复用连接时不保留空闲连接，MaxIdleConnsPerHost 为 0 时 Go 使用默认值 2，因此设为 -1
+	if cfg.DisableKeepAliveReuse {
+		transport.MaxIdleConnsPerHost = -1
+	}
+
+	// 指定 DNS 服务器时由自定义解析器解析主机名
+	dnsServers, err := cfg.DNSServerAddrs()
+	if err != nil {
+		return nil, err
+	}
+	if len(dnsServers) > 0 {
+		transport.DialContext = (&net.Dialer{Resolver: newResolver(dnsServers)}).DialContext
+	}
+
+	// 按 -resolve 将指定的 host:port 连接到固定地址，未覆盖的主机仍按上面的方式解析
+	overrides, err := cfg.ResolveOverrides()
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) > 0 {
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		transport.DialContext = resolveOverrides(overrides, dial)
+	}
+
+	// 代理，传输层被包装后 resty 的 SetProxy 无法修改，因此直接设置到传输层上
+	proxy, err := cfg.ProxyURL()
+	if err != nil {
+		return nil, err
+	}
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	// 调用方注入的客户端保留自己的传输层
+	if options.client == nil {
+		client.SetTransport(&decompressingTransport{next: transport})
+	}
 
 	// 创建 CSV 解析器
 	var csvParser *parser.CSVParser
@@ -69,39 +206,263 @@ func NewStressEngine(cfg *config.Config) (*StressEngine, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create CSV parser: %v", err)
 		}
+		if cfg.CSVCoverage {
+			csvParser.EnableCoverage()
+		}
+	}
+
+	// 加载请求体目录
+	var bodyDir *parser.BodyDir
+	if cfg.BodyDir != "" {
+		var err error
+		bodyDir, err = parser.NewBodyDir(cfg.BodyDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 加载请求体文件，以字符串保存原始字节，二进制模式下原样发送
+	var fileBody string
+	if cfg.BodyFile != "" {
+		content, err := os.ReadFile(cfg.BodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body file: %v", err)
+		}
+		fileBody = string(content)
+	}
+
+	// 加载 GraphQL 查询
+	var graphQL string
+	if cfg.GraphQL != "" {
+		content, err := os.ReadFile(cfg.GraphQL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GraphQL query: %v", err)
+		}
+		graphQL = string(content)
+	}
+
+	// 读入 multipart 表单的文件
+	form, err := newMultipartForm(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 响应变量，已通过配置验证
+	extractors, _ := cfg.Extractors()
+
+	// 加载速率计划，未指定时长时按计划总时长运行
+	rateSteps, err := cfg.RateSteps()
+	if err != nil {
+		return nil, err
+	}
+
+	var limiter *rate.Limiter
+	if len(rateSteps) > 0 {
+		if cfg.Duration == 0 {
+			cfg.Duration = types.TotalScheduleDuration(rateSteps)
+		}
+		limiter = rate.NewLimiter(rate.Limit(rateSteps[0].Rate), 1)
+	} else if cfg.RateLimit > 0 {
+		limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), 1)
+	}
+
+	// 泊松到达过程、启动延迟和随机的 CSV 行选择，未指定种子时随机选取并在启动信息中输出，便于复现
+	arrivalRate, err := cfg.ArrivalRate()
+	if err != nil {
+		return nil, err
+	}
+	if (arrivalRate > 0 || cfg.StartJitter > 0 || cfg.RandomCSVMode()) && cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	if csvParser != nil {
+		if err := csvParser.SetMode(cfg.CSVMode, cfg.Seed); err != nil {
+			return nil, err
+		}
+	}
+	var arrivals *poissonArrivals
+	if arrivalRate > 0 {
+		arrivals = newPoissonArrivals(arrivalRate, cfg.Seed)
 	}
 
 	// 创建模板解析器
 	tmplParser := parser.NewTemplateParser(csvParser)
 
 	// 创建日志记录器
-	logger, err := util.NewLogger(cfg.Verbose, cfg.LogFile)
+	logger, err := util.NewLoggerWithOptions(util.LoggerOptions{
+		Verbose:    cfg.Verbose,
+		LogFile:    cfg.LogFile,
+		MaxBackups: cfg.LogMaxBackups,
+		Progress:   cfg.Dashboard,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create logger: %v", err)
 	}
 
-	// 创建报告生成器
+	// 创建报告生成器，报告与进度共用控制台输出
 	reporter := reporter.NewReporter(cfg)
+	reporter.SetOutput(logger.Console())
+	if cfg.ReportTemplate != "" {
+		if err := reporter.LoadTemplate(cfg.ReportTemplate); err != nil {
+			logger.Close()
+			return nil, err
+		}
+	}
+
+	// 创建结果统计器
+	result := types.NewStressResult()
+	if cfg.ExtractMetric != "" {
+		result.CustomMetric = types.NewMetricStats(cfg.ExtractMetric)
+	}
+	if cfg.TimeSeriesOut != "" {
+		result.EnableTimeSeries(true)
+	} else if cfg.WarnSlowStart > 0 || cfg.LatencySLA > 0 {
+		// 慢启动检测和 SLA 达标率只需要每秒的计数和平均响应时间
+		result.EnableTimeSeries(false)
+	}
+	result.SetLatencySLA(cfg.LatencySLA)
+	if cfg.CRUD {
+		result.RegisterMethods(cfg.CRUDSequence())
+	}
+	if cfg.Successes > 0 {
+		result.SetSuccessGoal(cfg.Successes)
+	}
+	result.SetRateLimit(cfg.RateLimit)
+	if cfg.KeepAliveProbe {
+		result.EnableConnectionHealth()
+	}
+	if cfg.PoolStats {
+		result.EnablePoolStats()
+	}
+	if cfg.DNSServers != "" {
+		result.EnableDNSStats()
+	}
+	if cfg.LatencyBreakdown {
+		result.EnableLatencyBreakdown()
+	}
+	result.SetExcludeShutdownCanceled(cfg.ExcludeShutdownCanceled)
+	result.SetTimeoutsAsWarnings(cfg.TimeoutAs == types.TimeoutAsWarn)
+	result.SetFailureThreshold(cfg.FailureThreshold)
+	trackTLSHandshakes(tlsConfig, result)
+	trim, err := cfg.TrimPercent()
+	if err != nil {
+		return nil, err
+	}
+	result.SetTrimPercent(trim)
+	percentiles, err := cfg.PercentileList()
+	if err != nil {
+		return nil, err
+	}
+	result.SetPercentiles(percentiles)
+	if cfg.OpenModel {
+		result.EnableOpenModel()
+	} else if arrivals != nil {
+		// 每个工作协程平均每 concurrency/rate 秒应发出一个请求
+		result.SetExpectedInterval(time.Duration(float64(cfg.Concurrency) / arrivalRate * float64(time.Second)))
+	}
+	if len(cfg.Targets) > 0 {
+		result.RegisterTargets(targetNames(cfg.Targets))
+	}
+	if cfg.CompareEndpoints != "" {
+		result.EnableComparison(types.CompareTargetA, types.CompareTargetB)
+	}
+
+	var rawLog *rawLog
+	if cfg.RawLog != "" {
+		rawLog, err = newRawLog(cfg.RawLog)
+		if err != nil {
+			logger.Close()
+			return nil, err
+		}
+	}
+
+	// 实时指标服务，在 Run 中开始提供服务
+	var metrics *metricsServer
+	if cfg.MetricsAddr != "" {
+		metrics, err = newMetricsServer(cfg.MetricsAddr, result)
+		if err != nil {
+			if rawLog != nil {
+				rawLog.close()
+			}
+			logger.Close()
+			return nil, err
+		}
+	}
 
 	// 创建上下文
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &StressEngine{
+	e := &StressEngine{
 		config:     cfg,
 		client:     client,
 		csvParser:  csvParser,
+		bodyDir:    bodyDir,
+		fileBody:   fileBody,
+		graphQL:    graphQL,
+		form:       form,
+		extractors: extractors,
 		tmplParser: tmplParser,
+		tokens:     tokens,
+		limiter:    limiter,
+		rateSteps:  rateSteps,
+		arrivals:   arrivals,
+		jitter:     newStartJitter(cfg.StartJitter, cfg.Seed),
+		forbidden:  compileForbidden(cfg.ForbidBodyContains),
 		reporter:   reporter,
 		logger:     logger,
-		result:     types.NewStressResult(),
+		result:     result,
+		metrics:    metrics,
+		rawLog:     rawLog,
 		ctx:        ctx,
 		cancel:     cancel,
 		workers:    make([]*Worker, 0, cfg.Concurrency),
-	}, nil
+		input:      os.Stdin,
+		drain:      make(chan struct{}),
+	}
+
+	// 快速失败：第一个失败的请求停止整个压测
+	if cfg.FailFast {
+		result.EnableFailFast(e.Stop)
+	}
+
+	return e, nil
+}
+
+// compileForbidden 将禁止出现的子串编译为一个正则表达式，未配置时返回 nil
+func compileForbidden(substrings []string) *regexp.Regexp {
+	if len(substrings) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(substrings))
+	for i, s := range substrings {
+		quoted[i] = regexp.QuoteMeta(s)
+	}
+	return regexp.MustCompile(strings.Join(quoted, "|"))
+}
+
+// OnBeforeRequest 在共享客户端上注册请求中间件，在内置中间件（令牌、签名）之后执行，
+// 此时 worker 已展开 URL、请求体和 headers 模板。需在 Run 之前调用
+func (e *StressEngine) OnBeforeRequest(m resty.RequestMiddleware) *StressEngine {
+	e.client.OnBeforeRequest(m)
+	return e
+}
+
+// OnAfterResponse 在共享客户端上注册响应中间件，返回错误时该请求记为失败。需在 Run 之前调用
+func (e *StressEngine) OnAfterResponse(m resty.ResponseMiddleware) *StressEngine {
+	e.client.OnAfterResponse(m)
+	return e
 }
 
-// Run 运行压测
+// Run 运行压测，直到达到停止条件或调用 Stop
 func (e *StressEngine) Run() *types.StressResult {
+	return e.RunContext(context.Background())
+}
+
+// RunContext 运行压测，ctx 被取消时与调用 Stop 一样停止压测，仍返回已完成请求的结果
+func (e *StressEngine) RunContext(ctx context.Context) *types.StressResult {
+	stopOnCancel := context.AfterFunc(ctx, e.Stop)
+	defer stopOnCancel()
+
 	e.logger.Info("Starting stress test...")
 	e.logger.Info("URL: %s", e.config.URL)
 	e.logger.Info("Method: %s", e.config.Method)
@@ -109,45 +470,277 @@ func (e *StressEngine) Run() *types.StressResult {
 
 	if e.config.IsDurationBased() {
 		e.logger.Info("Duration: %v", e.config.Duration)
+		if e.config.StopOn != "" {
+			e.logger.Info("Total Requests: %d (stop on %s)", e.config.TotalRequests, e.config.StopOn)
+		}
+	} else if e.config.Successes > 0 {
+		e.logger.Info("Success Goal: %d (max %d attempts)", e.config.Successes, e.config.AttemptLimit())
 	} else {
 		e.logger.Info("Total Requests: %d", e.config.TotalRequests)
 	}
 
 	if e.csvParser != nil {
 		e.logger.Info("CSV Data Rows: %d", e.csvParser.RowCount())
+		if e.config.RandomCSVMode() {
+			e.logger.Info("CSV Mode: %s (seed %d)", e.config.CSVMode, e.config.Seed)
+		}
+	}
+
+	if e.config.BatchSize > 0 {
+		e.logger.Info("Batch Size: %d rows per request", e.config.BatchSize)
+	}
+
+	if e.bodyDir != nil {
+		e.logger.Info("Body Files: %d", e.bodyDir.Count())
+	}
+
+	if e.config.BodyFile != "" {
+		e.logger.Info("Body File: %s (%d bytes)", e.config.BodyFile, len(e.fileBody))
+	}
+
+	if e.form != nil {
+		e.logger.Info("Multipart Form: %d fields, %d files", len(e.form.fields), len(e.form.files))
+	}
+
+	if len(e.extractors) > 0 {
+		e.logger.Info("Extract: %s (per worker)", strings.Join(e.config.Extract, ", "))
+	}
+
+	if e.config.GraphQL != "" {
+		e.logger.Info("GraphQL Query: %s (responses with errors count as failed)", e.config.GraphQL)
+	}
+
+	if e.config.Binary {
+		e.logger.Info("Binary Body: sent as raw bytes, no template processing")
+	}
+
+	if len(e.config.Targets) > 0 {
+		e.logger.Info("Targets: %d", len(e.config.Targets))
+	}
+
+	if e.config.Burst > 0 {
+		e.logger.Info("Burst: %d requests released at once", e.config.Burst)
+	}
+
+	if e.config.ReadBodyOn == types.ReadBodyError || e.config.ReadBodyOn == types.ReadBodyNone {
+		e.logger.Info("Read Response Bodies: %s (others drained and discarded)", e.config.ReadBodyOn)
+	}
+
+	if e.rawLog != nil {
+		e.logger.Info("Raw Log: %s", e.config.RawLog)
+	}
+
+	if e.config.RetryCount > 0 {
+		on := "5xx"
+		if e.config.RetryOnStatus != "" {
+			on = "status " + e.config.RetryOnStatus
+		}
+		e.logger.Info("Retries: up to %d on transport errors and %s", e.config.RetryCount, on)
+	}
+
+	if e.config.DNSServers != "" {
+		e.logger.Info("DNS Servers: %s", e.config.DNSServers)
+	}
+
+	if len(e.config.Resolve) > 0 {
+		e.logger.Info("Resolve: %s", strings.Join(e.config.Resolve, ", "))
+	}
+
+	if e.config.TimeoutAs == types.TimeoutAsWarn {
+		e.logger.Info("Timeouts: warn only, excluded from the error rate gate")
+	}
+
+	if e.config.RateLimit > 0 {
+		e.logger.Info("Rate Limit: %d req/sec", e.config.RateLimit)
+	}
+
+	if e.config.OpenModel {
+		e.logger.Info("Open Model: up to %d requests in flight, arrivals beyond that are dropped", e.config.Concurrency)
+	}
+
+	if e.arrivals != nil {
+		e.logger.Info("Arrivals: Poisson, %.1f req/sec mean (seed %d)", e.arrivals.rate, e.config.Seed)
+	}
+
+	if e.config.ThinkTime != "" {
+		e.logger.Info("Think Time: %s", e.config.ThinkTime)
+	}
+
+	if e.config.RampUp > 0 {
+		e.logger.Info("Ramp-Up: %d workers over %v", e.config.Concurrency, e.config.RampUp)
+	}
+
+	if e.jitter != nil {
+		e.logger.Info("Start Jitter: up to %v per worker (seed %d)", e.jitter.max, e.config.Seed)
+	}
+
+	if e.config.CRUD {
+		e.logger.Info("CRUD Sequence: %s", strings.Join(e.config.CRUDSequence(), " -> "))
 	}
 
 	e.startTime = time.Now()
 	e.result.StartTime = e.startTime
 
+	if e.metrics != nil {
+		e.metrics.start()
+		e.logger.Info("Metrics: http://%s/metrics", e.metrics.addr())
+	}
+
 	// 预热工作协程
 	e.startWorkers()
 
-	// 启动进度监控
-	if e.config.Verbose {
-		go e.monitorProgress()
+	// 交互模式下读取命令并显示实时面板，测试结束时等待面板退出并清除状态行
+	if e.config.Interactive {
+		interactiveCtx, cancelInteractive := context.WithCancel(e.ctx)
+		dashboardDone := make(chan struct{})
+		go e.runInteractive(interactiveCtx)
+		go func() {
+			defer close(dashboardDone)
+			e.monitorDashboard(interactiveCtx)
+		}()
+		defer func() {
+			cancelInteractive()
+			<-dashboardDone
+			e.logger.ClearProgress()
+		}()
+	}
+
+	// 启动进度监控，测试结束时等待其退出并清除进度行，避免与报告输出交错
+	if (e.config.Verbose || e.config.Dashboard) && !e.config.Interactive {
+		progressCtx, cancelProgress := context.WithCancel(e.ctx)
+		progressDone := make(chan struct{})
+		go func() {
+			defer close(progressDone)
+			e.monitorProgress(progressCtx)
+		}()
+		defer func() {
+			cancelProgress()
+			<-progressDone
+			e.logger.ClearProgress()
+		}()
+	}
+
+	// 定期刷新令牌，测试结束时等待刷新协程退出
+	if e.tokens != nil && e.config.TokenRefreshInterval > 0 {
+		refreshCtx, cancelRefresh := context.WithCancel(e.ctx)
+		refreshDone := make(chan struct{})
+		go func() {
+			defer close(refreshDone)
+			e.tokens.refreshLoop(refreshCtx, e.config.TokenRefreshInterval, e.logger)
+		}()
+		defer func() {
+			cancelRefresh()
+			<-refreshDone
+		}()
+	}
+
+	// 按速率计划调整限速器
+	var scheduleDone chan struct{}
+	scheduleCtx, cancelSchedule := context.WithCancel(e.ctx)
+	if len(e.rateSteps) > 0 {
+		scheduleDone = make(chan struct{})
+		go func() {
+			defer close(scheduleDone)
+			e.runRateSchedule(scheduleCtx)
+		}()
 	}
 
 	// 等待测试完成
 	e.waitForCompletion()
 
+	cancelSchedule()
+	if scheduleDone != nil {
+		<-scheduleDone
+	}
+
 	e.result.EndTime = time.Now()
+	e.result.Interrupted = atomic.LoadInt32(&e.draining) == 1
+	if e.csvParser != nil {
+		e.result.CSVCoverage = e.csvParser.Coverage()
+	}
+	if e.bodyDir != nil {
+		e.result.DistinctBodies = e.bodyDir.Used()
+	}
 	e.result.CalculateMetrics()
 
+	// 所有工作协程已退出，原始日志不会再有写入
+	if e.rawLog != nil {
+		if err := e.rawLog.flush(); err != nil {
+			e.logger.Error("Failed to write raw log: %v", err)
+		}
+	}
+
 	e.logger.Info("Stress test completed")
 
+	// 确保报告输出前日志已完整写入
+	e.logger.Flush()
+
 	return e.result
 }
 
 // startWorkers 启动工作协程
 func (e *StressEngine) startWorkers() {
+	if e.config.Burst > 0 {
+		e.startBurst()
+		return
+	}
+
+	if e.config.TargetPools {
+		e.startTargetPools()
+		return
+	}
+
+	if e.config.Interactive {
+		e.startInteractive()
+		return
+	}
+
+	if e.config.OpenModel {
+		e.startOpenModel()
+		return
+	}
+
 	// 使用缓冲channel提高性能
 	requests := make(chan struct{}, e.config.Concurrency*2)
+	e.startWorkerGroup(requests, e.config.Concurrency, nil)
+
+	// 发送请求任务
+	go e.sendRequests(requests, e.config.Concurrency, e.config.TotalRequests)
+}
 
-	// 预创建工作协程
-	for i := 0; i < e.config.Concurrency; i++ {
-		worker := NewWorker(e.config, e.client, e.csvParser, e.tmplParser, e.result, e.ctx)
-		e.workers = append(e.workers, worker)
+// startTargetPools 按权重为每个目标分配独立的工作协程组和请求数，
+// 各组写入同一个 StressResult，结果按目标名称区分
+func (e *StressEngine) startTargetPools() {
+	targets := e.config.Targets
+	weights := targetWeights(targets)
+	workers := splitByWeight(e.config.Concurrency, weights, 1)
+	totals := splitByWeight(e.config.TotalRequests, weights, 0)
+
+	for i := range targets {
+		target := &targets[i]
+		e.result.SetTargetConcurrency(target.DisplayName(), workers[i])
+		e.logger.Info("Target pool: %s (%d workers)", target.DisplayName(), workers[i])
+
+		requests := make(chan struct{}, workers[i]*2)
+		e.startWorkerGroup(requests, workers[i], target)
+		go e.sendRequests(requests, workers[i], totals[i])
+	}
+}
+
+// startWorkerGroup 预创建 n 个从 requests 读取任务的工作协程，target 不为空时协程只压测该目标。
+// 开启 -ramp-up 时第 i 个协程在 RampUp*i/n 之后才开始读取任务，开启 -start-jitter 时再加上随机延迟
+func (e *StressEngine) startWorkerGroup(requests <-chan struct{}, n int, target *types.TargetSpec) {
+	for i := 0; i < n; i++ {
+		worker := e.newWorker()
+		if target != nil {
+			worker.targets = newTargetSelector([]types.TargetSpec{*target})
+		}
+		if e.config.RampUp > 0 {
+			worker.startDelay = e.config.RampUp * time.Duration(i) / time.Duration(n)
+		}
+		if e.jitter != nil {
+			worker.startDelay += e.jitter.next()
+		}
 
 		e.wg.Add(1)
 		go func(w *Worker) {
@@ -155,34 +748,89 @@ func (e *StressEngine) startWorkers() {
 			w.Run(requests)
 		}(worker)
 	}
+}
 
-	// 发送请求任务
-	go e.sendRequests(requests)
+// startBurst 为每个请求预创建一个工作协程，全部就绪后同时释放，每个协程只发送一个请求
+func (e *StressEngine) startBurst() {
+	release := make(chan struct{})
+	var ready sync.WaitGroup
+
+	for i := 0; i < e.config.Burst; i++ {
+		worker := e.newWorker()
+		task := make(chan struct{}, 1)
+		task <- struct{}{}
+		close(task)
+
+		ready.Add(1)
+		e.wg.Add(1)
+		go func(w *Worker) {
+			defer e.wg.Done()
+			ready.Done()
+			<-release
+			w.Run(task)
+		}(worker)
+	}
+
+	ready.Wait()
+	e.result.StartBurst(e.config.Burst, time.Now())
+	close(release)
+}
+
+// newWorker 创建工作协程并登记到引擎
+func (e *StressEngine) newWorker() *Worker {
+	worker := NewWorker(e.config, e.client, e.csvParser, e.tmplParser, e.result, e.ctx)
+	worker.forbidden = e.forbidden
+	worker.bodyDir = e.bodyDir
+	worker.fileBody = e.fileBody
+	worker.graphQLQuery = e.graphQL
+	worker.form = e.form
+	worker.extractors = e.extractors
+	worker.contextFunc = e.reqContext
+	worker.drain = e.drain
+	worker.rawLog = e.rawLog
+	e.workers = append(e.workers, worker)
+	return worker
 }
 
-// sendRequests 发送请求任务
-func (e *StressEngine) sendRequests(requests chan<- struct{}) {
+// sendRequests 发送请求任务，concurrency 为接收任务的协程数，total 为按请求数测试时的任务总数
+func (e *StressEngine) sendRequests(requests chan<- struct{}, concurrency, total int) {
 	defer close(requests)
 
+	if e.limiter != nil {
+		e.sendPacedRequests(requests, total)
+		return
+	}
+
+	if e.arrivals != nil {
+		e.sendArrivals(requests, total)
+		return
+	}
+
+	if e.config.Successes > 0 {
+		e.sendUntilSuccesses(requests)
+		return
+	}
+
 	if e.config.IsDurationBased() {
-		// 基于时间的测试
-		timer := time.NewTimer(e.config.Duration)
-		defer timer.Stop()
+		// 基于时间的测试，-stop-on 时同时按请求数判断
+		stop := e.newStopCondition(total)
+		timed, cancel := stop.context(e.ctx)
+		defer cancel()
 
-		batchSize := e.config.Concurrency
+		batchSize := concurrency
 		batch := make([]struct{}, batchSize)
 
-		for {
+		for sent := 0; !stop.reached(sent); {
+			ctx := stop.waitContext(timed, e.ctx, sent)
 			select {
-			case <-timer.C:
-				return
-			case <-e.ctx.Done():
+			case <-ctx.Done():
 				return
 			default:
 				// 批量发送请求，减少channel操作
-				for i := 0; i < batchSize; i++ {
+				for i := 0; i < batchSize && !stop.reached(sent); i++ {
 					select {
 					case requests <- batch[i]:
+						sent++
 					case <-e.ctx.Done():
 						return
 					default:
@@ -194,8 +842,8 @@ func (e *StressEngine) sendRequests(requests chan<- struct{}) {
 		}
 	} else {
 		// 基于请求数量的测试 - 使用批量发送
-		batchSize := min(100, e.config.Concurrency)
-		remaining := e.config.TotalRequests
+		batchSize := min(100, concurrency)
+		remaining := total
 
 		for remaining > 0 {
 			currentBatch := min(batchSize, remaining)
@@ -211,13 +859,94 @@ func (e *StressEngine) sendRequests(requests chan<- struct{}) {
 	}
 }
 
+// sendPacedRequests 按限速器的节奏发送请求任务
+func (e *StressEngine) sendPacedRequests(requests chan<- struct{}, total int) {
+	stop := e.newStopCondition(total)
+	timed, cancel := stop.context(e.ctx)
+	defer cancel()
+
+	for sent := 0; !stop.reached(sent); sent++ {
+		ctx := stop.waitContext(timed, e.ctx, sent)
+		if err := e.limiter.Wait(ctx); err != nil {
+			return
+		}
+
+		select {
+		case requests <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendUntilSuccesses 持续发送请求任务，直到成功数达到目标或请求数达到上限。
+// 只有失败的请求会释放发送额度，已发送数减去失败数达到目标时等待在途请求完成，保证成功数不超过目标
+func (e *StressEngine) sendUntilSuccesses(requests chan<- struct{}) {
+	goal := int64(e.config.Successes)
+	limit := int64(e.config.AttemptLimit())
+
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	for sent := int64(0); ; {
+		if atomic.LoadInt64(&e.result.SuccessfulRequests) >= goal {
+			return
+		}
+		if sent >= limit {
+			e.logger.Info("Reached %d attempts before %d successes, stopping", limit, goal)
+			return
+		}
+
+		if sent-atomic.LoadInt64(&e.result.FailedRequests) >= goal {
+			select {
+			case <-ticker.C:
+			case <-e.ctx.Done():
+				return
+			}
+			continue
+		}
+
+		select {
+		case requests <- struct{}{}:
+			sent++
+		case <-e.ctx.Done():
+			return
+		}
+	}
+}
+
+// runRateSchedule 依次执行速率计划的各个阶段，并记录每个阶段的实际速率
+func (e *StressEngine) runRateSchedule(ctx context.Context) {
+	for _, step := range e.rateSteps {
+		e.limiter.SetLimit(rate.Limit(step.Rate))
+		e.logger.Debug("Rate schedule: %.1f req/sec for %v", step.Rate, step.Duration)
+
+		start := time.Now()
+		before := atomic.LoadInt64(&e.result.TotalRequests)
+		e.result.StartRateSegment()
+
+		timer := time.NewTimer(step.Duration)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+
+		e.result.AddRateSegment(step, time.Since(start), atomic.LoadInt64(&e.result.TotalRequests)-before)
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
 // waitForCompletion 等待测试完成
 func (e *StressEngine) waitForCompletion() {
 	e.wg.Wait()
 }
 
 // monitorProgress 监控进度
-func (e *StressEngine) monitorProgress() {
+func (e *StressEngine) monitorProgress(ctx context.Context) {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
@@ -235,24 +964,47 @@ func (e *StressEngine) monitorProgress() {
 			now := time.Now()
 			elapsed := now.Sub(e.startTime)
 
+			// 计算瞬时RPS
+			var instantRPS float64
+			if !lastTime.IsZero() {
+				instantRPS = float64(current-lastCount) / now.Sub(lastTime).Seconds()
+			}
+			lastCount = current
+			lastTime = now
+
+			var done, total int64
+			var remaining time.Duration
 			if e.config.IsDurationBased() {
-				remaining := e.config.Duration - elapsed
-				e.logger.Progress(current, 0, e.startTime, 0, remaining)
+				// -stop-on first 时请求数也可能先达到，同时显示请求数进度
+				remaining = e.config.Duration - elapsed
+				done = current
+				if e.config.StopOn == types.StopOnFirst {
+					total = int64(e.config.TotalRequests)
+				}
 			} else {
-				// 计算瞬时RPS
-				var instantRPS float64
-				if !lastTime.IsZero() {
-					instantRPS = float64(current-lastCount) / now.Sub(lastTime).Seconds()
+				// CRUD 模式下每次迭代发送多个请求，成功目标模式下按成功数显示进度
+				total = int64(e.config.TotalRequests)
+				if e.config.CRUD {
+					total *= int64(len(e.config.CRUDSequence()))
 				}
+				done = current
+				if e.config.Successes > 0 {
+					total = int64(e.config.Successes)
+					done = atomic.LoadInt64(&e.result.SuccessfulRequests)
+				}
+			}
 
-				total := int64(e.config.TotalRequests)
-				e.logger.Progress(current, total, e.startTime, instantRPS, 0)
-
-				lastCount = current
-				lastTime = now
+			// 实时面板无法在非终端输出中重绘，此时回退为普通进度输出
+			if e.config.Dashboard && e.logger.Panel(e.dashboardLines(now, done, total, remaining, instantRPS)) {
+				continue
+			}
+			if e.config.IsDurationBased() {
+				e.logger.Progress(done, total, e.startTime, 0, remaining)
+			} else {
+				e.logger.Progress(done, total, e.startTime, instantRPS, 0)
 			}
 
-		case <-e.ctx.Done():
+		case <-ctx.Done():
 			return
 		}
 	}
@@ -273,20 +1025,136 @@ func (e *StressEngine) GenerateReport() error {
 	return e.reporter.GenerateReport(e.result)
 }
 
+// ExportTimeSeries 导出按秒统计的时间序列 CSV
+func (e *StressEngine) ExportTimeSeries() error {
+	return e.reporter.WriteTimeSeries(e.result, e.config.TimeSeriesOut)
+}
+
+// ExportHistogram 以 HdrHistogram 日志格式导出响应时间直方图
+func (e *StressEngine) ExportHistogram() error {
+	return e.reporter.WriteHistogram(e.result, e.config.HistogramOut)
+}
+
+// ExportCDF 导出响应时间的累积分布 CSV
+func (e *StressEngine) ExportCDF() error {
+	return e.reporter.WriteCDF(e.result, e.config.CDFOut)
+}
+
+// MetricsAddr 返回实时指标服务实际监听的地址，未启用时返回空字符串
+func (e *StressEngine) MetricsAddr() string {
+	if e.metrics == nil {
+		return ""
+	}
+	return e.metrics.addr()
+}
+
 // PrintReport 打印报告
 func (e *StressEngine) PrintReport() {
 	e.reporter.ConsoleReport(e.result)
 }
 
-// Cleanup 清理资源
+// Cleanup 清理资源，可重复调用
 func (e *StressEngine) Cleanup() {
 	e.Stop()
-	e.logger.Close()
+	if e.metrics != nil {
+		e.metrics.shutdown()
+	}
+	if e.rawLog != nil {
+		if err := e.rawLog.close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to close raw log: %v\n", err)
+		}
+	}
+	if err := e.logger.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to close logger: %v\n", err)
+	}
 	if e.client != nil {
 		e.client.GetClient().CloseIdleConnections()
 	}
 }
 
+// traceEnabled 判断是否需要启用连接追踪，调整了空闲超时时同样统计连接复用情况，
+// TLS 握手、DNS 解析耗时和延迟分解同样来自追踪信息
+func traceEnabled(cfg *config.Config) bool {
+	return cfg.MaxRequestsPerConnection > 0 || cfg.KeepAliveProbe || idleTimeout(cfg) != types.DefaultIdleTimeout ||
+		cfg.MinTLSLatency != "" || cfg.PoolStats || cfg.DNSServers != "" || cfg.LatencyBreakdown
+}
+
+// newTLSConfig 根据配置创建 TLS 配置，默认校验服务端证书。
+// 启用会话缓存，使新建的连接可以像真实客户端一样恢复之前的 TLS 会话；-disable-keepalive-reuse 时不缓存会话，每次都完整握手
+func newTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	minVersion, maxVersion, err := cfg.TLSVersions()
+	if err != nil {
+		return nil, err
+	}
+	cipherSuites, err := cfg.CipherSuiteIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.Insecure,
+		MinVersion:         minVersion,
+		MaxVersion:         maxVersion,
+		CipherSuites:       cipherSuites,
+	}
+	if !cfg.DisableKeepAliveReuse {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+
+	// 双向 TLS 的客户端证书
+	if cfg.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// 只信任指定的根证书，不再使用系统根证书
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", cfg.CACert)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	return tlsConfig, nil
+}
+
+// trackTLSHandshakes 统计 TLS 握手中恢复会话的比例，VerifyConnection 对完整握手和会话恢复都会调用
+func trackTLSHandshakes(tlsConfig *tls.Config, result *types.StressResult) {
+	tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+		result.AddTLSHandshake(state.DidResume)
+		return nil
+	}
+}
+
+// idleTimeout 返回空闲连接保留时长，未设置时使用默认值
+func idleTimeout(cfg *config.Config) time.Duration {
+	if cfg.IdleTimeout <= 0 {
+		return types.DefaultIdleTimeout
+	}
+	return cfg.IdleTimeout
+}
+
+// keepAlive 判断是否复用连接，-keep-alive=false 和 -disable-keepalive-reuse 都会关闭复用
+func keepAlive(cfg *config.Config) bool {
+	return cfg.KeepAlive && !cfg.DisableKeepAliveReuse
+}
+
+// maxIdleConns 返回保留的空闲连接总数，未指定 -max-idle-conns 时为并发数的 2 倍
+func maxIdleConns(cfg *config.Config) int {
+	if cfg.MaxIdleConns <= 0 {
+		return cfg.Concurrency * 2
+	}
+	return cfg.MaxIdleConns
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"encoding/json"
+
+	"github.com/budyaya/resty-stress-tester/internal/parser"
+	"github.com/go-resty/resty/v2"
+)
+
+// templateData 返回渲染当前请求模板使用的数据：CSV 行加上本协程从之前的响应中提取的变量。
+// 变量只用于渲染模板，不写入请求结果，避免令牌等敏感值出现在报告中
+func (w *Worker) templateData(csvData map[string]string) map[string]string {
+	return parser.MergeVars(csvData, w.vars)
+}
+
+// extractVars 按 -extract 从成功的 JSON 响应中提取变量，供本协程后续的请求使用。
+// 响应不是 JSON 或路径不存在时保留变量之前的值，第一次提取成功之前模板中的占位符保持原样
+func (w *Worker) extractVars(resp *resty.Response) {
+	if len(w.extractors) == 0 {
+		return
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(resp.Body(), &data); err != nil {
+		return
+	}
+
+	for _, extractor := range w.extractors {
+		value, ok := parser.LookupJSONString(data, extractor.Path)
+		if !ok {
+			continue
+		}
+		if w.vars == nil {
+			w.vars = make(map[string]string, len(w.extractors))
+		}
+		w.vars[extractor.Name] = value
+	}
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/go-resty/resty/v2"
+)
+
+// graphQLResponse GraphQL 响应中用于判定成功的部分，data 不关心
+type graphQLResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQLBody 构建 {"query": ..., "variables": ...} 请求体，变量模板使用当前 CSV 行渲染
+func (w *Worker) graphQLBody(csvData map[string]string) (map[string]interface{}, error) {
+	body := map[string]interface{}{"query": w.graphQLQuery}
+	if w.config.GraphQLVars == "" {
+		return body, nil
+	}
+
+	variables, err := w.tmplParser.ProcessJSON(w.config.GraphQLVars, csvData)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := variables.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("variables must be a JSON object")
+	}
+	body["variables"] = variables
+	return body, nil
+}
+
+// checkGraphQLErrors GraphQL 出错时通常仍返回 200，响应的 errors 非空时将请求记为失败，
+// 错误信息取第一个错误的 message
+func (w *Worker) checkGraphQLErrors(result *types.RequestResult, resp *resty.Response) {
+	if w.graphQLQuery == "" || !result.Success {
+		return
+	}
+
+	var parsed graphQLResponse
+	if err := json.Unmarshal(resp.Body(), &parsed); err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("invalid GraphQL response: %v", err)
+		result.ErrorCategory = types.ErrorCategoryGraphQLError
+		return
+	}
+	if len(parsed.Errors) == 0 {
+		return
+	}
+
+	message := parsed.Errors[0].Message
+	if len(message) > 200 {
+		message = message[:200] + "..."
+	}
+	result.Success = false
+	result.Error = "GraphQL error: " + message
+	result.ErrorCategory = types.ErrorCategoryGraphQLError
+}
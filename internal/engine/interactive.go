@@ -0,0 +1,219 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// interactiveHelp 交互模式的命令说明
+const interactiveHelp = "Commands: + / - [n] change concurrency, c <n> set concurrency, r <req/sec> set rate (0 = unlimited), q quit"
+
+// workerPool 交互模式下可动态增减的工作协程组
+type workerPool struct {
+	mu       sync.Mutex
+	requests chan struct{}   // 发送结束后置为 nil，不再接受扩容
+	quits    []chan struct{} // 每个协程一个退出信号，缩容时从末尾关闭
+}
+
+// SetInput 设置交互模式读取命令的输入，默认为标准输入。需在 Run 之前调用
+func (e *StressEngine) SetInput(r io.Reader) *StressEngine {
+	e.input = r
+	return e
+}
+
+// startInteractive 启动可调整的工作协程组，初始并发数为配置的并发数，初始速率不限
+func (e *StressEngine) startInteractive() {
+	if e.limiter == nil {
+		e.limiter = rate.NewLimiter(rate.Inf, 1)
+	}
+
+	e.pool = &workerPool{requests: make(chan struct{}, e.config.Concurrency*2)}
+	e.setConcurrency(e.config.Concurrency)
+
+	// 发送协程计入 wg，保证发送结束、停止扩容之前 wg 计数不会归零
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		e.sendRequests(e.pool.requests, e.config.Concurrency, e.config.TotalRequests)
+
+		e.pool.mu.Lock()
+		e.pool.requests = nil
+		e.pool.mu.Unlock()
+	}()
+}
+
+// setConcurrency 将工作协程数调整为 n，返回调整后的协程数
+func (e *StressEngine) setConcurrency(n int) (int, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("concurrency must be positive")
+	}
+
+	e.pool.mu.Lock()
+	defer e.pool.mu.Unlock()
+
+	if e.pool.requests == nil {
+		return len(e.pool.quits), fmt.Errorf("all requests have been sent")
+	}
+
+	for len(e.pool.quits) < n {
+		quit := make(chan struct{})
+		worker := e.newWorker()
+		worker.quit = quit
+		e.pool.quits = append(e.pool.quits, quit)
+
+		e.wg.Add(1)
+		go func(w *Worker, requests <-chan struct{}) {
+			defer e.wg.Done()
+			w.Run(requests)
+		}(worker, e.pool.requests)
+	}
+
+	// 缩容时协程处理完当前请求后退出
+	for len(e.pool.quits) > n {
+		last := len(e.pool.quits) - 1
+		close(e.pool.quits[last])
+		e.pool.quits = e.pool.quits[:last]
+	}
+
+	return n, nil
+}
+
+// concurrency 返回交互模式下当前的工作协程数
+func (e *StressEngine) concurrency() int {
+	e.pool.mu.Lock()
+	defer e.pool.mu.Unlock()
+	return len(e.pool.quits)
+}
+
+// rateString 返回当前速率的显示文本
+func (e *StressEngine) rateString() string {
+	limit := e.limiter.Limit()
+	if limit == rate.Inf {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%.1f req/sec", float64(limit))
+}
+
+// runInteractive 逐行读取命令并调整并发数和速率，直到测试结束
+func (e *StressEngine) runInteractive(ctx context.Context) {
+	e.logger.Info(interactiveHelp)
+
+	// 读取输入会一直阻塞，测试结束后读取协程随进程退出
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(e.input)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line := <-lines:
+			if err := e.handleCommand(strings.TrimSpace(line)); err != nil {
+				e.logger.Error("%v", err)
+			}
+		}
+	}
+}
+
+// handleCommand 执行一条交互命令
+func (e *StressEngine) handleCommand(line string) error {
+	if line == "" {
+		return nil
+	}
+
+	cmd, arg := line[:1], strings.TrimSpace(line[1:])
+	switch cmd {
+	case "+", "-":
+		step := 1
+		if arg != "" {
+			var err error
+			if step, err = strconv.Atoi(arg); err != nil || step <= 0 {
+				return fmt.Errorf("invalid step: %s", arg)
+			}
+		}
+		if cmd == "-" {
+			step = -step
+		}
+		// 缩容最少保留一个工作协程
+		return e.applyConcurrency(max(1, e.concurrency()+step))
+
+	case "c":
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid concurrency: %s", arg)
+		}
+		return e.applyConcurrency(n)
+
+	case "r":
+		r, err := strconv.ParseFloat(arg, 64)
+		if err != nil || r < 0 {
+			return fmt.Errorf("invalid rate: %s", arg)
+		}
+		if r == 0 {
+			e.limiter.SetLimit(rate.Inf)
+		} else {
+			e.limiter.SetLimit(rate.Limit(r))
+		}
+		e.logger.Info("Rate: %s", e.rateString())
+		return nil
+
+	case "q":
+		e.Stop()
+		return nil
+
+	default:
+		return fmt.Errorf("unknown command %q. %s", line, interactiveHelp)
+	}
+}
+
+// applyConcurrency 调整并发数并记录
+func (e *StressEngine) applyConcurrency(n int) error {
+	n, err := e.setConcurrency(n)
+	if err != nil {
+		return err
+	}
+	e.logger.Info("Concurrency: %d", n)
+	return nil
+}
+
+// monitorDashboard 每秒刷新交互模式的状态行
+func (e *StressEngine) monitorDashboard(ctx context.Context) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	var lastCount int64
+	lastTime := e.startTime
+
+	for {
+		select {
+		case now := <-ticker.C:
+			current := atomic.LoadInt64(&e.result.TotalRequests)
+			failed := atomic.LoadInt64(&e.result.FailedRequests)
+			instantRPS := float64(current-lastCount) / now.Sub(lastTime).Seconds()
+			lastCount, lastTime = current, now
+
+			e.logger.Status(fmt.Sprintf("Requests: %d - Failed: %d - Instant: %6.1f req/sec - Concurrency: %d - Rate: %s - Elapsed: %v",
+				current, failed, instantRPS, e.concurrency(), e.rateString(), now.Sub(e.startTime).Round(time.Second)))
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
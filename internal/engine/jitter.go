@@ -0,0 +1,29 @@
+package engine
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// startJitter 为每个工作协程生成 [0, max) 内均匀分布的启动延迟，相同的种子产生相同的延迟序列
+type startJitter struct {
+	rng *rand.Rand
+	max time.Duration
+}
+
+// newStartJitter 创建启动延迟生成器，max 为 0 时返回 nil
+func newStartJitter(max time.Duration, seed int64) *startJitter {
+	if max <= 0 {
+		return nil
+	}
+	// 与到达过程使用不同的随机序列，两者同时开启时互不影响
+	return &startJitter{
+		rng: rand.New(rand.NewPCG(uint64(seed), ^uint64(seed))),
+		max: max,
+	}
+}
+
+// next 返回下一个工作协程的启动延迟
+func (j *startJitter) next() time.Duration {
+	return time.Duration(j.rng.Int64N(int64(j.max)))
+}
@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// metricsServer 在压测进行中通过 HTTP 提供实时指标：
+// /metrics 为 Prometheus 文本格式，/metrics.json 为 JSON
+type metricsServer struct {
+	listener net.Listener
+	server   *http.Server
+	result   *types.StressResult
+}
+
+// newMetricsServer 监听指定地址，创建时即占用端口以便尽早报告地址错误，调用 start 后开始提供服务
+func newMetricsServer(addr string, result *types.StressResult) (*metricsServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on metrics-addr: %v", err)
+	}
+
+	m := &metricsServer{listener: listener, result: result}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", m.handlePrometheus)
+	mux.HandleFunc("GET /metrics.json", m.handleJSON)
+	m.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	return m, nil
+}
+
+// addr 返回实际监听的地址
+func (m *metricsServer) addr() string {
+	return m.listener.Addr().String()
+}
+
+// start 开始提供服务
+func (m *metricsServer) start() {
+	go m.server.Serve(m.listener)
+}
+
+// shutdown 停止服务并释放端口，可重复调用
+func (m *metricsServer) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	m.server.Shutdown(ctx)
+	// 未调用 start 时 Shutdown 不会关闭监听
+	m.listener.Close()
+}
+
+func (m *metricsServer) handleJSON(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m.result.Snapshot(time.Now()))
+}
+
+func (m *metricsServer) handlePrometheus(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	writePrometheus(w, m.result.Snapshot(time.Now()))
+}
+
+// writePrometheus 以 Prometheus 文本格式输出实时指标，时长单位为秒
+func writePrometheus(w io.Writer, metrics types.LiveMetrics) {
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	metric("rst_requests_total", "counter", "Completed requests by outcome.")
+	fmt.Fprintf(w, "rst_requests_total{result=\"success\"} %d\n", metrics.SuccessfulRequests)
+	fmt.Fprintf(w, "rst_requests_total{result=\"failure\"} %d\n", metrics.FailedRequests)
+
+	metric("rst_responses_total", "counter", "Successful responses by HTTP status code.")
+	codes := make([]int, 0, len(metrics.StatusCodes))
+	for code := range metrics.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		fmt.Fprintf(w, "rst_responses_total{code=\"%d\"} %d\n", code, metrics.StatusCodes[code])
+	}
+
+	metric("rst_errors_total", "counter", "Failed requests by error category.")
+	categories := make([]string, 0, len(metrics.ErrorCategories))
+	for category := range metrics.ErrorCategories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		fmt.Fprintf(w, "rst_errors_total{category=%q} %d\n", category, metrics.ErrorCategories[category])
+	}
+
+	metric("rst_requests_per_second", "gauge", "Average request rate since the start of the run.")
+	fmt.Fprintf(w, "rst_requests_per_second %g\n", metrics.RequestsPerSecond)
+
+	metric("rst_elapsed_seconds", "gauge", "Time since the start of the run.")
+	fmt.Fprintf(w, "rst_elapsed_seconds %g\n", metrics.Elapsed.Seconds())
+
+	metric("rst_response_time_seconds", "gauge", "Response time quantiles of successful requests.")
+	fmt.Fprintf(w, "rst_response_time_seconds{quantile=\"0.5\"} %g\n", metrics.P50ResponseTime.Seconds())
+	fmt.Fprintf(w, "rst_response_time_seconds{quantile=\"0.9\"} %g\n", metrics.P90ResponseTime.Seconds())
+	fmt.Fprintf(w, "rst_response_time_seconds{quantile=\"0.99\"} %g\n", metrics.P99ResponseTime.Seconds())
+
+	metric("rst_response_time_avg_seconds", "gauge", "Average response time of all requests.")
+	fmt.Fprintf(w, "rst_response_time_avg_seconds %g\n", metrics.AvgResponseTime.Seconds())
+	metric("rst_response_time_min_seconds", "gauge", "Fastest response time.")
+	fmt.Fprintf(w, "rst_response_time_min_seconds %g\n", metrics.MinResponseTime.Seconds())
+	metric("rst_response_time_max_seconds", "gauge", "Slowest response time.")
+	fmt.Fprintf(w, "rst_response_time_max_seconds %g\n", metrics.MaxResponseTime.Seconds())
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/internal/parser"
+)
+
+// formFile multipart 表单中的文件字段，内容在启动时读入
+type formFile struct {
+	field    string
+	fileName string
+	data     []byte
+}
+
+// multipartForm -form 和 -form-file 指定的 multipart/form-data 请求体。
+// 工作协程复用同一个 resty 请求对象，而 resty 的 SetFileReader/SetMultipartFormData 会在请求对象上累积，
+// 因此每个请求自行编码请求体
+type multipartForm struct {
+	fields []config.FormField
+	files  []formFile
+}
+
+// newMultipartForm 解析表单配置并读入文件，未配置表单时返回 nil
+func newMultipartForm(cfg *config.Config) (*multipartForm, error) {
+	if !cfg.HasForm() {
+		return nil, nil
+	}
+
+	// 已通过配置验证
+	fields, _ := cfg.FormFieldList()
+	specs, _ := cfg.FormFileList()
+
+	form := &multipartForm{fields: fields}
+	for _, spec := range specs {
+		data, err := os.ReadFile(spec.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read form file: %v", err)
+		}
+		form.files = append(form.files, formFile{field: spec.Name, fileName: filepath.Base(spec.Value), data: data})
+	}
+	return form, nil
+}
+
+// encode 按 CSV 数据渲染表单字段并编码请求体，返回请求体和 Content-Type
+func (f *multipartForm) encode(tmplParser *parser.TemplateParser, csvData map[string]string) ([]byte, string, error) {
+	size := 0
+	for _, file := range f.files {
+		size += len(file.data)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, size+1024))
+	writer := multipart.NewWriter(buf)
+	for _, field := range f.fields {
+		if err := writer.WriteField(field.Name, tmplParser.Process(field.Value, csvData)); err != nil {
+			return nil, "", err
+		}
+	}
+	for _, file := range f.files {
+		part, err := writer.CreateFormFile(file.field, file.fileName)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(file.data); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
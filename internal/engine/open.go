@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+// startOpenModel 开放模型：调度协程按计划时间发出请求，不等待之前的请求完成，服务端变慢时发送速率不随之下降。
+// 每个请求在临时协程中发送，-c 个工作协程对象轮流复用，全部在途时丢弃该次到达并计数
+func (e *StressEngine) startOpenModel() {
+	idle := make(chan *Worker, e.config.Concurrency)
+	for i := 0; i < e.config.Concurrency; i++ {
+		idle <- e.newWorker()
+	}
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		e.dispatchOpen(idle, e.config.TotalRequests)
+	}()
+}
+
+// dispatchOpen 按计划时间派发请求，返回前等待所有在途请求完成。计划时间按绝对时间推进，
+// 调度落后时之后的到达立即补发；total 为按请求数测试时的到达总数，丢弃的到达同样计入。
+// 引擎排空时不再派发新的到达，只等待在途请求完成
+func (e *StressEngine) dispatchOpen(idle chan *Worker, total int) {
+	var inflight sync.WaitGroup
+	defer inflight.Wait()
+
+	stop := e.newStopCondition(total)
+	timed, cancel := stop.context(e.ctx)
+	defer cancel()
+
+	next := time.Now()
+	for sent := 0; !stop.reached(sent); sent++ {
+		ctx := stop.waitContext(timed, e.ctx, sent)
+		next = next.Add(e.openInterval())
+		if wait := time.Until(next); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-e.drain:
+				timer.Stop()
+				return
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+
+		select {
+		case <-e.drain:
+			return
+		default:
+		}
+
+		select {
+		case w := <-idle:
+			inflight.Add(1)
+			go func(scheduled time.Time) {
+				defer inflight.Done()
+				w.scheduled = scheduled
+				w.makeRequest()
+				idle <- w
+			}(next)
+		default:
+			e.result.AddDroppedArrival()
+		}
+	}
+}
+
+// openInterval 返回到下一次到达的间隔：-arrival 时服从指数分布，否则为限速器当前速率的倒数，
+// 速率计划切换阶段时随之变化
+func (e *StressEngine) openInterval() time.Duration {
+	if e.arrivals != nil {
+		return e.arrivals.next()
+	}
+	return time.Duration(float64(time.Second) / float64(e.limiter.Limit()))
+}
@@ -0,0 +1,28 @@
+package engine
+
+import "github.com/go-resty/resty/v2"
+
+// Option 创建压测引擎时的可选项，用于在 Go 代码中嵌入压测引擎
+type Option func(*engineOptions)
+
+// engineOptions NewStressEngine 的可选项
+type engineOptions struct {
+	client *resty.Client
+}
+
+// WithClient 使用调用方提供的 resty 客户端发送请求。引擎仍会在该客户端上设置超时、重试、认证、
+// cookie、重定向和中间件，但保留客户端自己的传输层，因此 TLS、代理、DNS 和连接池相关的配置不生效
+func WithClient(client *resty.Client) Option {
+	return func(o *engineOptions) {
+		o.client = client
+	}
+}
+
+// newEngineOptions 依次应用可选项
+func newEngineOptions(opts []Option) engineOptions {
+	var o engineOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
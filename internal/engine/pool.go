@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// connWait 记录单个请求从请求连接到拿到连接的时间点，用于计算等待连接池分配连接的时间。
+// 拨号可能在独立的协程中完成，因此需要加锁
+type connWait struct {
+	mu        sync.Mutex
+	getConn   time.Time
+	gotConn   time.Time
+	dialStart time.Time // DNS 解析或建立 TCP 连接的开始时间
+	dialDone  time.Time // 建立 TCP 连接或 TLS 握手的完成时间
+	reused    bool
+}
+
+// trace 返回记录各时间点的 httptrace 钩子，与 resty 的追踪钩子组合使用
+func (c *connWait) trace() *httptrace.ClientTrace {
+	dialStart := func() {
+		c.mu.Lock()
+		if c.dialStart.IsZero() {
+			c.dialStart = time.Now()
+		}
+		c.mu.Unlock()
+	}
+	dialDone := func() {
+		c.mu.Lock()
+		c.dialDone = time.Now()
+		c.mu.Unlock()
+	}
+
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			c.mu.Lock()
+			c.getConn = time.Now()
+			c.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			c.mu.Lock()
+			c.gotConn = time.Now()
+			c.reused = info.Reused
+			c.mu.Unlock()
+		},
+		DNSStart:         func(httptrace.DNSStartInfo) { dialStart() },
+		ConnectStart:     func(string, string) { dialStart() },
+		ConnectDone:      func(string, string, error) { dialDone() },
+		TLSHandshakeDone: func(tls.ConnectionState, error) { dialDone() },
+	}
+}
+
+// wait 返回等待连接池的时间：复用连接时为请求连接到拿到连接的全部时间，
+// 新建连接时扣除 DNS 解析、建立连接和 TLS 握手的时间。未拿到连接时返回 0
+func (c *connWait) wait() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.getConn.IsZero() || c.gotConn.IsZero() {
+		return 0
+	}
+
+	wait := c.gotConn.Sub(c.getConn)
+	if !c.reused && !c.dialStart.IsZero() && c.dialDone.After(c.dialStart) {
+		wait -= c.dialDone.Sub(c.dialStart)
+	}
+	return max(wait, 0)
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// rawLogBufferSize 原始日志的写缓冲区大小，高并发下减少系统调用
+const rawLogBufferSize = 64 * 1024
+
+// rawLog 将每个请求结果以 JSON 行（NDJSON）写入 -raw-log 文件，不受内存中明细结果条数的限制
+type rawLog struct {
+	mu      sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	err     error // 第一个写入错误，之后的结果不再写入
+}
+
+// newRawLog 创建原始日志文件，已存在时覆盖
+func newRawLog(path string) (*rawLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create raw log directory: %v", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raw log: %v", err)
+	}
+
+	writer := bufio.NewWriterSize(file, rawLogBufferSize)
+	return &rawLog{file: file, writer: writer, encoder: json.NewEncoder(writer)}, nil
+}
+
+// write 写入一条请求结果，l 为 nil 时不做任何事
+func (l *rawLog) write(result *types.RequestResult) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err == nil {
+		l.err = l.encoder.Encode(result)
+	}
+}
+
+// flush 将缓冲区写入文件，返回写入过程中的第一个错误
+func (l *rawLog) flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err == nil {
+		l.err = l.writer.Flush()
+	}
+	return l.err
+}
+
+// close 刷新并关闭文件，可重复调用
+func (l *rawLog) close() error {
+	err := l.flush()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return err
+	}
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
+
+// addResult 记录请求结果，开启 -raw-log 时同时写入原始日志，开放模型下记录从计划发送时间到完成的耗时
+func (w *Worker) addResult(result *types.RequestResult) {
+	if !w.scheduled.IsZero() {
+		result.ScheduledLatency = result.Timestamp.Sub(w.scheduled)
+	}
+	w.rawLog.write(result)
+	w.result.AddResult(result)
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/go-resty/resty/v2"
+)
+
+// redirectLoopError 重定向循环或超过最大跳转次数
+type redirectLoopError struct {
+	hops int
+	loop bool
+	err  error
+}
+
+func (e *redirectLoopError) Error() string {
+	if e.loop {
+		return fmt.Sprintf("redirect loop detected after %d redirects", e.hops)
+	}
+	return fmt.Sprintf("too many redirects: %v", e.err)
+}
+
+// redirectPolicy 根据配置返回重定向策略
+// 跟随重定向时检测循环并限制最大跳转次数（未设置时使用默认值）；不跟随时直接返回 3xx 响应
+func redirectPolicy(followRedirects bool, maxRedirects int) resty.RedirectPolicy {
+	if !followRedirects {
+		return resty.RedirectPolicyFunc(func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		})
+	}
+
+	if maxRedirects <= 0 {
+		maxRedirects = types.DefaultMaxRedirects
+	}
+	maxHops := resty.FlexibleRedirectPolicy(maxRedirects)
+	return resty.RedirectPolicyFunc(func(req *http.Request, via []*http.Request) error {
+		target := req.URL.String()
+		for _, prev := range via {
+			if prev.URL.String() == target {
+				return &redirectLoopError{hops: len(via), loop: true}
+			}
+		}
+		if err := maxHops.Apply(req, via); err != nil {
+			return &redirectLoopError{hops: len(via), err: err}
+		}
+		return nil
+	})
+}
+
+// isRedirectLoop 判断错误是否由重定向循环引起
+func isRedirectLoop(err error) (*redirectLoopError, bool) {
+	var loopErr *redirectLoopError
+	if errors.As(err, &loopErr) {
+		return loopErr, true
+	}
+	return nil, false
+}
@@ -16,6 +16,7 @@ type RestyClientConfig struct {
 	RetryCount       int
 	RetryWaitTime    time.Duration
 	RetryMaxWaitTime time.Duration
+	Insecure         bool // 跳过服务端证书校验
 }
 
 // NewRestyClient 创建 RESTy 客户端
@@ -31,7 +32,7 @@ func NewRestyClient(cfg *RestyClientConfig) *resty.Client {
 
 	// TLS 配置
 	client.SetTLSClientConfig(&tls.Config{
-		InsecureSkipVerify: true,
+		InsecureSkipVerify: cfg.Insecure,
 	})
 
 	// 重试配置
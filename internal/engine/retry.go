@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"github.com/go-resty/resty/v2"
+)
+
+// retryCondition 返回 resty 的重试条件：传输层错误（重定向循环除外）以及 statuses 中的状态码，
+// statuses 为空时重试所有 5xx。压测停止导致的取消由 resty 根据请求上下文判断，不会重试
+func retryCondition(statuses []int) resty.RetryConditionFunc {
+	retryable := make(map[int]bool, len(statuses))
+	for _, status := range statuses {
+		retryable[status] = true
+	}
+
+	return func(resp *resty.Response, err error) bool {
+		if err != nil {
+			_, loop := isRedirectLoop(err)
+			return !loop
+		}
+		if resp == nil {
+			return false
+		}
+		if len(retryable) == 0 {
+			return resp.StatusCode() >= 500
+		}
+		return retryable[resp.StatusCode()]
+	}
+}
+
+// discardLogger 丢弃 resty 的日志
+type discardLogger struct{}
+
+func (discardLogger) Errorf(format string, v ...interface{}) {}
+func (discardLogger) Warnf(format string, v ...interface{})  {}
+func (discardLogger) Debugf(format string, v ...interface{}) {}
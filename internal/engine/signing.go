@@ -0,0 +1,335 @@
+package engine
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/go-resty/resty/v2"
+)
+
+// 签名凭证使用的环境变量
+const (
+	envAWSAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	envAWSSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	envAWSSessionToken    = "AWS_SESSION_TOKEN"
+	envHMACSecret         = "RST_HMAC_SECRET"
+)
+
+// unsignedPayload 无法读取请求体（流式上传）时使用的 SigV4 负载哈希
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// signableRequest 签名所需的请求信息，请求体模板已展开
+type signableRequest struct {
+	method   string
+	url      *url.URL
+	header   http.Header
+	body     []byte
+	streamed bool // 请求体为流，无法参与签名
+}
+
+// requestSigner 为请求计算签名并写入请求头
+type requestSigner interface {
+	sign(req *signableRequest, now time.Time) error
+}
+
+// newRequestSigner 根据配置创建签名器，未开启签名时返回 nil
+func newRequestSigner(cfg *config.Config) (requestSigner, error) {
+	switch {
+	case cfg.AWSSigV4 != "":
+		return newSigV4Signer(cfg.AWSSigV4)
+	case cfg.HMACHeader != "":
+		return newHMACSigner(cfg)
+	}
+	return nil, nil
+}
+
+// signingMiddleware 返回在发送前对请求签名的 resty 中间件
+// 中间件在 worker 展开 URL 和请求体模板之后执行，因此签名覆盖最终发送的内容
+func signingMiddleware(s requestSigner) resty.RequestMiddleware {
+	return func(c *resty.Client, req *resty.Request) error {
+		u, err := url.Parse(req.URL)
+		if err != nil {
+			return fmt.Errorf("sign request: %v", err)
+		}
+
+		sr := &signableRequest{method: req.Method, url: u, header: req.Header}
+		switch body := req.Body.(type) {
+		case nil:
+		case string:
+			sr.body = []byte(body)
+		case []byte:
+			sr.body = body
+		case io.Reader:
+			sr.streamed = true
+		default:
+			// JSON 模板解析后的结构体在此序列化，保证签名与实际发送的字节一致
+			data, err := c.JSONMarshal(body)
+			if err != nil {
+				return fmt.Errorf("sign request: %v", err)
+			}
+			if req.Header.Get("Content-Type") == "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			req.SetBody(data)
+			sr.body = data
+		}
+
+		return s.sign(sr, time.Now())
+	}
+}
+
+// sigV4Signer AWS Signature Version 4 签名器
+type sigV4Signer struct {
+	region       string
+	service      string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// newSigV4Signer 解析 region:service 并从环境变量读取 AWS 凭证
+func newSigV4Signer(spec string) (*sigV4Signer, error) {
+	region, service, ok := strings.Cut(spec, ":")
+	if !ok || region == "" || service == "" {
+		return nil, fmt.Errorf("invalid aws-sigv4 value %q (expected region:service)", spec)
+	}
+
+	s := &sigV4Signer{
+		region:       region,
+		service:      service,
+		accessKey:    os.Getenv(envAWSAccessKeyID),
+		secretKey:    os.Getenv(envAWSSecretAccessKey),
+		sessionToken: os.Getenv(envAWSSessionToken),
+	}
+	if s.accessKey == "" || s.secretKey == "" {
+		return nil, fmt.Errorf("aws-sigv4 requires %s and %s", envAWSAccessKeyID, envAWSSecretAccessKey)
+	}
+	return s, nil
+}
+
+// sign 计算 SigV4 签名并设置 X-Amz-Date 和 Authorization 请求头
+func (s *sigV4Signer) sign(req *signableRequest, now time.Time) error {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := unsignedPayload
+	if !req.streamed {
+		payloadHash = sha256Hex(req.body)
+	}
+
+	req.header.Set("X-Amz-Date", amzDate)
+	if s.sessionToken != "" {
+		req.header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	// S3 要求通过请求头声明负载哈希
+	if s.service == "s3" {
+		req.header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	host := req.url.Host
+	if h := req.header.Get("Host"); h != "" {
+		host = h
+	}
+
+	// 只签名由本签名器设置的请求头，resty 之后补充的请求头不影响签名
+	signed := map[string]string{"host": host, "x-amz-date": amzDate}
+	if s.sessionToken != "" {
+		signed["x-amz-security-token"] = s.sessionToken
+	}
+	if s.service == "s3" {
+		signed["x-amz-content-sha256"] = payloadHash
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(signed[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.method,
+		s.canonicalURI(req.url),
+		canonicalQuery(req.url),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/" + s.service + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSum(sha256.New, []byte("AWS4"+s.secretKey), []byte(date))
+	key = hmacSum(sha256.New, key, []byte(s.region))
+	key = hmacSum(sha256.New, key, []byte(s.service))
+	key = hmacSum(sha256.New, key, []byte("aws4_request"))
+	signature := hex.EncodeToString(hmacSum(sha256.New, key, []byte(stringToSign)))
+
+	req.header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+	return nil
+}
+
+// canonicalURI 返回规范化路径，除 S3 外路径需再编码一次
+func (s *sigV4Signer) canonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	if s.service == "s3" {
+		return path
+	}
+	return awsURIEncode(path, false)
+}
+
+// canonicalQuery 返回按参数名和值排序的规范化查询串
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, awsURIEncode(key, true)+"="+awsURIEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode 按 SigV4 规则编码，仅保留 RFC 3986 非保留字符，encodeSlash 为 false 时保留 '/'
+func awsURIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// hmacSigner 通用 HMAC 签名器，按模板构造待签名字符串
+type hmacSigner struct {
+	header          string
+	newHash         func() hash.Hash
+	template        string
+	encoding        string
+	timestampHeader string
+	secret          []byte
+}
+
+// hmacAlgorithms 支持的 HMAC 哈希算法
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// newHMACSigner 根据配置创建 HMAC 签名器，密钥从环境变量读取
+func newHMACSigner(cfg *config.Config) (*hmacSigner, error) {
+	newHash, ok := hmacAlgorithms[strings.ToLower(cfg.HMACAlgorithm)]
+	if !ok {
+		return nil, fmt.Errorf("invalid hmac algorithm: %s (expected sha1, sha256 or sha512)", cfg.HMACAlgorithm)
+	}
+
+	secret := os.Getenv(envHMACSecret)
+	if secret == "" {
+		return nil, fmt.Errorf("hmac signing requires %s", envHMACSecret)
+	}
+
+	return &hmacSigner{
+		header:          cfg.HMACHeader,
+		newHash:         newHash,
+		template:        strings.ReplaceAll(cfg.HMACTemplate, `\n`, "\n"),
+		encoding:        strings.ToLower(cfg.HMACEncoding),
+		timestampHeader: cfg.HMACTimestampHeader,
+		secret:          []byte(secret),
+	}, nil
+}
+
+// sign 按模板计算 HMAC 并写入签名请求头，模板使用 {timestamp} 时同时设置时间戳请求头
+func (s *hmacSigner) sign(req *signableRequest, now time.Time) error {
+	usesBody := strings.Contains(s.template, "{body}") || strings.Contains(s.template, "{body_sha256}")
+	if usesBody && req.streamed {
+		return fmt.Errorf("hmac: cannot sign a streamed request body")
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	if strings.Contains(s.template, "{timestamp}") && s.timestampHeader != "" {
+		req.header.Set(s.timestampHeader, timestamp)
+	}
+
+	path := req.url.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	message := strings.NewReplacer(
+		"{method}", req.method,
+		"{path}", path,
+		"{query}", req.url.RawQuery,
+		"{timestamp}", timestamp,
+		"{body}", string(req.body),
+		"{body_sha256}", sha256Hex(req.body),
+	).Replace(s.template)
+
+	sum := hmacSum(s.newHash, s.secret, []byte(message))
+	signature := hex.EncodeToString(sum)
+	if s.encoding == "base64" {
+		signature = base64.StdEncoding.EncodeToString(sum)
+	}
+
+	req.header.Set(s.header, signature)
+	return nil
+}
+
+// hmacSum 计算 HMAC 摘要
+func hmacSum(newHash func() hash.Hash, key, data []byte) []byte {
+	mac := hmac.New(newHash, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// sha256Hex 计算 SHA-256 并以十六进制返回
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
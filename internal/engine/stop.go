@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// stopCondition 发送任务的停止条件：按时长、按请求数，或由 -stop-on 将两者组合
+type stopCondition struct {
+	deadline time.Time // 零值表示不限时长
+	total    int       // 0 表示不限请求数
+	both     bool      // 时长和请求数都达到才停止
+}
+
+// newStopCondition 从现在开始计时，total 为本组发送的任务总数
+func (e *StressEngine) newStopCondition(total int) stopCondition {
+	stop := stopCondition{total: total, both: e.config.StopOn == types.StopOnBoth}
+	if e.config.IsDurationBased() {
+		stop.deadline = time.Now().Add(e.config.Duration)
+	}
+	return stop
+}
+
+// expired 判断时长是否已到
+func (s stopCondition) expired() bool {
+	return !s.deadline.IsZero() && !time.Now().Before(s.deadline)
+}
+
+// reached 判断已发送 sent 个任务后是否应停止发送
+func (s stopCondition) reached(sent int) bool {
+	switch {
+	case s.deadline.IsZero():
+		return sent >= s.total
+	case s.total <= 0:
+		return s.expired()
+	case s.both:
+		return s.expired() && sent >= s.total
+	default:
+		return s.expired() || sent >= s.total
+	}
+}
+
+// context 返回在时长到达时取消的上下文，不限时长时只随 parent 取消
+func (s stopCondition) context(parent context.Context) (context.Context, context.CancelFunc) {
+	if s.deadline.IsZero() {
+		return context.WithCancel(parent)
+	}
+	return context.WithDeadline(parent, s.deadline)
+}
+
+// waitContext 返回发送第 sent+1 个任务时等待使用的上下文：时长到达即可停止时使用 timed，
+// 否则（-stop-on both 且请求数未达到）使用不随时长取消的 parent
+func (s stopCondition) waitContext(timed, parent context.Context, sent int) context.Context {
+	if s.both && sent < s.total {
+		return parent
+	}
+	return timed
+}
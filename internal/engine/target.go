@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"math/rand/v2"
+	"sort"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// targetSelector 按权重随机选择压测目标
+type targetSelector struct {
+	targets    []types.TargetSpec
+	cumulative []int
+	total      int
+}
+
+// newTargetSelector 创建目标选择器，未设置权重的目标按权重 1 处理
+func newTargetSelector(targets []types.TargetSpec) *targetSelector {
+	s := &targetSelector{
+		targets:    targets,
+		cumulative: make([]int, len(targets)),
+	}
+
+	for i, target := range targets {
+		weight := target.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		s.total += weight
+		s.cumulative[i] = s.total
+	}
+
+	return s
+}
+
+// pick 按权重随机选择一个目标
+func (s *targetSelector) pick() *types.TargetSpec {
+	n := rand.IntN(s.total)
+	for i, bound := range s.cumulative {
+		if n < bound {
+			return &s.targets[i]
+		}
+	}
+	return &s.targets[len(s.targets)-1]
+}
+
+// targetWeights 返回目标权重列表，未设置权重的目标按权重 1 处理
+func targetWeights(targets []types.TargetSpec) []int {
+	weights := make([]int, len(targets))
+	for i, target := range targets {
+		weights[i] = max(target.Weight, 1)
+	}
+	return weights
+}
+
+// splitByWeight 按权重将 total 分配给各项，每项至少分得 minEach，
+// 余数按最大余额法分配，保证分配结果之和等于 total
+func splitByWeight(total int, weights []int, minEach int) []int {
+	shares := make([]int, len(weights))
+	remaining := total - minEach*len(weights)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+
+	type remainder struct {
+		index int
+		value int
+	}
+	remainders := make([]remainder, len(weights))
+	assigned := 0
+	for i, w := range weights {
+		shares[i] = remaining * w / sum
+		assigned += shares[i]
+		remainders[i] = remainder{i, remaining * w % sum}
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value > remainders[j].value
+	})
+	for i := 0; i < remaining-assigned; i++ {
+		shares[remainders[i].index]++
+	}
+
+	for i := range shares {
+		shares[i] += minEach
+	}
+	return shares
+}
+
+// targetNames 返回目标在报告中的名称列表
+func targetNames(targets []types.TargetSpec) []string {
+	names := make([]string, len(targets))
+	for i := range targets {
+		names[i] = targets[i].DisplayName()
+	}
+	return names
+}
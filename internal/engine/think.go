@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// thinkTime 返回本次请求后的等待时间，范围内按均匀分布随机选取
+func (w *Worker) thinkTime() time.Duration {
+	if w.thinkMax <= w.thinkMin {
+		return w.thinkMin
+	}
+	return w.thinkMin + time.Duration(rand.Int64N(int64(w.thinkMax-w.thinkMin)+1))
+}
+
+// think 在两个请求之间等待 -think-time 指定的时间，等待不计入请求的响应时间。测试在等待期间结束时返回 false
+func (w *Worker) think() bool {
+	delay := w.thinkTime()
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-w.ctx.Done():
+		return false
+	case <-w.quit:
+		return false
+	case <-w.drain:
+		return false
+	}
+}
@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// uploadProgressKey 在请求上下文中保存上传进度的键
+type uploadProgressKey struct{}
+
+// uploadProgress 单个请求的请求体发送进度，由 Transport 的写协程更新
+type uploadProgress struct {
+	attached int32 // 请求带有请求体
+	complete int32 // 请求体已读取完毕
+	sent     int64 // 已交给 Transport 的字节数
+}
+
+// failedMidUpload 请求体已开始发送但未发送完毕时返回 true 和已发送的字节数
+func (p *uploadProgress) failedMidUpload() (int64, bool) {
+	if atomic.LoadInt32(&p.attached) == 0 || atomic.LoadInt32(&p.complete) == 1 {
+		return 0, false
+	}
+	sent := atomic.LoadInt64(&p.sent)
+	return sent, sent > 0
+}
+
+// countingBody 统计 Transport 从请求体读取的字节数
+type countingBody struct {
+	io.ReadCloser
+	progress *uploadProgress
+}
+
+// Read 实现 io.Reader
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddInt64(&b.progress.sent, int64(n))
+	if err == io.EOF {
+		atomic.StoreInt32(&b.progress.complete, 1)
+	}
+	return n, err
+}
+
+// trackUpload 是 resty 的 PreRequestHook，用计数读取器包装请求体，
+// 以便上传中途失败时区分请求侧错误并记录已发送的字节数
+func trackUpload(_ *resty.Client, req *http.Request) error {
+	progress, ok := req.Context().Value(uploadProgressKey{}).(*uploadProgress)
+	if !ok || req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	atomic.StoreInt32(&progress.attached, 1)
+	req.Body = &countingBody{ReadCloser: req.Body, progress: progress}
+	return nil
+}
@@ -2,7 +2,13 @@ package engine
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"net/http/httptrace"
+	"os"
+	"regexp"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -21,9 +27,50 @@ type Worker struct {
 	tmplParser *parser.TemplateParser
 	result     *types.StressResult
 	ctx        context.Context
+	quit       <-chan struct{} // 交互模式下缩减并发时关闭
+	drain      <-chan struct{} // 引擎排空时关闭
+	startDelay time.Duration   // -ramp-up 和 -start-jitter 分配的启动延迟
 	requestID  int64
+	trace      bool
+	targets    *targetSelector
+	crud       []string
+	forbidden  *regexp.Regexp
+	bodyDir    *parser.BodyDir
+	fileBody   string // -body-file 的内容
+	// -graphql 的查询
+	graphQLQuery string
+	// -form 和 -form-file 的 multipart 表单
+	form *multipartForm
+	// -extract 的响应变量及本协程已提取的值
+	extractors []config.Extractor
+	vars       map[string]string
+	// 嵌入使用时设置的每个请求的上下文
+	contextFunc RequestContextFunc
+	// 当前连接上已发送的请求数
+	connRequests int
+	// 连接健康统计：是否已建立过连接，上一个/当前请求是否要求关闭连接
+	connected      bool
+	closeRequested bool
+	closePending   bool
 	// 复用请求对象减少分配
 	baseRequest *resty.Request
+	// 当前请求的请求体发送进度
+	upload *uploadProgress
+	// 当前请求的响应压缩情况
+	compression *compressionStats
+	// -read-body-on 不读取全部响应体时由 readBody 处理响应体，discarded 为当前请求丢弃的字节数
+	selectiveBody bool
+	discarded     int64
+	// 当前请求的批量请求体包含的行数
+	batchRows int
+	// -pool-stats 开启时当前请求获取连接的时间点
+	connWait *connWait
+	// 开放模型下当前请求的计划发送时间
+	scheduled time.Time
+	// -think-time 的等待时间范围
+	thinkMin, thinkMax time.Duration
+	// -raw-log 的原始日志，未开启时为 nil
+	rawLog *rawLog
 }
 
 // NewWorker 创建工作协程
@@ -42,112 +89,447 @@ func NewWorker(
 		tmplParser: tmplParser,
 		result:     result,
 		ctx:        ctx,
+		trace:      traceEnabled(cfg),
 	}
 
+	if len(cfg.Targets) > 0 {
+		worker.targets = newTargetSelector(cfg.Targets)
+	}
+	if cfg.CRUD {
+		worker.crud = cfg.CRUDSequence()
+	}
+	// 已通过配置验证
+	worker.thinkMin, worker.thinkMax, _ = cfg.ThinkTimeRange()
+
 	// 预创建基础请求对象
 	worker.baseRequest = client.R().SetContext(ctx)
+	if cfg.ReadBodyOn == types.ReadBodyError || cfg.ReadBodyOn == types.ReadBodyNone {
+		worker.selectiveBody = true
+		worker.baseRequest.SetDoNotParseResponse(true)
+	}
 
 	return worker
 }
 
 // Run 运行工作协程
 func (w *Worker) Run(requests <-chan struct{}) {
-	for {
+	if !w.waitStart() {
+		return
+	}
+
+	for first := true; ; first = false {
 		select {
 		case <-w.ctx.Done():
 			return
+		case <-w.quit:
+			return
+		case <-w.drain:
+			return
 		case _, ok := <-requests:
-			if !ok {
+			if !ok || w.draining() {
 				return
 			}
-			w.makeRequest()
+			// 在两个请求之间等待，最后一个请求之后不再等待，避免拉长测试时长
+			if !first && !w.think() {
+				return
+			}
+			if w.crud != nil {
+				w.makeCRUDIteration()
+			} else {
+				w.makeRequest()
+			}
 		}
 	}
 }
 
+// waitStart 等待启动延迟，测试在等待期间结束时返回 false
+func (w *Worker) waitStart() bool {
+	if w.startDelay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(w.startDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-w.ctx.Done():
+		return false
+	case <-w.quit:
+		return false
+	case <-w.drain:
+		return false
+	}
+}
+
 // makeRequest 发送单个请求
 func (w *Worker) makeRequest() {
+	// 获取 CSV 数据，按行指定的间隔等待，等待时间不计入响应时间
+	csvData := w.nextCSVRow()
+	if !w.waitDelay(csvData) {
+		return
+	}
+
 	startTime := time.Now()
 
-	// 获取 CSV 数据
-	var csvData map[string]string
-	if w.csvParser != nil {
-		requestID := atomic.AddInt64(&w.requestID, 1)
-		csvData = w.csvParser.GetRow(int(requestID - 1))
+	// 多目标模式下按权重选择目标，目标未设置的字段使用全局配置
+	method, urlTemplate, bodyTemplate := w.config.Method, w.config.URL, w.config.Body
+	if w.config.BodyFile != "" {
+		bodyTemplate = w.fileBody
+	}
+	var target *types.TargetSpec
+	if w.targets != nil {
+		target = w.targets.pick()
+		urlTemplate = target.URL
+		if target.Method != "" {
+			method = target.Method
+		}
+		if target.Body != "" {
+			bodyTemplate = target.Body
+		}
 	}
 
-	// 复用基础请求对象
-	req := w.baseRequest
+	// 处理 URL，模板中可以引用 CSV 列和 -extract 提取的变量
+	data := w.templateData(csvData)
+	url := w.tmplParser.ProcessURL(urlTemplate, data)
 
-	// 处理 URL
-	url := w.tmplParser.ProcessURL(w.config.URL, csvData)
+	req := w.prepareRequest(csvData, target)
 
-	// 处理 Headers
-	if len(w.config.Headers) > 0 {
-		headers := w.tmplParser.ProcessHeaders(w.config.Headers, csvData)
-		req.SetHeaders(headers)
-	} else {
-		// 清除可能存在的headers
-		req.Header = make(map[string][]string)
-	}
-
-	// 处理请求体
-	if w.config.Body != "" {
-		body, err := w.tmplParser.ProcessJSON(w.config.Body, csvData)
+	// 处理请求体，CSV 行指定了请求体文件时直接流式发送文件，不读入内存
+	if w.graphQLQuery != "" {
+		body, err := w.graphQLBody(data)
 		if err != nil {
-			w.recordError(startTime, fmt.Sprintf("Failed to process body template: %v", err), csvData)
+			w.recordError(startTime, method, fmt.Sprintf("Failed to process GraphQL variables: %v", err), csvData, target)
 			return
 		}
 		req.SetBody(body)
-	} else {
-		req.SetBody(nil)
+	} else if w.form != nil {
+		body, contentType, err := w.form.encode(w.tmplParser, data)
+		if err != nil {
+			w.recordError(startTime, method, fmt.Sprintf("Failed to encode multipart form: %v", err), csvData, target)
+			return
+		}
+		req.SetHeader("Content-Type", contentType)
+		req.SetBody(body)
+	} else if w.config.BatchSize > 0 {
+		// 批量模式：当前行及其后的行各渲染一次请求体模板，组成 JSON 数组
+		rows := w.nextCSVBatch(csvData)
+		body, err := w.batchBody(bodyTemplate, rows)
+		if err != nil {
+			w.recordError(startTime, method, fmt.Sprintf("Failed to process body template: %v", err), csvData, target)
+			return
+		}
+		req.SetBody(body)
+		w.batchRows = len(rows)
+	} else if bodyFile := w.bodyFile(csvData); bodyFile != "" {
+		file, err := os.Open(bodyFile)
+		if err != nil {
+			w.recordError(startTime, method, fmt.Sprintf("Failed to open body file: %v", err), csvData, target)
+			return
+		}
+		defer file.Close()
+		req.SetBody(file)
+	} else if bodyTemplate != "" || w.bodyDir != nil {
+		// 未指定请求体时从请求体目录按顺序取下一个文件
+		if bodyTemplate == "" {
+			bodyTemplate = w.bodyDir.Next()
+		}
+		if w.config.Binary {
+			// 二进制请求体（protobuf、图片等）原样发送，不做模板替换
+			req.SetBody([]byte(bodyTemplate))
+		} else {
+			body, err := w.tmplParser.ProcessJSON(bodyTemplate, data)
+			if err != nil {
+				w.recordError(startTime, method, fmt.Sprintf("Failed to process body template: %v", err), csvData, target)
+				return
+			}
+			req.SetBody(body)
+		}
 	}
 
 	// 发送请求
-	var resp *resty.Response
-	var err error
+	resp, err := w.execute(req, method, url)
+
+	duration := time.Since(startTime)
+	w.recordResult(resp, err, duration, method, csvData, target)
+}
+
+// nextCSVBatch 返回以 first 开头、共 -batch-size 行的 CSV 数据
+func (w *Worker) nextCSVBatch(first map[string]string) []map[string]string {
+	rows := make([]map[string]string, 1, w.config.BatchSize)
+	rows[0] = first
+	for len(rows) < w.config.BatchSize {
+		rows = append(rows, w.nextCSVRow())
+	}
+	return rows
+}
+
+// batchBody 使用每一行数据渲染请求体模板，返回 JSON 数组请求体
+func (w *Worker) batchBody(template string, rows []map[string]string) ([]interface{}, error) {
+	elements := make([]interface{}, len(rows))
+	for i, row := range rows {
+		element, err := w.tmplParser.ProcessJSON(template, w.templateData(row))
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+	return elements, nil
+}
+
+// nextCSVRow 按顺序获取下一行 CSV 数据
+func (w *Worker) nextCSVRow() map[string]string {
+	if w.csvParser == nil {
+		return nil
+	}
+	requestID := atomic.AddInt64(&w.requestID, 1)
+	return w.csvParser.GetRow(int(requestID - 1))
+}
+
+// prepareRequest 重置复用的请求对象并设置 headers
+func (w *Worker) prepareRequest(csvData map[string]string, target *types.TargetSpec) *resty.Request {
+	// 复用基础请求对象，重置上下文避免追踪上下文逐次嵌套
+	// 每个请求使用新的上传进度和压缩统计，避免上一个请求的写协程更新到本次请求
+	req := w.baseRequest
+	w.upload = &uploadProgress{}
+	w.compression = &compressionStats{}
+	w.discarded = 0
+	w.batchRows = 0
+	var targetName string
+	if target != nil {
+		targetName = target.DisplayName()
+	}
+	ctx := w.requestContext(w.ctx, csvData, targetName)
+	ctx = context.WithValue(ctx, uploadProgressKey{}, w.upload)
+	if w.config.PoolStats {
+		w.connWait = &connWait{}
+		ctx = httptrace.WithClientTrace(ctx, w.connWait.trace())
+	}
+	req.SetContext(context.WithValue(ctx, compressionKey{}, w.compression))
+	req.SetBody(nil)
+	// resty 只在不重试时重置尝试次数
+	req.Attempt = 0
+
+	// 处理 Headers，先清除上一个请求遗留的 headers
+	req.Header = make(map[string][]string)
+	data := w.templateData(csvData)
+	if len(w.config.Headers) > 0 {
+		req.SetHeaders(w.tmplParser.ProcessHeaders(w.config.Headers, data))
+	}
+	if target != nil && len(target.Headers) > 0 {
+		req.SetHeaders(w.tmplParser.ProcessHeaders(target.Headers, data))
+	}
+	if w.config.Binary && req.Header.Get("Content-Type") == "" {
+		req.SetHeader("Content-Type", "application/octet-stream")
+	}
+
+	// 达到单连接请求上限时要求服务端关闭连接，下一个请求将重新建立连接
+	w.closeRequested = w.closePending
+	w.closePending = w.shouldCloseConnection()
+	if w.closePending {
+		req.SetHeader("Connection", "close")
+	}
 
-	switch strings.ToUpper(w.config.Method) {
+	return req
+}
+
+// execute 按 HTTP 方法发送请求，并在计时范围内读取或丢弃响应体
+func (w *Worker) execute(req *resty.Request, method, url string) (*resty.Response, error) {
+	resp, err := w.send(req, method, url)
+	if err != nil || !w.selectiveBody {
+		return resp, err
+	}
+	return resp, w.readBody(resp)
+}
+
+// readBody 按 -read-body-on 读取响应体，不需要的响应体读完后丢弃，保证连接可以复用
+func (w *Worker) readBody(resp *resty.Response) error {
+	body := resp.RawBody()
+	if body == nil {
+		return nil
+	}
+	defer body.Close()
+
+	if w.config.ReadsBody(resp.StatusCode()) {
+		data, err := io.ReadAll(body)
+		resp.SetBody(data)
+		return err
+	}
+
+	n, err := io.Copy(io.Discard, body)
+	w.discarded = n
+	return err
+}
+
+// send 按 HTTP 方法发送请求
+func (w *Worker) send(req *resty.Request, method, url string) (*resty.Response, error) {
+	switch strings.ToUpper(method) {
 	case "GET":
-		resp, err = req.Get(url)
+		return req.Get(url)
 	case "POST":
-		resp, err = req.Post(url)
+		return req.Post(url)
 	case "PUT":
-		resp, err = req.Put(url)
+		return req.Put(url)
 	case "DELETE":
-		resp, err = req.Delete(url)
+		return req.Delete(url)
 	case "PATCH":
-		resp, err = req.Patch(url)
+		return req.Patch(url)
 	case "HEAD":
-		resp, err = req.Head(url)
+		return req.Head(url)
 	case "OPTIONS":
-		resp, err = req.Execute("OPTIONS", url)
+		return req.Execute("OPTIONS", url)
 	default:
-		err = fmt.Errorf("unsupported HTTP method: %s", w.config.Method)
+		return nil, fmt.Errorf("unsupported HTTP method: %s", method)
 	}
+}
 
-	duration := time.Since(startTime)
-	w.recordResult(resp, err, duration, csvData)
+// waitDelay 等待当前 CSV 行通过 __delay_ms 列指定的时长，测试被取消时返回 false
+func (w *Worker) waitDelay(csvData map[string]string) bool {
+	if w.csvParser == nil || csvData == nil {
+		return true
+	}
+
+	delay := w.csvParser.Delay(csvData)
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
+
+// bodyFile 返回当前 CSV 行通过 __body_file 列指定的请求体文件
+func (w *Worker) bodyFile(csvData map[string]string) string {
+	if w.csvParser == nil || csvData == nil {
+		return ""
+	}
+	return w.csvParser.BodyFile(csvData)
+}
+
+// shouldCloseConnection 统计当前连接上的请求数，达到上限时返回 true 并重新计数
+func (w *Worker) shouldCloseConnection() bool {
+	limit := w.config.MaxRequestsPerConnection
+	if limit <= 0 {
+		return false
+	}
+
+	w.connRequests++
+	if w.connRequests >= limit {
+		w.connRequests = 0
+		return true
+	}
+	return false
 }
 
 // recordResult 记录请求结果
-func (w *Worker) recordResult(resp *resty.Response, err error, duration time.Duration, csvData map[string]string) {
+func (w *Worker) recordResult(resp *resty.Response, err error, duration time.Duration, method string, csvData map[string]string, target *types.TargetSpec) {
+	result := w.newResult(resp, err, duration, method, csvData, target)
+	w.checkGraphQLErrors(result, resp)
+	w.checkForbiddenContent(result, resp)
+
+	if result.Success {
+		w.extractMetric(resp)
+		w.extractVars(resp)
+	}
+
+	w.addResult(result)
+}
+
+// newResult 根据响应构建请求结果
+func (w *Worker) newResult(resp *resty.Response, err error, duration time.Duration, method string, csvData map[string]string, target *types.TargetSpec) *types.RequestResult {
 	result := &types.RequestResult{
 		Timestamp: time.Now(),
 		Duration:  duration,
+		Method:    strings.ToUpper(method),
 		CSVData:   csvData,
+		BatchRows: w.batchRows,
+	}
+
+	if w.baseRequest.Attempt > 1 {
+		result.Retries = w.baseRequest.Attempt - 1
+	}
+
+	if w.upload != nil {
+		result.RequestSize = atomic.LoadInt64(&w.upload.sent)
+	}
+
+	if target != nil {
+		result.Target = target.DisplayName()
+	}
+
+	if resp != nil && resp.Request != nil {
+		result.URL = resp.Request.URL
+	}
+
+	if resp != nil && resp.RawResponse != nil && resp.RawResponse.TLS != nil {
+		state := resp.RawResponse.TLS
+		result.TLSVersion = tls.VersionName(state.Version)
+		result.TLSCipher = tls.CipherSuiteName(state.CipherSuite)
+	}
+
+	if w.trace && resp != nil && resp.Request != nil {
+		traceInfo := resp.Request.TraceInfo()
+		result.ReusedConn = traceInfo.IsConnReused
+		result.NewConnection = !result.ReusedConn
+		result.TLSHandshake = traceInfo.TLSHandshake
+		result.DNSLookup = traceInfo.DNSLookup
+		if w.config.LatencyBreakdown {
+			result.ConnectTime = traceInfo.TCPConnTime
+			result.ServerTime = traceInfo.ServerTime
+		}
+		w.trackReconnect(result)
+		if w.connWait != nil {
+			result.ConnWait = w.connWait.wait()
+		}
 	}
 
 	if err != nil {
 		result.Success = false
 		result.Error = w.sanitizeError(err)
+		if w.canceledByShutdown(err) {
+			result.Error = "request canceled by shutdown"
+			result.ErrorCategory = types.ErrorCategoryShutdownCanceled
+		} else if loopErr, ok := isRedirectLoop(err); ok {
+			result.Error = loopErr.Error()
+			result.ErrorCategory = types.ErrorCategoryRedirectLoop
+		} else if isDNSError(err) {
+			// 解析超时同样归为 DNS 错误，便于与服务端响应超时区分
+			result.ErrorCategory = types.ErrorCategoryDNSError
+		} else if isTLSError(err) {
+			result.ErrorCategory = types.ErrorCategoryTLSError
+		} else if isTimeout(err) {
+			result.ErrorCategory = types.ErrorCategoryTimeout
+		} else if sent, ok := w.uploadFailed(); ok {
+			result.Error = "upload failed: " + result.Error
+			result.ErrorCategory = types.ErrorCategoryUploadError
+			result.BytesSent = sent
+		} else {
+			result.ErrorCategory = classifyConnectionError(err)
+		}
 	} else {
 		result.Success = true
 		result.StatusCode = resp.StatusCode()
-		result.ResponseSize = len(resp.Body())
+		result.ResponseSize = len(resp.Body()) + int(w.discarded)
+		if w.compression != nil && w.compression.compressed {
+			result.Compressed = true
+			result.WireSize = w.compression.wireSize
+			result.DecompressTime = w.compression.decompress
+		}
 
-		// 检查 HTTP 错误状态码
-		if resp.StatusCode() >= 400 {
+		// 目标配置了自身的判定条件时按目标规则检查
+		if target != nil && (len(target.ExpectStatus) > 0 || target.ExpectBodyContains != "") {
+			if reason := target.CheckResponse(resp.StatusCode(), resp.Body()); reason != "" {
+				result.Success = false
+				result.Error = reason
+			}
+		} else if resp.StatusCode() >= 400 {
+			// 检查 HTTP 错误状态码
 			result.Success = false
 			// 对于HTTP错误，提供更详细的错误信息
 			if len(resp.Body()) > 0 {
@@ -163,20 +545,67 @@ func (w *Worker) recordResult(resp *resty.Response, err error, duration time.Dur
 		}
 	}
 
-	w.result.AddResult(result)
+	return result
+}
+
+// canceledByShutdown 判断请求是否因压测停止（快速失败、交互模式退出等）而被取消
+func (w *Worker) canceledByShutdown(err error) bool {
+	return w.ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded))
+}
+
+// checkForbiddenContent 响应体包含禁止出现的内容时将请求记为失败，
+// 错误状态码的响应同样检查，以便统计压力下泄漏的堆栈等信息
+func (w *Worker) checkForbiddenContent(result *types.RequestResult, resp *resty.Response) {
+	if w.forbidden == nil || resp == nil {
+		return
+	}
+
+	if match := w.forbidden.Find(resp.Body()); match != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("forbidden content in response body: %q", match)
+		result.ErrorCategory = types.ErrorCategoryForbiddenContent
+	}
+}
+
+// uploadFailed 判断当前请求是否在发送请求体的过程中失败
+func (w *Worker) uploadFailed() (int64, bool) {
+	if w.upload == nil {
+		return 0, false
+	}
+	return w.upload.failedMidUpload()
+}
+
+// extractMetric 从成功响应中提取自定义数值指标
+func (w *Worker) extractMetric(resp *resty.Response) {
+	metric := w.result.CustomMetric
+	if metric == nil {
+		return
+	}
+
+	value, err := parser.ExtractJSONNumber(resp.Body(), w.config.ExtractMetric)
+	if err != nil {
+		metric.AddMissing()
+		return
+	}
+	metric.Add(value)
 }
 
 // recordError 记录错误
-func (w *Worker) recordError(startTime time.Time, errorMsg string, csvData map[string]string) {
+func (w *Worker) recordError(startTime time.Time, method, errorMsg string, csvData map[string]string, target *types.TargetSpec) {
 	result := &types.RequestResult{
 		Timestamp: time.Now(),
 		Duration:  time.Since(startTime),
+		Method:    strings.ToUpper(method),
 		Success:   false,
 		Error:     errorMsg,
 		CSVData:   csvData,
 	}
 
-	w.result.AddResult(result)
+	if target != nil {
+		result.Target = target.DisplayName()
+	}
+
+	w.addResult(result)
 }
 
 // sanitizeError 清理错误信息
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// BodyDir 从目录加载的请求体集合，请求按顺序循环使用
+type BodyDir struct {
+	bodies []string
+	next   int64
+	used   []int32 // 每个请求体是否被使用过
+}
+
+// NewBodyDir 按文件名顺序加载目录下的所有普通文件作为请求体，忽略子目录和隐藏文件
+func NewBodyDir(dir string) (*BodyDir, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body directory: %v", err)
+	}
+
+	d := &BodyDir{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body file: %v", err)
+		}
+		d.bodies = append(d.bodies, string(content))
+	}
+
+	if len(d.bodies) == 0 {
+		return nil, fmt.Errorf("body directory %s contains no files", dir)
+	}
+
+	d.used = make([]int32, len(d.bodies))
+	return d, nil
+}
+
+// Next 返回下一个请求体模板，所有工作协程共享同一个游标
+func (d *BodyDir) Next() string {
+	i := int((atomic.AddInt64(&d.next, 1) - 1) % int64(len(d.bodies)))
+	atomic.StoreInt32(&d.used[i], 1)
+	return d.bodies[i]
+}
+
+// Count 返回请求体文件数
+func (d *BodyDir) Count() int {
+	return len(d.bodies)
+}
+
+// Used 返回实际被使用过的不同请求体数
+func (d *BodyDir) Used() int {
+	used := 0
+	for i := range d.used {
+		if atomic.LoadInt32(&d.used[i]) == 1 {
+			used++
+		}
+	}
+	return used
+}
@@ -3,8 +3,24 @@ package parser
 import (
 	"encoding/csv"
 	"fmt"
+	"math/rand/v2"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// CSV 保留列
+const (
+	// BodyFileColumn 值为该行请求体所在文件的路径，文件内容会被流式发送
+	BodyFileColumn = "__body_file"
+	// DelayColumn 值为发送该行请求前等待的毫秒数，用于按记录的时间间隔回放流量
+	DelayColumn = "__delay_ms"
 )
 
 // CSVParser CSV 解析器
@@ -12,6 +28,15 @@ type CSVParser struct {
 	data     []map[string]string
 	headers  []string
 	rowCount int
+	// CSV 文件所在目录，用于解析相对路径
+	dir string
+	// 每行被使用的次数，启用覆盖率统计后有效
+	hits []int32
+	// 行选择方式：random 模式下 rng 非空；shuffle-once 模式下 order 为打乱后的行顺序
+	mode  string
+	order []int
+	rngMu sync.Mutex
+	rng   *rand.Rand
 }
 
 // NewCSVParser 创建 CSV 解析器
@@ -51,6 +76,11 @@ func NewCSVParser(filename string) (*CSVParser, error) {
 				row[header] = ""
 			}
 		}
+		if delay := row[DelayColumn]; delay != "" {
+			if ms, err := strconv.ParseFloat(delay, 64); err != nil || ms < 0 {
+				return nil, fmt.Errorf("invalid %s value on row %d: %q", DelayColumn, i, delay)
+			}
+		}
 		data = append(data, row)
 	}
 
@@ -58,6 +88,7 @@ func NewCSVParser(filename string) (*CSVParser, error) {
 		data:     data,
 		headers:  headers,
 		rowCount: len(data),
+		dir:      filepath.Dir(filename),
 	}, nil
 }
 
@@ -73,10 +104,73 @@ func (p *CSVParser) GetRow(index int) map[string]string {
 	}
 
 	// 使用取模运算实现循环读取
-	if p.rowCount > 0 {
-		return p.data[index%p.rowCount]
+	i := index % p.RowCount()
+	switch p.mode {
+	case types.CSVModeRandom:
+		p.rngMu.Lock()
+		i = p.rng.IntN(p.RowCount())
+		p.rngMu.Unlock()
+	case types.CSVModeShuffleOnce:
+		i = p.order[i]
+	}
+	if p.hits != nil {
+		atomic.AddInt32(&p.hits[i], 1)
+	}
+	return p.data[i]
+}
+
+// SetMode 设置行选择方式，需在读取数据前调用。sequential 按顺序循环；random 每次随机选取一行；
+// shuffle-once 立即打乱行顺序，之后按打乱后的顺序循环。相同的种子产生相同的顺序
+func (p *CSVParser) SetMode(mode string, seed int64) error {
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed)>>1))
+	switch mode {
+	case "", types.CSVModeSequential:
+		mode = types.CSVModeSequential
+	case types.CSVModeRandom:
+		p.rng = rng
+	case types.CSVModeShuffleOnce:
+		p.order = rng.Perm(p.RowCount())
+	default:
+		return fmt.Errorf("invalid CSV mode: %s (expected sequential, random or shuffle-once)", mode)
+	}
+	p.mode = mode
+	return nil
+}
+
+// EnableCoverage 开启按行统计使用次数，需在读取数据前调用
+func (p *CSVParser) EnableCoverage() {
+	p.hits = make([]int32, p.RowCount())
+}
+
+// Coverage 返回各行的使用情况，未开启覆盖率统计时返回 nil
+func (p *CSVParser) Coverage() *types.CSVCoverage {
+	if p.hits == nil {
+		return nil
+	}
+
+	hits := make([]int32, len(p.hits))
+	for i := range p.hits {
+		hits[i] = atomic.LoadInt32(&p.hits[i])
+	}
+	return types.NewCSVCoverage(hits)
+}
+
+// BodyFile 返回行数据中 __body_file 列指定的文件路径，相对路径以 CSV 文件所在目录为基准
+func (p *CSVParser) BodyFile(row map[string]string) string {
+	path := row[BodyFileColumn]
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(p.dir, path)
+}
+
+// Delay 返回行数据中 __delay_ms 列指定的等待时长，未指定时返回 0
+func (p *CSVParser) Delay(row map[string]string) time.Duration {
+	ms, err := strconv.ParseFloat(row[DelayColumn], 64)
+	if err != nil {
+		return 0
 	}
-	return p.data[index%len(p.data)]
+	return time.Duration(ms * float64(time.Millisecond))
 }
 
 // RowCount 获取行数
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LookupJSONPath 在已解码的 JSON 数据中按路径查找值
+// 路径使用点号分隔，可选 "$." 前缀，数组下标可写为 items[0] 或 items.0
+func LookupJSONPath(data interface{}, path string) (interface{}, bool) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return data, true
+	}
+
+	current := data
+	for _, segment := range splitJSONPath(path) {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// ExtractJSONPath 解析 JSON 文本并按路径查找值
+func ExtractJSONPath(body []byte, path string) (interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %v", err)
+	}
+
+	value, ok := LookupJSONPath(data, path)
+	if !ok {
+		return nil, fmt.Errorf("path %s not found", path)
+	}
+	return value, nil
+}
+
+// ExtractJSONString 解析 JSON 文本并按路径提取标量值的文本形式，数字按原样转换为字符串
+func ExtractJSONString(body []byte, path string) (string, error) {
+	value, err := ExtractJSONPath(body, path)
+	if err != nil {
+		return "", err
+	}
+
+	text, ok := scalarString(value)
+	if !ok {
+		return "", fmt.Errorf("value at %s is not a scalar", path)
+	}
+	return text, nil
+}
+
+// LookupJSONString 在已解码的 JSON 数据中按路径查找标量值的文本形式，路径不存在或不是标量时返回 false
+func LookupJSONString(data interface{}, path string) (string, bool) {
+	value, ok := LookupJSONPath(data, path)
+	if !ok {
+		return "", false
+	}
+	return scalarString(value)
+}
+
+// scalarString 返回标量值的文本形式，数字按原样转换为字符串
+func scalarString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+// ExtractJSONNumber 解析 JSON 文本并按路径提取数值，数字字符串同样被接受
+func ExtractJSONNumber(body []byte, path string) (float64, error) {
+	value, err := ExtractJSONPath(body, path)
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("value at %s is not numeric: %q", path, v)
+		}
+		return number, nil
+	default:
+		return 0, fmt.Errorf("value at %s is not numeric", path)
+	}
+}
+
+// splitJSONPath 将路径拆分为字段名和数组下标
+func splitJSONPath(path string) []string {
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+
+	parts := strings.Split(path, ".")
+	segments := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			segments = append(segments, part)
+		}
+	}
+	return segments
+}
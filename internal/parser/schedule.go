@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// ParseRateSchedule 解析请求速率计划文件
+// 每行格式为 "持续时间 速率"，如 "60s 100"，空行和以 # 开头的行被忽略
+func ParseRateSchedule(filename string) ([]types.RateStep, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rate schedule: %v", err)
+	}
+	defer file.Close()
+
+	var steps []types.RateStep
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		step, err := parseRateStep(line)
+		if err != nil {
+			return nil, fmt.Errorf("rate schedule line %d: %v", lineNum, err)
+		}
+		steps = append(steps, step)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rate schedule: %v", err)
+	}
+
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("rate schedule is empty")
+	}
+
+	return steps, nil
+}
+
+// parseRateStep 解析单行速率计划
+func parseRateStep(line string) (types.RateStep, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return types.RateStep{}, fmt.Errorf("expected \"<duration> <rate>\", got %q", line)
+	}
+
+	duration, err := time.ParseDuration(fields[0])
+	if err != nil {
+		return types.RateStep{}, fmt.Errorf("invalid duration %q: %v", fields[0], err)
+	}
+	if duration <= 0 {
+		return types.RateStep{}, fmt.Errorf("duration must be positive: %s", fields[0])
+	}
+
+	rate, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return types.RateStep{}, fmt.Errorf("invalid rate %q: %v", fields[1], err)
+	}
+	if rate <= 0 {
+		return types.RateStep{}, fmt.Errorf("rate must be positive: %s", fields[1])
+	}
+
+	return types.RateStep{Duration: duration, Rate: rate}, nil
+}
@@ -1,11 +1,22 @@
 package parser
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// templateFuncPattern 匹配内置函数占位符，如 {{uuid}}、{{randInt 1 100}}
+var templateFuncPattern = regexp.MustCompile(`\{\{\s*(uuid|timestamp|timestampMs|randInt|randString)((?:\s+[^{}\s]+)*)\s*\}\}`)
+
+// randStringChars {{randString n}} 使用的字符
+const randStringChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
 // TemplateParser 模板解析器
 type TemplateParser struct {
 	csvParser *CSVParser
@@ -18,9 +29,10 @@ func NewTemplateParser(csvParser *CSVParser) *TemplateParser {
 	}
 }
 
-// Process 处理模板字符串
+// Process 处理模板字符串：先替换 CSV 列，再对内置函数逐个求值，没有 CSV 数据时同样生效。
+// 与内置函数同名的 CSV 列优先
 func (p *TemplateParser) Process(template string, data map[string]string) string {
-	if data == nil {
+	if !strings.Contains(template, "{{") {
 		return template
 	}
 
@@ -29,7 +41,109 @@ func (p *TemplateParser) Process(template string, data map[string]string) string
 		placeholder := "{{" + key + "}}"
 		result = strings.ReplaceAll(result, placeholder, value)
 	}
-	return result
+	return evalFuncs(result)
+}
+
+// evalFuncs 对模板中的内置函数求值，每次出现都单独求值，参数无效的占位符保持原样
+func evalFuncs(template string) string {
+	if !strings.Contains(template, "{{") {
+		return template
+	}
+
+	return templateFuncPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		match := templateFuncPattern.FindStringSubmatch(placeholder)
+		value, err := callFunc(match[1], strings.Fields(match[2]))
+		if err != nil {
+			return placeholder
+		}
+		return value
+	})
+}
+
+// callFunc 执行一个内置函数
+func callFunc(name string, args []string) (string, error) {
+	switch name {
+	case "uuid":
+		if len(args) != 0 {
+			break
+		}
+		return newUUID(), nil
+	case "timestamp":
+		if len(args) != 0 {
+			break
+		}
+		return strconv.FormatInt(time.Now().Unix(), 10), nil
+	case "timestampMs":
+		if len(args) != 0 {
+			break
+		}
+		return strconv.FormatInt(time.Now().UnixMilli(), 10), nil
+	case "randInt":
+		if len(args) != 2 {
+			break
+		}
+		min, minErr := strconv.ParseInt(args[0], 10, 64)
+		max, maxErr := strconv.ParseInt(args[1], 10, 64)
+		if minErr != nil || maxErr != nil || min > max {
+			return "", fmt.Errorf("randInt expects min <= max, got %s %s", args[0], args[1])
+		}
+		return strconv.FormatInt(min+rand.Int64N(max-min+1), 10), nil
+	case "randString":
+		if len(args) != 1 {
+			break
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return "", fmt.Errorf("randString expects a positive length, got %s", args[0])
+		}
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = randStringChars[rand.IntN(len(randStringChars))]
+		}
+		return string(b), nil
+	}
+	return "", fmt.Errorf("wrong number of arguments to %s: %d", name, len(args))
+}
+
+// newUUID 生成随机的 UUID v4
+func newUUID() string {
+	var b [16]byte
+	for i := 0; i < len(b); i += 8 {
+		v := rand.Uint64()
+		for j := 0; j < 8; j++ {
+			b[i+j] = byte(v >> (8 * j))
+		}
+	}
+	b[6] = b[6]&0x0f | 0x40 // 版本 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 变体
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
+
+// MergeVars 合并 CSV 数据和从响应中提取的变量，同名时使用变量的值。没有变量时直接返回 data
+func MergeVars(data, vars map[string]string) map[string]string {
+	if len(vars) == 0 {
+		return data
+	}
+
+	merged := make(map[string]string, len(data)+len(vars))
+	for key, value := range data {
+		merged[key] = value
+	}
+	for key, value := range vars {
+		merged[key] = value
+	}
+	return merged
 }
 
 // ProcessJSON 处理 JSON 模板
@@ -53,7 +167,7 @@ func (p *TemplateParser) ProcessURL(urlTemplate string, data map[string]string)
 
 // ProcessHeaders 处理 Headers 模板
 func (p *TemplateParser) ProcessHeaders(headers map[string]string, data map[string]string) map[string]string {
-	if data == nil {
+	if data == nil && !hasPlaceholder(headers) {
 		return headers
 	}
 
@@ -64,6 +178,16 @@ func (p *TemplateParser) ProcessHeaders(headers map[string]string, data map[stri
 	return processed
 }
 
+// hasPlaceholder 判断 headers 中是否有需要求值的占位符
+func hasPlaceholder(headers map[string]string) bool {
+	for _, value := range headers {
+		if strings.Contains(value, "{{") {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateTemplate 验证模板
 func (p *TemplateParser) ValidateTemplate(template string) error {
 	// 检查未闭合的模板标签
@@ -74,6 +198,12 @@ func (p *TemplateParser) ValidateTemplate(template string) error {
 		return fmt.Errorf("unbalanced template tags: %d opening vs %d closing", openCount, closeCount)
 	}
 
+	for _, match := range templateFuncPattern.FindAllStringSubmatch(template, -1) {
+		if _, err := callFunc(match[1], strings.Fields(match[2])); err != nil {
+			return fmt.Errorf("invalid template function %s: %v", match[0], err)
+		}
+	}
+
 	return nil
 }
 
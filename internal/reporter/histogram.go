@@ -0,0 +1,77 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// WriteHistogram 以 HdrHistogram 日志格式（.hlog）导出全部成功请求的响应时间直方图。
+// 整个压测作为一个区间，数值单位为微秒，Interval_Max 列为毫秒
+func (r *StressReporter) WriteHistogram(result *types.StressResult, filename string) error {
+	histogram := result.LatencyHistogram()
+	encoded, err := types.EncodeHistogram(histogram)
+	if err != nil {
+		return fmt.Errorf("failed to encode histogram: %v", err)
+	}
+
+	start := result.StartTime
+	length := result.EndTime.Sub(start).Seconds()
+	content := fmt.Sprintf("#[Histogram log format version 1.3]\n"+
+		"#[StartTime: %.3f (seconds since epoch), %s]\n"+
+		"\"StartTimestamp\",\"Interval_Length\",\"Interval_Max\",\"Interval_Compressed_Histogram\"\n"+
+		"%.3f,%.3f,%.3f,%s\n",
+		float64(start.UnixMilli())/1000, start.Format("Mon Jan 02 15:04:05 MST 2006"),
+		0.0, max(length, 0), float64(histogram.Max())/1000, encoded)
+
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write histogram file: %v", err)
+	}
+	return nil
+}
+
+// WriteCDF 将全部成功请求的响应时间直方图导出为累积分布的两列 CSV（latency_ms, cumulative_fraction），
+// 每个非空槽位一个点，精度与直方图相同（3 位有效数字），可直接绘制 CDF 曲线
+func (r *StressReporter) WriteCDF(result *types.StressResult, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create CDF file: %v", err)
+	}
+	if err := writeCDF(file, result.LatencyHistogram()); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeCDF 按槽位从小到大写入累积分布，每个点为槽位的上限
+func writeCDF(w io.Writer, histogram *hdrhistogram.Histogram) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"latency_ms", "cumulative_fraction"}); err != nil {
+		return err
+	}
+
+	var cumulative int64
+	total := float64(histogram.TotalCount())
+	for _, bar := range histogram.Distribution() {
+		if bar.Count == 0 {
+			continue
+		}
+		cumulative += bar.Count
+		record := []string{
+			strconv.FormatFloat(float64(bar.To)/1000, 'f', 3, 64),
+			strconv.FormatFloat(float64(cumulative)/total, 'f', 6, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,85 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// jsonReport JSON 报告中合并所需的部分
+type jsonReport struct {
+	Config   *config.Config      `json:"config"`
+	Result   *types.StressResult `json:"result"`
+	TimeUnit string              `json:"time_unit"`
+}
+
+// LoadJSONReport 读取 -report json 生成的报告
+func LoadJSONReport(filename string) (*config.Config, *types.StressResult, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read report: %v", err)
+	}
+
+	cfg, result, err := ParseJSONReport(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return cfg, result, nil
+}
+
+// ParseJSONReport 解析 JSON 报告
+func ParseJSONReport(data []byte) (*config.Config, *types.StressResult, error) {
+	report := jsonReport{Config: &config.Config{StressConfig: &types.StressConfig{}}}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, nil, fmt.Errorf("not a JSON report: %v", err)
+	}
+	if report.Result == nil {
+		return nil, nil, fmt.Errorf("report has no result section")
+	}
+	// 指定时间单位后时长被换算为小数，无法还原为原始样本
+	if report.TimeUnit != "" {
+		return nil, nil, fmt.Errorf("report was written with -time-unit %s; only reports without -time-unit can be merged", report.TimeUnit)
+	}
+	return report.Config, report.Result, nil
+}
+
+// MergeReports 读取多份 JSON 报告并合并为一次运行的结果
+func MergeReports(filenames []string) (*config.Config, *types.StressResult, error) {
+	if len(filenames) == 0 {
+		return nil, nil, fmt.Errorf("no reports to merge")
+	}
+
+	configs := make([]*config.Config, 0, len(filenames))
+	results := make([]*types.StressResult, 0, len(filenames))
+	for _, filename := range filenames {
+		cfg, result, err := LoadJSONReport(filename)
+		if err != nil {
+			return nil, nil, err
+		}
+		configs = append(configs, cfg)
+		results = append(results, result)
+	}
+
+	cfg, result := CombineReports(configs, results)
+	return cfg, result, nil
+}
+
+// CombineReports 将多份报告的配置和结果合并为一次运行。
+// 合并后的配置以第一份报告为准，并发数和请求总数取各报告之和
+func CombineReports(configs []*config.Config, results []*types.StressResult) (*config.Config, *types.StressResult) {
+	merged := *configs[0].StressConfig
+	for _, cfg := range configs[1:] {
+		merged.Concurrency += cfg.Concurrency
+		merged.TotalRequests += cfg.TotalRequests
+	}
+
+	result := types.NewStressResult()
+	for _, part := range results {
+		result.Merge(part)
+	}
+	result.CalculateMetrics()
+	return &config.Config{StressConfig: &merged}, result
+}
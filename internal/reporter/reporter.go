@@ -1,13 +1,20 @@
 package reporter
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/internal/util"
 	"github.com/budyaya/resty-stress-tester/pkg/types"
 )
 
@@ -18,18 +25,40 @@ type Reporter interface {
 	SaveReport(result *types.StressResult, filename string) error
 }
 
+// workerIdleThreshold 工作协程实际吞吐量低于理论上限的该百分比时提示增加并发无效
+const workerIdleThreshold = 50.0
+
+// slaDipPoints 最差一秒的 SLA 达标率比整体低出该百分点数时提示
+const slaDipPoints = 5.0
+
+// bytesPerMB 吞吐量以 MB/s 显示，与 FormatBytes 一样按 1024 进位
+const bytesPerMB = 1024 * 1024
+
+// rateShortfallThreshold 实际速率低于 -rate 目标速率的该百分比时提示工作协程跟不上
+const rateShortfallThreshold = 95.0
+
 // StressReporter 压测报告生成器
 type StressReporter struct {
-	config *config.Config
+	config    *config.Config
+	out       io.Writer
+	template  *template.Template
+	formatter *util.Formatter
 }
 
 // NewReporter 创建报告生成器
 func NewReporter(cfg *config.Config) *StressReporter {
 	return &StressReporter{
-		config: cfg,
+		config:    cfg,
+		out:       os.Stdout,
+		formatter: util.NewFormatter(),
 	}
 }
 
+// SetOutput 设置报告的控制台输出
+func (r *StressReporter) SetOutput(w io.Writer) {
+	r.out = w
+}
+
 // GenerateReport 生成报告
 func (r *StressReporter) GenerateReport(result *types.StressResult) error {
 	switch r.config.ReportFormat {
@@ -37,6 +66,8 @@ func (r *StressReporter) GenerateReport(result *types.StressResult) error {
 		return r.generateJSONReport(result)
 	case "html":
 		return r.generateHTMLReport(result)
+	case config.ReportFormatTemplate:
+		return r.generateTemplateReport(result)
 	default:
 		r.ConsoleReport(result)
 		return nil
@@ -53,6 +84,12 @@ func (r *StressReporter) ConsoleReport(result *types.StressResult) {
 	buf.WriteString(fmt.Sprintf("Target URL:          %s\n", r.config.URL))
 	buf.WriteString(fmt.Sprintf("HTTP Method:         %s\n", r.config.Method))
 	buf.WriteString(fmt.Sprintf("Concurrency:         %d\n", r.config.Concurrency))
+	if r.config.ThinkTime != "" {
+		buf.WriteString(fmt.Sprintf("Think Time:          %s\n", r.config.ThinkTime))
+	}
+	if r.config.RampUp > 0 {
+		buf.WriteString(fmt.Sprintf("Ramp-Up:             %v (1 -> %d workers)\n", r.config.RampUp, r.config.Concurrency))
+	}
 
 	if r.config.IsDurationBased() {
 		buf.WriteString(fmt.Sprintf("Test Duration:       %v\n", r.config.Duration))
@@ -64,38 +101,532 @@ func (r *StressReporter) ConsoleReport(result *types.StressResult) {
 		buf.WriteString(fmt.Sprintf("CSV Data Rows:       %d\n", len(result.DetailedResults)))
 	}
 
+	if coverage := result.CSVCoverage; coverage != nil {
+		buf.WriteString(fmt.Sprintf("CSV Coverage:        used %d of %d rows; %d unused, %d reused\n",
+			coverage.Used, coverage.Rows, coverage.Unused, coverage.Reused))
+		if len(coverage.UnusedRows) > 0 {
+			rows := make([]string, len(coverage.UnusedRows))
+			for i, row := range coverage.UnusedRows {
+				rows[i] = strconv.Itoa(row)
+			}
+			more := ""
+			if coverage.Unused > len(coverage.UnusedRows) {
+				more = ", ..."
+			}
+			buf.WriteString(fmt.Sprintf("Unused CSV Rows:     %s%s\n", strings.Join(rows, ", "), more))
+		}
+	}
+
+	if r.config.BodyDir != "" {
+		buf.WriteString(fmt.Sprintf("Distinct Bodies:     %d\n", result.DistinctBodies))
+	}
+
 	buf.WriteString(fmt.Sprintf("Actual Duration:     %v\n", result.TotalDuration))
+	if result.Interrupted {
+		buf.WriteString("Interrupted:         stopped by signal, results cover completed requests only\n")
+	}
+
+	// 没有完成的请求时成功率、吞吐量等都没有意义，只说明原因，避免看起来像正常的结果
+	if result.TotalRequests == 0 {
+		buf.WriteString("\nNo requests completed.\n")
+		if result.ShutdownCanceled > 0 {
+			buf.WriteString(fmt.Sprintf("Canceled by Stop:    %d (excluded from totals)\n", result.ShutdownCanceled))
+		}
+		r.writeErrorDistribution(&buf, result)
+		buf.WriteString(strings.Repeat("=", 70) + "\n")
+		fmt.Fprint(r.out, buf.String())
+		return
+	}
+
 	buf.WriteString(fmt.Sprintf("Total Requests:      %d\n", result.TotalRequests))
 	buf.WriteString(fmt.Sprintf("Successful:          %d\n", result.SuccessfulRequests))
 	buf.WriteString(fmt.Sprintf("Failed:              %d\n", result.FailedRequests))
+	timeoutNote := "counted as failed"
+	if result.TimeoutsAsWarnings() {
+		timeoutNote = "failed, excluded from error rate gate"
+	}
+	buf.WriteString(fmt.Sprintf("Timeouts:            %d (%.2f%%, %s)\n", result.Timeouts, result.GetTimeoutRate(), timeoutNote))
+	if result.ShutdownCanceled > 0 {
+		note := "counted as failed"
+		if result.ShutdownCanceledExcluded() {
+			note = "excluded from totals"
+		}
+		buf.WriteString(fmt.Sprintf("Canceled by Stop:    %d (%s)\n", result.ShutdownCanceled, note))
+	}
 	buf.WriteString(fmt.Sprintf("Success Rate:        %.2f%%\n", result.GetSuccessRate()))
 
+	if goal := result.SuccessGoal; goal != nil {
+		status := "reached"
+		if !goal.Reached {
+			status = "NOT reached, attempt limit hit"
+		}
+		buf.WriteString(fmt.Sprintf("Success Goal:        %d/%d in %d attempts (%s)\n", goal.Successes, goal.Goal, goal.Attempts, status))
+	}
+
 	if result.TotalRequests > 0 {
 		buf.WriteString(fmt.Sprintf("Requests/sec:        %.2f\n", result.GetRequestsPerSecond()))
+		r.writeDataTransfer(&buf, result)
+		r.writeRateLimit(&buf, result)
+		r.writeWorkerThroughput(&buf, result)
 		buf.WriteString(fmt.Sprintf("Avg Response Time:   %v\n", result.GetAverageResponseTime()))
 		buf.WriteString(fmt.Sprintf("Min Response Time:   %v\n", result.GetMinResponseTime()))
 		buf.WriteString(fmt.Sprintf("Max Response Time:   %v\n", result.GetMaxResponseTime()))
-		// 新增分位数统计显示
-		buf.WriteString(fmt.Sprintf("P50 Response Time:   %v\n", result.P50ResponseTime))
-		buf.WriteString(fmt.Sprintf("P90 Response Time:   %v\n", result.P90ResponseTime))
-		buf.WriteString(fmt.Sprintf("P99 Response Time:   %v\n", result.P99ResponseTime))
+		// 新增分位数统计显示，配置了 -percentiles 时按配置顺序输出
+		if percentiles := result.GetPercentiles(); percentiles != nil {
+			for _, p := range percentiles {
+				buf.WriteString(fmt.Sprintf("%-21s%v\n", types.PercentileLabel(p.Percentile)+" Response Time:", p.ResponseTime))
+			}
+		} else {
+			buf.WriteString(fmt.Sprintf("P50 Response Time:   %v\n", result.P50ResponseTime))
+			buf.WriteString(fmt.Sprintf("P90 Response Time:   %v\n", result.P90ResponseTime))
+			buf.WriteString(fmt.Sprintf("P99 Response Time:   %v\n", result.P99ResponseTime))
+		}
+		if corrected := result.Corrected; corrected != nil && corrected.Measured {
+			// 开放模型下从计划发送时间测量，包括请求等待发出的时间
+			buf.WriteString(fmt.Sprintf("Corrected P50:       %v\n", corrected.P50ResponseTime))
+			buf.WriteString(fmt.Sprintf("Corrected P90:       %v\n", corrected.P90ResponseTime))
+			buf.WriteString(fmt.Sprintf("Corrected P99:       %v  (measured from scheduled send time)\n", corrected.P99ResponseTime))
+		} else if corrected != nil {
+			buf.WriteString(fmt.Sprintf("Corrected P99:       %v  (coordinated omission, %.1f%% back-filled)\n", corrected.P99ResponseTime, corrected.BackfilledPercent))
+		}
+		if result.DroppedArrivals > 0 {
+			buf.WriteString(fmt.Sprintf("Dropped Arrivals:    %d  (all -c requests in flight)\n", result.DroppedArrivals))
+		}
+		buf.WriteString(fmt.Sprintf("Std Dev:             %v\n", result.StdDevResponseTime))
+		if trimmed := result.Trimmed; trimmed != nil {
+			buf.WriteString(fmt.Sprintf("%-21s%v\n", fmt.Sprintf("Trimmed Avg (%g%%):", trimmed.Percent), trimmed.AvgResponseTime))
+			buf.WriteString(fmt.Sprintf("Trimmed Std Dev:     %v  (%d outliers excluded)\n", trimmed.StdDevResponseTime, trimmed.Excluded))
+		}
 	}
 
+	if result.NewConnections > 0 {
+		buf.WriteString(fmt.Sprintf("New Connections:     %d\n", result.NewConnections))
+		buf.WriteString(fmt.Sprintf("Reused Connections:  %d\n", result.ReusedConns))
+		buf.WriteString(fmt.Sprintf("Requests/Connection: %.2f\n", result.GetRequestsPerConnection()))
+	}
+
+	// 延迟 SLA 达标率
+	r.writeSLACompliance(&buf, result)
+
+	// 延迟分解
+	r.writeLatencyBreakdown(&buf, result)
+
+	// 连接健康
+	r.writeConnectionHealth(&buf, result)
+
+	// 连接池等待
+	r.writeConnectionPool(&buf, result)
+
+	// DNS 解析
+	r.writeDNS(&buf, result)
+
+	// TLS 协商结果
+	r.writeTLS(&buf, result)
+
+	// 响应压缩
+	r.writeCompression(&buf, result)
+
+	// 批量请求
+	r.writeBatch(&buf, result)
+
+	// 重试
+	r.writeRetries(&buf, result)
+
+	// 突发请求
+	r.writeBurst(&buf, result)
+
+	// 分目标结果
+	r.writeTargetResults(&buf, result)
+
+	// A/B 对比
+	r.writeComparison(&buf, result)
+
+	// 分方法结果
+	r.writeMethodResults(&buf, result)
+
+	// 速率计划
+	r.writeRateSegments(&buf, result)
+
+	// 自定义指标
+	r.writeCustomMetric(&buf, result)
+
 	// 状态码分布
 	r.writeStatusCodes(&buf, result)
 
+	// 分状态码响应时间
+	r.writeStatusLatency(&buf, result)
+
 	// 错误分布
 	r.writeErrorDistribution(&buf, result)
 
+	// 触发快速失败的请求
+	r.writeFailFast(&buf, result)
+
 	buf.WriteString(strings.Repeat("=", 70) + "\n")
 
 	// 检查是否需要警告
 	if result.ShouldFail() {
-		buf.WriteString(fmt.Sprintf("\n⚠️  Warning: High failure rate (%.1f%%) detected!\n",
-			100-result.GetSuccessRate()))
+		buf.WriteString(fmt.Sprintf("\n⚠️  Warning: High failure rate (%.1f%%) detected! (threshold %g%%)\n",
+			100-result.GetSuccessRate(), result.FailureThreshold()))
+	}
+
+	// 超时通常说明服务端已饱和，只作提示时单独给出警告
+	if result.TimeoutsAsWarnings() && result.Timeouts > 0 {
+		buf.WriteString(fmt.Sprintf("\n⚠️  Warning: %d requests (%.1f%%) timed out after %v - the server may be saturated.\n",
+			result.Timeouts, result.GetTimeoutRate(), r.config.Timeout))
+	}
+
+	if slow := result.DetectSlowStart(types.DefaultSlowStartWindow, r.config.WarnSlowStart); slow != nil {
+		buf.WriteString(fmt.Sprintf("\n⚠️  Warning: Slow start detected - avg response time in the first %v was %v, %.1fx the steady-state %v.\n",
+			slow.Window, slow.WarmupAvg.Round(time.Microsecond), slow.Ratio, slow.SteadyAvg.Round(time.Microsecond)))
+		buf.WriteString(fmt.Sprintf("   Warmup inflated the overall average by %.0f%%; consider a warmup run or a longer -duration.\n",
+			slow.OverallSkew*100))
+	}
+
+	// 已通过配置验证，解析错误时视为未开启
+	tlsPercent, _ := r.config.TLSLatencyPercent()
+	if overhead := result.DetectTLSOverhead(tlsPercent); overhead != nil {
+		buf.WriteString(fmt.Sprintf("\n⚠️  Warning: TLS handshakes account for %.0f%% of latency (avg %v of %v per request).\n",
+			overhead.Percent, overhead.AvgHandshake.Round(time.Microsecond), overhead.AvgResponse.Round(time.Microsecond)))
+		buf.WriteString("   Enable -keep-alive, avoid forcing reconnects, or enable TLS session resumption on the server.\n")
+	}
+
+	// 一次性写入，避免与进度输出交错
+	fmt.Fprint(r.out, buf.String())
+}
+
+// writeDataTransfer 写入收发的数据量和吞吐量，没有请求体的请求不输出 Data Sent
+func (r *StressReporter) writeDataTransfer(buf *strings.Builder, result *types.StressResult) {
+	buf.WriteString(fmt.Sprintf("Data Received:       %s (%.2f MB/s)\n",
+		r.formatter.FormatBytes(result.TotalBytesReceived), result.GetBytesReceivedPerSecond()/bytesPerMB))
+	if result.TotalBytesSent > 0 {
+		buf.WriteString(fmt.Sprintf("Data Sent:           %s (%.2f MB/s)\n",
+			r.formatter.FormatBytes(result.TotalBytesSent), result.GetBytesSentPerSecond()/bytesPerMB))
+	}
+}
+
+// writeRateLimit 写入固定速率模式的目标速率，实际速率明显低于目标时提示工作协程跟不上
+func (r *StressReporter) writeRateLimit(buf *strings.Builder, result *types.StressResult) {
+	stats := result.RateLimit
+	if stats == nil {
+		return
+	}
+
+	buf.WriteString(fmt.Sprintf("Target Rate:         %.2f (%.1f%% achieved)\n", stats.TargetRate, stats.Percent))
+	if stats.Percent < rateShortfallThreshold {
+		buf.WriteString("  Workers could not keep up with the target rate; add workers (-c) or check server latency.\n")
+	}
+}
+
+// writeWorkerThroughput 写入每个工作协程的实际吞吐量，并与按平均响应时间计算的理论上限（1/平均响应时间）比较。
+// 远低于上限说明工作协程大部分时间在等待，增加并发不会提高吞吐量
+func (r *StressReporter) writeWorkerThroughput(buf *strings.Builder, result *types.StressResult) {
+	avg := result.GetAverageResponseTime()
+	if r.config.Concurrency <= 0 || avg <= 0 {
+		return
+	}
+
+	perWorker := result.GetRequestsPerSecond() / float64(r.config.Concurrency)
+	maxPerWorker := 1 / avg.Seconds()
+	busy := perWorker / maxPerWorker * 100
+	buf.WriteString(fmt.Sprintf("RPS/Worker:          %.2f (max %.2f at avg response time, %.0f%% busy)\n", perWorker, maxPerWorker, busy))
+	if busy < workerIdleThreshold {
+		buf.WriteString("  Workers spent most of the time waiting between requests (rate limit, delays or client overhead);\n")
+		buf.WriteString("  adding workers will not raise throughput.\n")
+	}
+}
+
+// writeSLACompliance 写入延迟 SLA 的整体达标率和达标率最低的一秒
+func (r *StressReporter) writeSLACompliance(buf *strings.Builder, result *types.StressResult) {
+	sla := result.SLACompliance
+	if sla == nil {
+		return
+	}
+
+	buf.WriteString(fmt.Sprintf("\nSLA Compliance (<= %v):\n", sla.SLA))
+	buf.WriteString(fmt.Sprintf("  Overall:                %.2f%%\n", sla.Compliance))
+	buf.WriteString(fmt.Sprintf("  Worst Second:           %.2f%% at %ds (%d requests)\n", sla.WorstCompliance, sla.WorstSecond, sla.WorstRequests))
+	if sla.Compliance-sla.WorstCompliance > slaDipPoints {
+		buf.WriteString("  Compliance dipped well below the overall rate during the run;\n")
+		buf.WriteString("  write -timeseries-out to see which seconds were affected.\n")
+	}
+}
+
+// writeConnectionPool 写入等待连接池分配连接的请求比例和等待时间
+func (r *StressReporter) writeConnectionPool(buf *strings.Builder, result *types.StressResult) {
+	stats := result.ConnectionPool
+	if stats == nil {
+		return
+	}
+
+	buf.WriteString("\nConnection Pool:\n")
+	buf.WriteString(fmt.Sprintf("  Waited for Connection:  %d of %d requests (%.2f%%)\n", stats.Waited, stats.Requests, stats.WaitedPercent))
+	if stats.Waited > 0 {
+		buf.WriteString(fmt.Sprintf("  Avg Wait:               %v (max %v)\n", stats.AvgWait.Round(time.Microsecond), stats.MaxWait.Round(time.Microsecond)))
+	}
+	if stats.Saturated() {
+		buf.WriteString("  Requests are queuing for connections: the pool is too small for the offered load,\n")
+		buf.WriteString("  so latency includes client-side waiting. Raise the connection pool size.\n")
+	}
+}
+
+// writeDNS 写入 -dns-servers 指定的 DNS 服务器的解析次数、耗时和失败数
+func (r *StressReporter) writeDNS(buf *strings.Builder, result *types.StressResult) {
+	stats := result.DNS
+	if stats == nil {
+		return
+	}
+
+	buf.WriteString(fmt.Sprintf("\nDNS Resolution (%s):\n", r.config.DNSServers))
+	buf.WriteString(fmt.Sprintf("  Lookups:                %d\n", stats.Lookups))
+	if stats.Lookups > 0 {
+		buf.WriteString(fmt.Sprintf("  Avg Lookup:             %v (max %v)\n", stats.AvgLookup.Round(time.Microsecond), stats.MaxLookup.Round(time.Microsecond)))
+	}
+	buf.WriteString(fmt.Sprintf("  Failures:               %d\n", stats.Failures))
+}
+
+// writeLatencyBreakdown 写入各阶段耗时，DNS、建连和 TLS 只统计新建连接的请求
+func (r *StressReporter) writeLatencyBreakdown(buf *strings.Builder, result *types.StressResult) {
+	breakdown := result.LatencyBreakdown
+	if breakdown == nil {
+		return
+	}
+
+	buf.WriteString(fmt.Sprintf("\nLatency Breakdown (%d successful requests):\n", breakdown.Requests))
+	buf.WriteString(fmt.Sprintf("  %-20s %8s %12s %12s %12s\n", "Phase", "Count", "Avg", "P50", "P99"))
+	for _, phase := range []struct {
+		name  string
+		stats types.PhaseStats
+	}{
+		{"DNS Lookup", breakdown.DNS},
+		{"TCP Connect", breakdown.Connect},
+		{"TLS Handshake", breakdown.TLS},
+		{"Time to First Byte", breakdown.TTFB},
+	} {
+		if phase.stats.Count == 0 {
+			buf.WriteString(fmt.Sprintf("  %-20s %8d %12s %12s %12s\n", phase.name, 0, "-", "-", "-"))
+			continue
+		}
+		buf.WriteString(fmt.Sprintf("  %-20s %8d %12v %12v %12v\n", phase.name, phase.stats.Count,
+			phase.stats.Avg.Round(time.Microsecond), phase.stats.P50.Round(time.Microsecond), phase.stats.P99.Round(time.Microsecond)))
+	}
+}
+
+// writeConnectionHealth 写入连接被重置、中断和保持连接被关闭的次数
+func (r *StressReporter) writeConnectionHealth(buf *strings.Builder, result *types.StressResult) {
+	health := result.ConnectionHealth
+	if health == nil {
+		return
+	}
+
+	status := "OK"
+	if health.Unhealthy() {
+		status = "UNSTABLE"
+	}
+
+	buf.WriteString(fmt.Sprintf("\nConnection Health: %s\n", status))
+	buf.WriteString(fmt.Sprintf("  New Connections:         %d\n", health.NewConnections))
+	buf.WriteString(fmt.Sprintf("  Reconnects:              %d\n", health.Reconnects))
+	buf.WriteString(fmt.Sprintf("  Connection Resets:       %d\n", health.Resets))
+	buf.WriteString(fmt.Sprintf("  Dropped Mid-Request:     %d\n", health.Dropped))
+	buf.WriteString(fmt.Sprintf("  Idle Closed by Server:   %d\n", health.IdleClosed))
+}
+
+// writeTLS 写入协商的 TLS 版本、密码套件和会话恢复率
+func (r *StressReporter) writeTLS(buf *strings.Builder, result *types.StressResult) {
+	if len(result.TLS) == 0 && result.TLSResumption == nil {
+		return
+	}
+
+	buf.WriteString("\nNegotiated TLS:\n")
+	for _, negotiation := range result.TLS {
+		buf.WriteString(fmt.Sprintf("  %-8s %-45s %d requests\n", negotiation.Version, negotiation.CipherSuite, negotiation.Requests))
+	}
+
+	if resumption := result.TLSResumption; resumption != nil {
+		buf.WriteString(fmt.Sprintf("  Handshakes:         %d (%d full, %d resumed)\n",
+			resumption.Handshakes, resumption.FullHandshakes, resumption.Resumed))
+		buf.WriteString(fmt.Sprintf("  Session Resumption: %.2f%%\n", resumption.ResumptionRate))
+	}
+}
+
+// writeCompression 写入 gzip 压缩响应的比例、压缩率和解压耗时
+func (r *StressReporter) writeCompression(buf *strings.Builder, result *types.StressResult) {
+	stats := result.Compression
+	if stats == nil {
+		return
+	}
+
+	buf.WriteString("\nResponse Compression (gzip):\n")
+	buf.WriteString(fmt.Sprintf("  Compressed Responses:   %d (%d uncompressed)\n", stats.CompressedResponses, stats.UncompressedResponses))
+	buf.WriteString(fmt.Sprintf("  Wire / Decoded Bytes:   %d / %d\n", stats.WireBytes, stats.DecodedBytes))
+	buf.WriteString(fmt.Sprintf("  Compression Ratio:      %.2fx\n", stats.Ratio))
+	buf.WriteString(fmt.Sprintf("  Avg Decompress Time:    %v (total %v)\n", stats.AvgDecompressTime, stats.TotalDecompressTime))
+}
+
+// writeBatch 写入批量请求发送的行数和成功写入的速率
+func (r *StressReporter) writeBatch(buf *strings.Builder, result *types.StressResult) {
+	stats := result.Batch
+	if stats == nil {
+		return
+	}
+
+	buf.WriteString("\nBatch Requests:\n")
+	buf.WriteString(fmt.Sprintf("  Rows Sent:              %d in %d requests\n", stats.Rows, stats.Requests))
+	buf.WriteString(fmt.Sprintf("  Rows/Request:           %.2f\n", stats.RowsPerRequest))
+	buf.WriteString(fmt.Sprintf("  Succeeded Rows:         %d (%.2f rows/sec)\n", stats.SucceededRows, stats.RowsPerSecond))
+}
+
+// writeRetries 写入重试过的请求数以及重试后的结果
+func (r *StressReporter) writeRetries(buf *strings.Builder, result *types.StressResult) {
+	stats := result.Retry
+	if stats == nil {
+		return
+	}
+
+	buf.WriteString("\nRetries:\n")
+	buf.WriteString(fmt.Sprintf("  Retried Requests:       %d (%.2f%% of requests, %d retries)\n",
+		stats.RetriedRequests, float64(stats.RetriedRequests)/float64(result.TotalRequests)*100, stats.Retries))
+	buf.WriteString(fmt.Sprintf("  Succeeded After Retry:  %d\n", stats.RetriedSucceeded))
+	buf.WriteString(fmt.Sprintf("  Failed After Retry:     %d\n", stats.RetriedRequests-stats.RetriedSucceeded))
+}
+
+// writeBurst 写入突发请求的完成时间和延迟直方图
+func (r *StressReporter) writeBurst(buf *strings.Builder, result *types.StressResult) {
+	burst := result.Burst
+	if burst == nil {
+		return
+	}
+
+	buf.WriteString(fmt.Sprintf("\nBurst (%d requests released at once):\n", burst.Size))
+	buf.WriteString(fmt.Sprintf("  First Response:      %v\n", burst.FirstResponse.Round(time.Microsecond)))
+	buf.WriteString(fmt.Sprintf("  All Completed:       %v\n", burst.Completion.Round(time.Microsecond)))
+
+	var peak int64
+	for _, bucket := range burst.Histogram {
+		peak = max(peak, bucket.Count)
+	}
+	if peak == 0 {
+		return
+	}
+
+	buf.WriteString("  Latency Histogram:\n")
+	for _, bucket := range burst.Histogram {
+		bar := strings.Repeat("#", int(bucket.Count*40/peak))
+		buf.WriteString(fmt.Sprintf("    <= %-12v %6d %s\n", bucket.UpperBound.Round(time.Microsecond), bucket.Count, bar))
+	}
+}
+
+// writeTargetResults 写入多目标压测中每个目标按自身判定条件的通过情况
+func (r *StressReporter) writeTargetResults(buf *strings.Builder, result *types.StressResult) {
+	targets := result.GetTargetStats()
+	if len(targets) == 0 {
+		return
 	}
 
-	fmt.Print(buf.String())
+	buf.WriteString("\nTarget Results:\n")
+	for _, target := range targets {
+		status := "PASS"
+		if target.FailedRequests > 0 {
+			status = "FAIL"
+		}
+		name := target.Name
+		if len(name) > 40 {
+			name = name[:37] + "..."
+		}
+		buf.WriteString(fmt.Sprintf("  [%s] %-40s requests: %d  passed: %d  failed: %d  (%.2f%%)",
+			status, name, target.TotalRequests, target.SuccessfulRequests, target.FailedRequests, target.GetSuccessRate()))
+		if target.Concurrency > 0 {
+			buf.WriteString(fmt.Sprintf("  workers: %d", target.Concurrency))
+		}
+		buf.WriteString("\n")
+	}
+}
+
+// writeComparison 写入 A/B 对比的并列统计与显著性检验结果
+func (r *StressReporter) writeComparison(buf *strings.Builder, result *types.StressResult) {
+	c := result.Comparison
+	if c == nil {
+		return
+	}
+
+	buf.WriteString("\nA/B Comparison:\n")
+	for _, target := range r.config.Targets {
+		buf.WriteString(fmt.Sprintf("  %s: %s\n", target.Name, target.URL))
+	}
+
+	buf.WriteString(fmt.Sprintf("  %-14s %14s %14s %10s\n", "", "A", "B", "B vs A"))
+	buf.WriteString(fmt.Sprintf("  %-14s %14d %14d\n", "Requests", c.A.TotalRequests, c.B.TotalRequests))
+	buf.WriteString(fmt.Sprintf("  %-14s %13.2f%% %13.2f%%\n", "Success Rate", c.A.GetSuccessRate(), c.B.GetSuccessRate()))
+	rows := []struct {
+		name  string
+		a, b  time.Duration
+		delta float64
+	}{
+		{"Avg", c.A.AvgResponseTime, c.B.AvgResponseTime, c.AvgDelta},
+		{"P50", c.A.P50ResponseTime, c.B.P50ResponseTime, c.P50Delta},
+		{"P99", c.A.P99ResponseTime, c.B.P99ResponseTime, c.P99Delta},
+	}
+	for _, row := range rows {
+		buf.WriteString(fmt.Sprintf("  %-14s %14v %14v %+9.1f%%\n",
+			row.name, row.a.Round(time.Microsecond), row.b.Round(time.Microsecond), row.delta))
+	}
+
+	buf.WriteString(fmt.Sprintf("  Mann-Whitney U: z=%.2f, p=%.4f (%s at p<%.2f)\n",
+		c.Z, c.PValue, c.Verdict(), types.DefaultSignificanceLevel))
+}
+
+// writeMethodResults 写入 CRUD 模式下每个方法的请求数与响应时间
+func (r *StressReporter) writeMethodResults(buf *strings.Builder, result *types.StressResult) {
+	methods := result.GetMethodStats()
+	if len(methods) == 0 {
+		return
+	}
+
+	buf.WriteString("\nMethod Results:\n")
+	for _, method := range methods {
+		buf.WriteString(fmt.Sprintf("  %-7s requests: %d  passed: %d  failed: %d  (%.2f%%)\n",
+			method.Method, method.TotalRequests, method.SuccessfulRequests, method.FailedRequests, method.GetSuccessRate()))
+		buf.WriteString(fmt.Sprintf("  %-7s avg: %v  p50: %v  p99: %v\n",
+			"", method.AvgResponseTime, method.P50ResponseTime, method.P99ResponseTime))
+	}
+}
+
+// writeRateSegments 写入速率计划各阶段的目标速率与实际速率
+func (r *StressReporter) writeRateSegments(buf *strings.Builder, result *types.StressResult) {
+	if len(result.RateSegments) == 0 {
+		return
+	}
+
+	buf.WriteString("\nRate Schedule:\n")
+	for i, segment := range result.RateSegments {
+		label := segment.Label
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		buf.WriteString(fmt.Sprintf("  %-9s %-10v target: %8.1f req/sec  achieved: %8.1f req/sec  (%d requests)\n",
+			label, segment.Duration.Round(time.Millisecond), segment.TargetRate, segment.AchievedRate, segment.Requests))
+		buf.WriteString(fmt.Sprintf("  %-9s %-10s avg: %v  p50: %v  p99: %v\n",
+			"", "", segment.AvgResponseTime, segment.P50ResponseTime, segment.P99ResponseTime))
+	}
+}
+
+// writeCustomMetric 写入从响应中提取的自定义指标
+func (r *StressReporter) writeCustomMetric(buf *strings.Builder, result *types.StressResult) {
+	metric := result.CustomMetric
+	if metric == nil {
+		return
+	}
+
+	buf.WriteString(fmt.Sprintf("\nCustom Metric (%s):\n", metric.Name))
+	if metric.Count == 0 {
+		buf.WriteString(fmt.Sprintf("  No samples extracted (missing: %d)\n", metric.Missing))
+		return
+	}
+
+	buf.WriteString(fmt.Sprintf("  Samples: %d (missing: %d)\n", metric.Count, metric.Missing))
+	buf.WriteString(fmt.Sprintf("  Min: %.2f  Avg: %.2f  Max: %.2f\n", metric.Min, metric.Avg, metric.Max))
+	buf.WriteString(fmt.Sprintf("  P50: %.2f  P90: %.2f  P99: %.2f\n", metric.P50, metric.P90, metric.P99))
 }
 
 // writeStatusCodes 写入状态码分布
@@ -112,10 +643,38 @@ func (r *StressReporter) writeStatusCodes(buf *strings.Builder, result *types.St
 	}
 }
 
+// writeStatusLatency 写入各状态码的响应时间，只收到一种状态码时与整体统计相同，不再输出
+func (r *StressReporter) writeStatusLatency(buf *strings.Builder, result *types.StressResult) {
+	if len(result.StatusLatency) < 2 {
+		return
+	}
+
+	buf.WriteString("\nStatus Code Latency:\n")
+	for _, status := range result.StatusLatency {
+		buf.WriteString(fmt.Sprintf("  %d: %d requests  p50: %v  p99: %v\n",
+			status.StatusCode, status.Count, status.P50ResponseTime, status.P99ResponseTime))
+	}
+}
+
 // writeErrorDistribution 写入错误分布
 func (r *StressReporter) writeErrorDistribution(buf *strings.Builder, result *types.StressResult) {
 	errorList, totalErrors := result.GetSortedErrors()
 
+	// 错误分类
+	if categories := result.GetErrorCategories(); len(categories) > 0 {
+		names := make([]string, 0, len(categories))
+		for name := range categories {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		buf.WriteString("\nError Categories:\n")
+		for _, name := range names {
+			percentage := float64(categories[name]) / float64(totalErrors) * 100
+			buf.WriteString(fmt.Sprintf("  %s: %d (%.2f%%)\n", name, categories[name], percentage))
+		}
+	}
+
 	if totalErrors > 0 {
 		buf.WriteString(fmt.Sprintf("\nError Distribution (Total: %d):\n", totalErrors))
 
@@ -131,25 +690,99 @@ func (r *StressReporter) writeErrorDistribution(buf *strings.Builder, result *ty
 	}
 }
 
+// writeFailFast 写入触发快速失败的请求详情
+func (r *StressReporter) writeFailFast(buf *strings.Builder, result *types.StressResult) {
+	failed := result.GetFailFast()
+	if failed == nil {
+		return
+	}
+
+	buf.WriteString("\nStopped by -fail-fast on:\n")
+	buf.WriteString(fmt.Sprintf("  Time:     %s\n", failed.Timestamp.Format(time.RFC3339Nano)))
+	if failed.URL != "" {
+		buf.WriteString(fmt.Sprintf("  Request:  %s %s\n", failed.Method, failed.URL))
+	} else if failed.Method != "" {
+		buf.WriteString(fmt.Sprintf("  Method:   %s\n", failed.Method))
+	}
+	if failed.StatusCode != 0 {
+		buf.WriteString(fmt.Sprintf("  Status:   %d\n", failed.StatusCode))
+	}
+	buf.WriteString(fmt.Sprintf("  Error:    %s\n", failed.Error))
+	buf.WriteString(fmt.Sprintf("  Duration: %v\n", failed.Duration))
+	if failed.CSVData != nil {
+		buf.WriteString(fmt.Sprintf("  CSV Data: %v\n", failed.CSVData))
+	}
+}
+
 // generateJSONReport 生成 JSON 报告
 func (r *StressReporter) generateJSONReport(result *types.StressResult) error {
+	slo := result.EvaluateSLOs(r.config.SLOThresholds())
 	report := struct {
-		Config  *config.Config         `json:"config"`
-		Result  *types.StressResult    `json:"result"`
-		Summary map[string]interface{} `json:"summary"`
+		Config   *config.Config         `json:"config"`
+		Result   *redactedResult        `json:"result"`
+		Summary  map[string]interface{} `json:"summary"`
+		SLO      types.SLOReport        `json:"slo"`
+		TimeUnit string                 `json:"time_unit,omitempty"`
+		types.ExitStatus
 	}{
-		Config: r.config,
-		Result: result,
+		Config: r.config.Redacted(),
+		Result: r.redactResult(result),
 		Summary: map[string]interface{}{
-			"requests_per_second":   result.GetRequestsPerSecond(),
-			"success_rate":          result.GetSuccessRate(),
-			"average_response_time": result.GetAverageResponseTime().String(),
-			"min_response_time":     result.GetMinResponseTime().String(),
-			"max_response_time":     result.GetMaxResponseTime().String(),
-			"p50_response_time":     result.P50ResponseTime.String(),
-			"p90_response_time":     result.P90ResponseTime.String(),
-			"p99_response_time":     result.P99ResponseTime.String(),
+			"requests_per_second":     result.GetRequestsPerSecond(),
+			"success_rate":            result.GetSuccessRate(),
+			"average_response_time":   r.jsonDuration(result.GetAverageResponseTime()),
+			"min_response_time":       r.jsonDuration(result.GetMinResponseTime()),
+			"max_response_time":       r.jsonDuration(result.GetMaxResponseTime()),
+			"p50_response_time":       r.jsonDuration(result.P50ResponseTime),
+			"p90_response_time":       r.jsonDuration(result.P90ResponseTime),
+			"p99_response_time":       r.jsonDuration(result.P99ResponseTime),
+			"stddev_response_time":    r.jsonDuration(result.StdDevResponseTime),
+			"requests_per_connection": result.GetRequestsPerConnection(),
+			"timeout_rate":            result.GetTimeoutRate(),
 		},
+		SLO:      slo,
+		TimeUnit: r.config.TimeUnit,
+		// 与进程退出码保持一致，便于 CI 解析失败原因
+		ExitStatus: result.ExitStatus(slo),
+	}
+	if percentiles := result.GetPercentiles(); percentiles != nil {
+		// 数组保留配置顺序
+		values := make([]map[string]interface{}, 0, len(percentiles))
+		for _, p := range percentiles {
+			values = append(values, map[string]interface{}{
+				"percentile":    p.Percentile,
+				"response_time": r.jsonDuration(p.ResponseTime),
+			})
+		}
+		report.Summary["percentiles"] = values
+	}
+	if result.Trimmed != nil {
+		report.Summary["trimmed_average_response_time"] = r.jsonDuration(result.Trimmed.AvgResponseTime)
+	}
+	if result.Corrected != nil {
+		report.Summary["corrected_p99_response_time"] = r.jsonDuration(result.Corrected.P99ResponseTime)
+	}
+	if result.DroppedArrivals > 0 {
+		report.Summary["dropped_arrivals"] = result.DroppedArrivals
+	}
+	if breakdown := result.LatencyBreakdown; breakdown != nil {
+		report.Summary["avg_dns_lookup_duration"] = r.jsonDuration(breakdown.DNS.Avg)
+		report.Summary["avg_tcp_connect_duration"] = r.jsonDuration(breakdown.Connect.Avg)
+		report.Summary["avg_tls_handshake_duration"] = r.jsonDuration(breakdown.TLS.Avg)
+		report.Summary["avg_ttfb_duration"] = r.jsonDuration(breakdown.TTFB.Avg)
+	}
+	if result.RateLimit != nil {
+		report.Summary["target_rate"] = result.RateLimit.TargetRate
+	}
+	report.Summary["data_received"] = r.formatter.FormatBytes(result.TotalBytesReceived)
+	report.Summary["received_mb_per_second"] = result.GetBytesReceivedPerSecond() / bytesPerMB
+	if result.TotalBytesSent > 0 {
+		report.Summary["data_sent"] = r.formatter.FormatBytes(result.TotalBytesSent)
+		report.Summary["sent_mb_per_second"] = result.GetBytesSentPerSecond() / bytesPerMB
+	}
+	if result.SLACompliance != nil {
+		report.Summary["sla_compliance"] = result.SLACompliance.Compliance
+		report.Summary["worst_second_sla_compliance"] = result.SLACompliance.WorstCompliance
 	}
 
 	jsonData, err := json.MarshalIndent(report, "", "  ")
@@ -157,14 +790,116 @@ func (r *StressReporter) generateJSONReport(result *types.StressResult) error {
 		return err
 	}
 
+	if unit := r.config.TimeUnitDuration(); unit > 0 {
+		if jsonData, err = convertDurations(jsonData, unit); err != nil {
+			return err
+		}
+	}
+
 	if r.config.OutputFile != "" {
 		return os.WriteFile(r.config.OutputFile, jsonData, 0644)
 	}
 
-	fmt.Println(string(jsonData))
+	fmt.Fprintln(r.out, string(jsonData))
 	return nil
 }
 
+// redactedResult 写入 JSON 报告的结果：外层的同名字段在编码时覆盖 StressResult 中的字段，
+// 其中请求记录的 URL 已替换敏感信息
+type redactedResult struct {
+	*types.StressResult
+	DetailedResults []*types.RequestResult          `json:"detailed_results,omitempty"`
+	ErrorExamples   map[string]*types.RequestResult `json:"error_examples,omitempty"`
+	FailFast        *types.RequestResult            `json:"fail_fast,omitempty"`
+}
+
+// redactResult 按配置的敏感模式替换结果中各请求记录的 URL，原结果不受影响
+func (r *StressReporter) redactResult(result *types.StressResult) *redactedResult {
+	redactURL := r.config.URLRedactor()
+	redact := func(request *types.RequestResult) *types.RequestResult {
+		if request == nil || request.URL == "" {
+			return request
+		}
+		redacted := *request
+		redacted.URL = redactURL(request.URL)
+		return &redacted
+	}
+
+	redacted := &redactedResult{
+		StressResult: result,
+		FailFast:     redact(result.GetFailFast()),
+	}
+	if result.DetailedResults != nil {
+		redacted.DetailedResults = make([]*types.RequestResult, len(result.DetailedResults))
+		for i, request := range result.DetailedResults {
+			redacted.DetailedResults[i] = redact(request)
+		}
+	}
+	if result.ErrorExamples != nil {
+		redacted.ErrorExamples = make(map[string]*types.RequestResult, len(result.ErrorExamples))
+		for errorMsg, request := range result.ErrorExamples {
+			redacted.ErrorExamples[errorMsg] = redact(request)
+		}
+	}
+	return redacted
+}
+
+// jsonDuration 返回 JSON 摘要中的时长，指定时间单位时保留原始值交由 convertDurations 统一换算
+func (r *StressReporter) jsonDuration(d time.Duration) interface{} {
+	if r.config.TimeUnitDuration() > 0 {
+		return d
+	}
+	return d.String()
+}
+
+// convertDurations 将 JSON 中以纳秒表示的时长字段换算为指定单位的数值
+func convertDurations(data []byte, unit time.Duration) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var report interface{}
+	if err := decoder.Decode(&report); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(convertDurationValue(report, "", unit), "", "  ")
+}
+
+// convertDurationValue 递归换算时长字段
+func convertDurationValue(value interface{}, key string, unit time.Duration) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = convertDurationValue(child, k, unit)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = convertDurationValue(child, key, unit)
+		}
+		return v
+	case json.Number:
+		if !isDurationKey(key) {
+			return v
+		}
+		ns, err := v.Int64()
+		if err != nil {
+			return v
+		}
+		return float64(ns) / float64(unit)
+	default:
+		return v
+	}
+}
+
+// isDurationKey 判断 JSON 字段是否为 time.Duration 序列化得到的时长
+func isDurationKey(key string) bool {
+	return key == "duration" || key == "timeout" || key == "response_time" ||
+		strings.HasSuffix(key, "_duration") ||
+		strings.HasSuffix(key, "_response_time") ||
+		strings.HasSuffix(key, "_interval")
+}
+
 // generateHTMLReport 生成 HTML 报告
 func (r *StressReporter) generateHTMLReport(result *types.StressResult) error {
 
@@ -203,10 +938,79 @@ func (r *StressReporter) generateHTMLReport(result *types.StressResult) error {
 		return os.WriteFile(r.config.OutputFile, []byte(htmlContent.String()), 0644)
 	}
 
-	fmt.Println(htmlContent.String())
+	fmt.Fprintln(r.out, htmlContent.String())
 	return nil
 }
 
+// WriteTimeSeries 将按秒统计的数据写为两列 CSV（秒, 指标），便于 gnuplot 或表格软件绘图。
+// 设置 -sla 时追加一列该秒的 SLA 达标百分比，没有请求的秒留空
+func (r *StressReporter) WriteTimeSeries(result *types.StressResult, filename string) error {
+	metric := r.config.TimeSeriesMetric
+	if metric == "" {
+		metric = types.DefaultTimeSeriesMetric
+	}
+
+	column := metric
+	if metric != "rps" && metric != "errors" {
+		column = metric + "_ms"
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create time series file: %v", err)
+	}
+	defer file.Close()
+
+	header := []string{"second", column}
+	sla := r.config.LatencySLA > 0
+	if sla {
+		header = append(header, "sla_compliance_pct")
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, point := range result.GetTimeSeries() {
+		var value string
+		switch metric {
+		case "rps":
+			value = strconv.FormatInt(point.Requests, 10)
+		case "errors":
+			value = strconv.FormatInt(point.Errors, 10)
+		case "p50":
+			value = formatMillis(point.P50ResponseTime)
+		case "p90":
+			value = formatMillis(point.P90ResponseTime)
+		default:
+			value = formatMillis(point.P99ResponseTime)
+		}
+		record := []string{strconv.Itoa(point.Second), value}
+		if sla {
+			compliance := ""
+			if point.Requests > 0 {
+				compliance = strconv.FormatFloat(point.SLACompliance(), 'f', 2, 64)
+			}
+			record = append(record, compliance)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// formatMillis 将时长格式化为毫秒数值
+func formatMillis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
+
 // SaveReport 保存报告到文件
 func (r *StressReporter) SaveReport(result *types.StressResult, filename string) error {
 	return r.generateJSONReport(result)
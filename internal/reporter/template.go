@@ -0,0 +1,93 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+)
+
+// TemplateData 自定义报告模板可访问的数据。Result 的导出字段和方法（如 GetSuccessRate、
+// GetSortedStatusCodes）都可以在模板中直接使用
+type TemplateData struct {
+	Config      *config.Config
+	Result      *types.StressResult
+	SLO         types.SLOReport
+	ExitStatus  types.ExitStatus
+	GeneratedAt time.Time
+}
+
+// templateFuncs 报告模板可用的辅助函数
+var templateFuncs = template.FuncMap{
+	// ms 将时长转换为毫秒数
+	"ms": func(d time.Duration) float64 {
+		return float64(d) / float64(time.Millisecond)
+	},
+	// seconds 将时长转换为秒数
+	"seconds": func(d time.Duration) float64 {
+		return d.Seconds()
+	},
+	// percent 计算 part 占 total 的百分比
+	"percent": func(part, total int64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(part) / float64(total) * 100
+	},
+	// json 将任意值编码为 JSON
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// LoadTemplate 读取并解析报告模板，在压测开始前调用以便尽早发现模板错误
+func (r *StressReporter) LoadTemplate(filename string) error {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read report template: %v", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(filename)).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return fmt.Errorf("failed to parse report template: %v", err)
+	}
+	r.template = tmpl
+	return nil
+}
+
+// generateTemplateReport 使用 -report-template 渲染报告，未指定输出文件时输出到控制台
+func (r *StressReporter) generateTemplateReport(result *types.StressResult) error {
+	if r.template == nil {
+		if err := r.LoadTemplate(r.config.ReportTemplate); err != nil {
+			return err
+		}
+	}
+
+	slo := result.EvaluateSLOs(r.config.SLOThresholds())
+	data := TemplateData{
+		Config:      r.config.Redacted(),
+		Result:      result,
+		SLO:         slo,
+		ExitStatus:  result.ExitStatus(slo),
+		GeneratedAt: time.Now(),
+	}
+
+	// 先完整渲染，模板执行出错时不留下不完整的文件
+	var buf bytes.Buffer
+	if err := r.template.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to render report template: %v", err)
+	}
+
+	if r.config.OutputFile != "" {
+		return os.WriteFile(r.config.OutputFile, buf.Bytes(), 0644)
+	}
+	_, err := r.out.Write(buf.Bytes())
+	return err
+}
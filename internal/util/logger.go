@@ -2,10 +2,13 @@ package util
 
 import (
 	"bufio"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -19,6 +22,8 @@ const (
 	defaultFlushInterval = 5 * time.Second
 	// 默认日志文件大小限制 (100MB)
 	defaultMaxFileSize = 100 * 1024 * 1024
+	// 输出被重定向时按行输出进度的间隔
+	lineProgressInterval = 10 * time.Second
 )
 
 // Logger 日志记录器
@@ -30,15 +35,37 @@ type Logger struct {
 	mu             sync.RWMutex
 	lastLineLength int
 
+	// 控制台输出，进度与报告共用同一把锁避免输出交错
+	out       io.Writer
+	consoleMu sync.Mutex
+
+	// 输出被重定向到文件或管道时不使用回车覆盖进度行，而是定期输出一行
+	lineProgress     bool
+	lastLineProgress time.Time
+
+	// 未开启 verbose 时也显示进度，用于 -dashboard 在非终端下的回退输出
+	progress bool
+
+	// 多行面板当前占用的行数，重绘和清除时据此把光标移回面板首行
+	panelLines int
+
 	// 文件轮转相关
 	logFilePath string
 	maxFileSize int64
 	currentSize int64
+	bufferSize  int
+	maxBackups  int
+
+	// 轮转文件压缩
+	compressMu sync.Mutex
+	compressWg sync.WaitGroup
 
 	// 异步日志支持
 	asyncQueue chan string
+	asyncFlush chan chan struct{}
 	asyncWg    sync.WaitGroup
 	asyncStop  chan struct{}
+	closeOnce  sync.Once
 
 	// 定期刷新
 	flushTicker *time.Ticker
@@ -53,6 +80,9 @@ type LoggerOptions struct {
 	BufferSize    int
 	FlushInterval time.Duration
 	MaxFileSize   int64
+	MaxBackups    int       // 保留的压缩备份数量，0 表示不限制
+	Output        io.Writer // 控制台输出，默认为标准输出
+	Progress      bool      // 未开启 verbose 时也显示进度
 }
 
 // NewLogger 创建日志记录器
@@ -71,20 +101,40 @@ func NewLogger(verbose bool, logFile string) (*Logger, error) {
 func NewLoggerWithOptions(opts LoggerOptions) (*Logger, error) {
 	logger := &Logger{
 		verbose:     opts.Verbose,
+		progress:    opts.Progress,
 		asyncQueue:  make(chan string, 1000),
+		asyncFlush:  make(chan chan struct{}),
 		asyncStop:   make(chan struct{}),
 		flushStop:   make(chan struct{}),
 		logFilePath: opts.LogFile,
 		maxFileSize: opts.MaxFileSize,
+		bufferSize:  opts.BufferSize,
+		maxBackups:  opts.MaxBackups,
+		out:         opts.Output,
+	}
+
+	if logger.out == nil {
+		logger.out = os.Stdout
+	}
+	logger.lineProgress = IsRedirected(logger.out)
+
+	if logger.bufferSize <= 0 {
+		logger.bufferSize = defaultBufferSize
+	}
+	if logger.maxFileSize <= 0 {
+		logger.maxFileSize = defaultMaxFileSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushInterval
 	}
 
 	// 初始化日志输出
 	if opts.LogFile != "" {
-		if err := logger.initFileLogging(opts.LogFile, opts.BufferSize); err != nil {
+		if err := logger.initFileLogging(opts.LogFile, logger.bufferSize); err != nil {
 			return nil, err
 		}
 	} else {
-		logger.logger = log.New(os.Stdout, "", log.LstdFlags)
+		logger.logger = log.New(logger.Console(), "", log.LstdFlags)
 	}
 
 	// 启动异步日志处理
@@ -133,36 +183,49 @@ func (l *Logger) processAsyncLogs() {
 		select {
 		case msg := <-l.asyncQueue:
 			l.writeLog(msg)
+		case done := <-l.asyncFlush:
+			l.drainAsyncQueue()
+			l.flush()
+			close(done)
 		case <-l.asyncStop:
 			// 处理剩余日志
-			for {
-				select {
-				case msg := <-l.asyncQueue:
-					l.writeLog(msg)
-				default:
-					return
-				}
-			}
+			l.drainAsyncQueue()
+			return
+		}
+	}
+}
+
+// drainAsyncQueue 写入队列中剩余的日志
+func (l *Logger) drainAsyncQueue() {
+	for {
+		select {
+		case msg := <-l.asyncQueue:
+			l.writeLog(msg)
+		default:
+			return
 		}
 	}
 }
 
 // writeLog 写入日志（线程安全）
+// 写入与轮转共用写锁，保证轮转替换 writer 时不会与并发写入交错
 func (l *Logger) writeLog(msg string) {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.logger == nil {
+		return
+	}
 
-	if l.logger != nil {
-		l.logger.Print(msg)
+	l.logger.Print(msg)
 
-		// 更新文件大小
-		if l.writer != nil {
-			atomic.AddInt64(&l.currentSize, int64(len(msg)+1)) // +1 换行符
+	// 更新文件大小
+	if l.writer != nil {
+		size := atomic.AddInt64(&l.currentSize, int64(len(msg)+1)) // +1 换行符
 
-			// 检查是否需要轮转
-			if atomic.LoadInt64(&l.currentSize) > l.maxFileSize {
-				go l.rotateLog()
-			}
+		// 检查是否需要轮转
+		if size > l.maxFileSize {
+			l.rotateLog()
 		}
 	}
 }
@@ -193,34 +256,102 @@ func (l *Logger) flush() {
 	}
 }
 
-// rotateLog 日志轮转
+// rotateLog 日志轮转，调用方必须持有 l.mu 写锁
 func (l *Logger) rotateLog() {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	if l.logFilePath == "" || l.file == nil {
+		return
+	}
 
 	// 关闭当前文件
-	if l.file != nil {
-		if l.writer != nil {
-			l.writer.Flush()
-		}
-		l.file.Close()
+	if l.writer != nil {
+		l.writer.Flush()
 	}
+	l.file.Close()
+	l.file = nil
+	l.writer = nil
 
 	// 重命名当前日志文件
-	if l.logFilePath != "" {
-		timestamp := time.Now().Format("20060102-150405")
-		backupPath := l.logFilePath + "." + timestamp
-		os.Rename(l.logFilePath, backupPath)
+	timestamp := time.Now().Format("20060102-150405.000000000")
+	backupPath := l.logFilePath + "." + timestamp
+	if err := os.Rename(l.logFilePath, backupPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to rename log file: %v\n", err)
+	} else {
+		// 压缩在后台进行，避免阻塞日志写入
+		l.compressWg.Add(1)
+		go l.compressBackup(backupPath)
 	}
 
 	// 创建新文件
-	if err := l.initFileLogging(l.logFilePath, l.writer.Size()); err != nil {
+	atomic.StoreInt64(&l.currentSize, 0)
+	if err := l.initFileLogging(l.logFilePath, l.bufferSize); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to rotate log file: %v\n", err)
-		// 降级到标准输出
-		l.logger = log.New(os.Stdout, "", log.LstdFlags)
-		l.writer = nil
-		l.file = nil
+		// 降级到控制台输出
+		l.logger = log.New(l.Console(), "", log.LstdFlags)
+	}
+}
+
+// compressBackup 使用 gzip 压缩轮转后的日志文件并清理超出保留数量的旧备份
+func (l *Logger) compressBackup(backupPath string) {
+	defer l.compressWg.Done()
+
+	l.compressMu.Lock()
+	defer l.compressMu.Unlock()
+
+	if err := gzipFile(backupPath, backupPath+".gz"); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to compress log file: %v\n", err)
+		return
 	}
+	os.Remove(backupPath)
+
+	l.pruneBackups()
+}
+
+// pruneBackups 删除最旧的压缩备份，只保留 maxBackups 个
+func (l *Logger) pruneBackups() {
+	if l.maxBackups <= 0 {
+		return
+	}
+
+	matches, err := filepath.Glob(l.logFilePath + ".*.gz")
+	if err != nil || len(matches) <= l.maxBackups {
+		return
+	}
+
+	// 备份文件名包含时间戳，按名称排序即按时间排序
+	sort.Strings(matches)
+	for _, path := range matches[:len(matches)-l.maxBackups] {
+		if err := os.Remove(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to remove old log backup: %v\n", err)
+		}
+	}
+}
+
+// gzipFile 将 src 压缩写入 dst
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		gz.Close()
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
 }
 
 // logAsync 异步记录日志
@@ -254,7 +385,7 @@ func (l *Logger) Error(format string, args ...interface{}) {
 
 // Progress 显示进度
 func (l *Logger) Progress(current, total int64, startTime time.Time, instantRPS float64, remaining time.Duration) {
-	if !l.verbose {
+	if !l.verbose && !l.progress {
 		return
 	}
 
@@ -265,26 +396,141 @@ func (l *Logger) Progress(current, total int64, startTime time.Time, instantRPS
 	// 构建固定格式的进度信息
 	var progressStr string
 	if remaining <= 0 {
-		progressStr = fmt.Sprintf("\rProgress: %d/%d (%5.1f%%) - %6.1f req/sec - Instant: %6.1f req/sec - Elapsed: %v",
+		progressStr = fmt.Sprintf("Progress: %d/%d (%5.1f%%) - %6.1f req/sec - Instant: %6.1f req/sec - Elapsed: %v",
 			current, total, percent, rps, instantRPS, elapsed.Round(time.Second))
 	} else {
-		progressStr = fmt.Sprintf("\rProgress: %d/%d (%5.1f%%) - %6.1f req/sec - Elapsed: %v - Remaining: %v",
+		progressStr = fmt.Sprintf("Progress: %d/%d (%5.1f%%) - %6.1f req/sec - Elapsed: %v - Remaining: %v",
 			current, total, percent, rps, elapsed.Round(time.Second), remaining.Round(time.Second))
 	}
 
+	l.consoleMu.Lock()
+	defer l.consoleMu.Unlock()
+
+	// 输出被重定向时回车无法覆盖上一行，改为定期输出完整的一行，按时长测试时 total 为 0
+	if l.lineProgress {
+		done := total > 0 && current >= total
+		if !done && time.Since(l.lastLineProgress) < lineProgressInterval {
+			return
+		}
+		l.lastLineProgress = time.Now()
+		fmt.Fprintln(l.out, progressStr)
+		return
+	}
+	progressStr = "\r" + progressStr
+
 	// 清理行尾并输出
-	fmt.Print(progressStr + strings.Repeat(" ", max(0, l.lastLineLength-len(progressStr))))
+	fmt.Fprint(l.out, progressStr+strings.Repeat(" ", max(0, l.lastLineLength-len(progressStr))))
 	l.lastLineLength = len(progressStr)
 
 	// 完成后换行
 	if current >= total {
-		fmt.Println()
+		fmt.Fprintln(l.out)
 		l.lastLineLength = 0
 	}
 }
 
+// Status 在进度行位置显示一行状态，不受 verbose 限制，用于交互模式的实时面板
+func (l *Logger) Status(line string) {
+	line = "\r" + line
+
+	l.consoleMu.Lock()
+	defer l.consoleMu.Unlock()
+
+	fmt.Fprint(l.out, line+strings.Repeat(" ", max(0, l.lastLineLength-len(line))))
+	l.lastLineLength = len(line)
+}
+
+// Panel 使用 ANSI 光标控制原地重绘多行面板，用于 -dashboard 实时面板。
+// 输出被重定向时无法重绘，返回 false 由调用方回退为普通进度输出
+func (l *Logger) Panel(lines []string) bool {
+	if l.lineProgress {
+		return false
+	}
+
+	l.consoleMu.Lock()
+	defer l.consoleMu.Unlock()
+
+	var b strings.Builder
+	l.rewindPanel(&b)
+	// 光标停在面板最后一行的行尾，下次重绘时向上回退 len(lines)-1 行
+	b.WriteString(strings.Join(lines, "\n"))
+	fmt.Fprint(l.out, b.String())
+	l.panelLines = len(lines)
+	return true
+}
+
+// rewindPanel 把光标移回面板首行行首并清除到屏幕末尾，调用方必须持有 l.consoleMu
+func (l *Logger) rewindPanel(b *strings.Builder) {
+	if l.panelLines == 0 {
+		return
+	}
+	if l.panelLines > 1 {
+		fmt.Fprintf(b, "\x1b[%dA", l.panelLines-1)
+	}
+	b.WriteString("\r\x1b[J")
+	l.panelLines = 0
+}
+
+// ClearProgress 清除未结束的进度行，使后续输出从干净的行首开始
+func (l *Logger) ClearProgress() {
+	l.consoleMu.Lock()
+	defer l.consoleMu.Unlock()
+	l.clearProgressLocked()
+}
+
+// clearProgressLocked 清除进度行和多行面板，调用方必须持有 l.consoleMu
+func (l *Logger) clearProgressLocked() {
+	if l.panelLines > 0 {
+		var b strings.Builder
+		l.rewindPanel(&b)
+		fmt.Fprint(l.out, b.String())
+	}
+	if l.lastLineLength == 0 {
+		return
+	}
+	fmt.Fprint(l.out, "\r"+strings.Repeat(" ", l.lastLineLength)+"\r")
+	l.lastLineLength = 0
+}
+
+// Console 返回与进度显示共享锁的控制台 writer，写入前会先清除进度行
+func (l *Logger) Console() io.Writer {
+	return consoleWriter{l: l}
+}
+
+// consoleWriter 串行化控制台输出
+type consoleWriter struct {
+	l *Logger
+}
+
+func (w consoleWriter) Write(p []byte) (int, error) {
+	w.l.consoleMu.Lock()
+	defer w.l.consoleMu.Unlock()
+	w.l.clearProgressLocked()
+	return w.l.out.Write(p)
+}
+
+// Flush 等待异步队列中已提交的日志全部写入并刷新缓冲区
+func (l *Logger) Flush() {
+	done := make(chan struct{})
+	select {
+	case l.asyncFlush <- done:
+		<-done
+	case <-l.asyncStop:
+		// 已关闭，Close 会处理剩余日志
+	}
+}
+
 // Close 关闭日志记录器
 func (l *Logger) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		err = l.close()
+	})
+	return err
+}
+
+// close 执行实际的关闭逻辑
+func (l *Logger) close() error {
 	// 停止异步处理
 	close(l.asyncStop)
 	l.asyncWg.Wait()
@@ -299,6 +545,9 @@ func (l *Logger) Close() error {
 	// 最终刷新缓冲区
 	l.flush()
 
+	// 等待轮转文件压缩完成
+	l.compressWg.Wait()
+
 	// 关闭文件
 	if l.file != nil {
 		return l.file.Close()
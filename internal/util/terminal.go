@@ -0,0 +1,18 @@
+package util
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// IsRedirected 判断 w 是否为被重定向到文件或管道的输出。
+// 只检查 *os.File，其他 writer（如测试中的缓冲区）由调用方决定输出格式，返回 false
+func IsRedirected(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	return !term.IsTerminal(int(file.Fd()))
+}
@@ -0,0 +1,44 @@
+package types
+
+import "sync/atomic"
+
+// BatchStats -batch-size 批量请求的统计：每个请求将多行 CSV 数据作为 JSON 数组发送
+type BatchStats struct {
+	Requests       int64   `json:"requests"`       // 携带批量请求体的请求数
+	Rows           int64   `json:"rows"`           // 发送的行数
+	SucceededRows  int64   `json:"succeeded_rows"` // 成功请求中的行数
+	RowsPerRequest float64 `json:"rows_per_request"`
+	RowsPerSecond  float64 `json:"rows_per_second"` // 成功写入的行数除以测试时长
+}
+
+// addBatchResult 统计批量请求发送和成功的行数
+func (sr *StressResult) addBatchResult(result *RequestResult) {
+	if result.BatchRows == 0 {
+		return
+	}
+
+	atomic.AddInt64(&sr.batchRequests, 1)
+	atomic.AddInt64(&sr.batchRows, int64(result.BatchRows))
+	if result.Success {
+		atomic.AddInt64(&sr.batchSucceeded, int64(result.BatchRows))
+	}
+}
+
+// GetBatchStats 汇总批量请求统计，没有批量请求时返回 nil
+func (sr *StressResult) GetBatchStats() *BatchStats {
+	requests := atomic.LoadInt64(&sr.batchRequests)
+	if requests == 0 {
+		return nil
+	}
+
+	stats := &BatchStats{
+		Requests:      requests,
+		Rows:          atomic.LoadInt64(&sr.batchRows),
+		SucceededRows: atomic.LoadInt64(&sr.batchSucceeded),
+	}
+	stats.RowsPerRequest = float64(stats.Rows) / float64(requests)
+	if sr.TotalDuration > 0 {
+		stats.RowsPerSecond = float64(stats.SucceededRows) / sr.TotalDuration.Seconds()
+	}
+	return stats
+}
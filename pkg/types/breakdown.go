@@ -0,0 +1,88 @@
+package types
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// PhaseStats 请求某一阶段的耗时统计，只统计经历了该阶段的请求
+type PhaseStats struct {
+	Count int64         `json:"count"`
+	Avg   time.Duration `json:"avg_duration"`
+	P50   time.Duration `json:"p50_duration"`
+	P99   time.Duration `json:"p99_duration"`
+}
+
+// LatencyBreakdown -latency-breakdown 的延迟分解，用于区分慢在 DNS、建连、TLS 还是服务端处理。
+// DNS、建连和 TLS 只在新建连接时发生；首字节耗时为获得连接到收到响应首字节的时间
+type LatencyBreakdown struct {
+	Requests int64      `json:"requests"` // 参与统计的成功请求数
+	DNS      PhaseStats `json:"dns_lookup"`
+	Connect  PhaseStats `json:"tcp_connect"`
+	TLS      PhaseStats `json:"tls_handshake"`
+	TTFB     PhaseStats `json:"time_to_first_byte"`
+}
+
+// EnableLatencyBreakdown 开启延迟分解统计，需在压测开始前调用
+func (sr *StressResult) EnableLatencyBreakdown() {
+	atomic.StoreInt32(&sr.breakdownOn, 1)
+}
+
+// GetLatencyBreakdown 由保留的详细记录中的成功请求汇总延迟分解，与分位数使用相同的样本，未开启时返回 nil
+func (sr *StressResult) GetLatencyBreakdown() *LatencyBreakdown {
+	if atomic.LoadInt32(&sr.breakdownOn) == 0 {
+		return nil
+	}
+
+	var dns, connect, tlsHandshake, ttfb []time.Duration
+	sr.resultsLock.RLock()
+	for _, result := range sr.DetailedResults {
+		if !result.Success {
+			continue
+		}
+		dns = appendPhase(dns, result.DNSLookup)
+		connect = appendPhase(connect, result.ConnectTime)
+		tlsHandshake = appendPhase(tlsHandshake, result.TLSHandshake)
+		ttfb = appendPhase(ttfb, result.ServerTime)
+	}
+	sr.resultsLock.RUnlock()
+
+	return &LatencyBreakdown{
+		Requests: int64(len(ttfb)),
+		DNS:      phaseStats(dns),
+		Connect:  phaseStats(connect),
+		TLS:      phaseStats(tlsHandshake),
+		TTFB:     phaseStats(ttfb),
+	}
+}
+
+// appendPhase 只记录实际发生的阶段，复用连接时 DNS、建连和 TLS 耗时为 0
+func appendPhase(durations []time.Duration, d time.Duration) []time.Duration {
+	if d <= 0 {
+		return durations
+	}
+	return append(durations, d)
+}
+
+// phaseStats 计算一个阶段的平均值和分位数
+func phaseStats(durations []time.Duration) PhaseStats {
+	if len(durations) == 0 {
+		return PhaseStats{}
+	}
+
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return PhaseStats{
+		Count: int64(len(durations)),
+		Avg:   total / time.Duration(len(durations)),
+		P50:   calculatePercentile(durations, 0.50),
+		P99:   calculatePercentile(durations, 0.99),
+	}
+}
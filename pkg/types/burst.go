@@ -0,0 +1,73 @@
+package types
+
+import "time"
+
+// burstHistogramBuckets 突发模式延迟直方图的桶数
+const burstHistogramBuckets = 10
+
+// LatencyBucket 延迟直方图中的一个桶，UpperBound 为桶的延迟上界
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      int64         `json:"count"`
+}
+
+// BurstStats 突发模式下同时释放的一批请求的统计
+type BurstStats struct {
+	Size          int             `json:"size"`
+	ReleasedAt    time.Time       `json:"released_at"`
+	FirstResponse time.Duration   `json:"first_response"`  // 从释放到第一个请求完成
+	Completion    time.Duration   `json:"completion_time"` // 从释放到所有请求完成
+	Histogram     []LatencyBucket `json:"histogram"`
+}
+
+// StartBurst 记录突发请求的释放时间，需在请求发出前调用
+func (sr *StressResult) StartBurst(size int, releasedAt time.Time) {
+	sr.burst = &BurstStats{Size: size, ReleasedAt: releasedAt}
+}
+
+// calculateBurst 根据详细结果计算突发请求的完成时间和延迟直方图
+func (sr *StressResult) calculateBurst() *BurstStats {
+	if sr.burst == nil {
+		return nil
+	}
+
+	sr.resultsLock.RLock()
+	defer sr.resultsLock.RUnlock()
+
+	stats := *sr.burst
+	if len(sr.DetailedResults) == 0 {
+		return &stats
+	}
+
+	minLatency, maxLatency := sr.DetailedResults[0].Duration, sr.DetailedResults[0].Duration
+	for i, result := range sr.DetailedResults {
+		elapsed := result.Timestamp.Sub(stats.ReleasedAt)
+		if i == 0 || elapsed < stats.FirstResponse {
+			stats.FirstResponse = elapsed
+		}
+		stats.Completion = max(stats.Completion, elapsed)
+		minLatency = min(minLatency, result.Duration)
+		maxLatency = max(maxLatency, result.Duration)
+	}
+
+	// 在最小与最大延迟之间等宽分桶
+	width := (maxLatency - minLatency) / burstHistogramBuckets
+	if width <= 0 {
+		stats.Histogram = []LatencyBucket{{UpperBound: maxLatency, Count: int64(len(sr.DetailedResults))}}
+		return &stats
+	}
+
+	stats.Histogram = make([]LatencyBucket, burstHistogramBuckets)
+	for i := range stats.Histogram {
+		stats.Histogram[i].UpperBound = minLatency + width*time.Duration(i+1)
+	}
+	stats.Histogram[burstHistogramBuckets-1].UpperBound = maxLatency
+	for _, result := range sr.DetailedResults {
+		i := int((result.Duration - minLatency) / width)
+		if i >= burstHistogramBuckets {
+			i = burstHistogramBuckets - 1
+		}
+		stats.Histogram[i].Count++
+	}
+	return &stats
+}
@@ -0,0 +1,137 @@
+package types
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultSignificanceLevel 判定差异显著的 p 值上限
+const DefaultSignificanceLevel = 0.05
+
+// Comparison A/B 两个端点的延迟对比，显著性使用 Mann-Whitney U 检验（正态近似）
+type Comparison struct {
+	A TargetStats `json:"a"`
+	B TargetStats `json:"b"`
+
+	// B 相对 A 的变化百分比，正数表示 B 更慢
+	AvgDelta float64 `json:"avg_delta_percent"`
+	P50Delta float64 `json:"p50_delta_percent"`
+	P99Delta float64 `json:"p99_delta_percent"`
+
+	U           float64 `json:"mann_whitney_u"`
+	Z           float64 `json:"z_score"`
+	PValue      float64 `json:"p_value"`
+	Significant bool    `json:"significant"`
+}
+
+// Verdict 返回对比结论
+func (c *Comparison) Verdict() string {
+	switch {
+	case !c.Significant:
+		return "no significant difference"
+	case c.Z > 0:
+		// A 组秩和偏高，即 A 的响应时间整体更长
+		return "B is significantly faster than A"
+	default:
+		return "B is significantly slower than A"
+	}
+}
+
+// EnableComparison 开启 A/B 对比，a、b 为两个目标在报告中的名称
+func (sr *StressResult) EnableComparison(a, b string) {
+	sr.compareTargets = [2]string{a, b}
+}
+
+// compare 根据两个目标的统计和响应时间样本计算对比结果，未开启时返回 nil
+func (sr *StressResult) compare(targets []TargetStats) *Comparison {
+	if sr.compareTargets[0] == "" {
+		return nil
+	}
+
+	c := &Comparison{}
+	for _, ts := range targets {
+		switch ts.Name {
+		case sr.compareTargets[0]:
+			c.A = ts
+		case sr.compareTargets[1]:
+			c.B = ts
+		}
+	}
+
+	c.AvgDelta = percentChange(c.A.AvgResponseTime, c.B.AvgResponseTime)
+	c.P50Delta = percentChange(c.A.P50ResponseTime, c.B.P50ResponseTime)
+	c.P99Delta = percentChange(c.A.P99ResponseTime, c.B.P99ResponseTime)
+
+	c.U, c.Z, c.PValue = mannWhitneyU(sr.targetSamples(sr.compareTargets[0]), sr.targetSamples(sr.compareTargets[1]))
+	c.Significant = c.PValue < DefaultSignificanceLevel
+	return c
+}
+
+// targetSamples 返回目标成功请求响应时间的有序样本
+func (sr *StressResult) targetSamples(name string) []time.Duration {
+	sr.targetLock.Lock()
+	defer sr.targetLock.Unlock()
+
+	if ts, ok := sr.targetStats[name]; ok {
+		return ts.sortedSamples()
+	}
+	return nil
+}
+
+// percentChange 计算 b 相对 a 的变化百分比
+func percentChange(a, b time.Duration) float64 {
+	if a == 0 {
+		return 0
+	}
+	return float64(b-a) / float64(a) * 100
+}
+
+// mannWhitneyU 对两组已排序样本做双侧 Mann-Whitney U 检验，返回 A 组的 U 值、z 值和 p 值。
+// 样本不足时 p 值为 1
+func mannWhitneyU(a, b []time.Duration) (u, z, p float64) {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if len(a) < 2 || len(b) < 2 {
+		return 0, 0, 1
+	}
+
+	// 合并排序并计算秩，相同值取平均秩，同时累计结的修正项
+	var rankSumA, tieTerm float64
+	i, j := 0, 0
+	rank := 1.0
+	for i < len(a) || j < len(b) {
+		var v time.Duration
+		if j >= len(b) || (i < len(a) && a[i] <= b[j]) {
+			v = a[i]
+		} else {
+			v = b[j]
+		}
+
+		countA, countB := 0, 0
+		for i < len(a) && a[i] == v {
+			countA++
+			i++
+		}
+		for j < len(b) && b[j] == v {
+			countB++
+			j++
+		}
+
+		t := float64(countA + countB)
+		avgRank := rank + (t-1)/2
+		rankSumA += avgRank * float64(countA)
+		tieTerm += t*t*t - t
+		rank += t
+	}
+
+	u = rankSumA - n1*(n1+1)/2
+	n := n1 + n2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return u, 0, 1
+	}
+
+	z = (u - mean) / math.Sqrt(variance)
+	p = math.Erfc(math.Abs(z) / math.Sqrt2)
+	return u, z, p
+}
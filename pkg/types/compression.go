@@ -0,0 +1,54 @@
+package types
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// CompressionStats gzip 压缩响应的统计，用于判断压缩节省的传输量是否值得解压的 CPU 开销
+type CompressionStats struct {
+	CompressedResponses   int64         `json:"compressed_responses"`
+	UncompressedResponses int64         `json:"uncompressed_responses"`
+	WireBytes             int64         `json:"wire_bytes"`    // 压缩响应体的传输大小之和
+	DecodedBytes          int64         `json:"decoded_bytes"` // 压缩响应体解压后的大小之和
+	Ratio                 float64       `json:"compression_ratio"`
+	AvgDecompressTime     time.Duration `json:"avg_decompress_time"`
+	TotalDecompressTime   time.Duration `json:"total_decompress_time"`
+}
+
+// addCompressionResult 统计收到的响应是否压缩，以及压缩响应的传输大小、解压后大小和解压耗时
+func (sr *StressResult) addCompressionResult(result *RequestResult) {
+	if !result.Compressed {
+		// 没有状态码说明没有收到响应
+		if result.StatusCode != 0 {
+			atomic.AddInt64(&sr.uncompressedResponses, 1)
+		}
+		return
+	}
+
+	atomic.AddInt64(&sr.compressedResponses, 1)
+	atomic.AddInt64(&sr.wireBytes, result.WireSize)
+	atomic.AddInt64(&sr.decodedBytes, int64(result.ResponseSize))
+	atomic.AddInt64(&sr.decompressTime, int64(result.DecompressTime))
+}
+
+// GetCompressionStats 汇总压缩响应统计，没有压缩响应时返回 nil
+func (sr *StressResult) GetCompressionStats() *CompressionStats {
+	compressed := atomic.LoadInt64(&sr.compressedResponses)
+	if compressed == 0 {
+		return nil
+	}
+
+	stats := &CompressionStats{
+		CompressedResponses:   compressed,
+		UncompressedResponses: atomic.LoadInt64(&sr.uncompressedResponses),
+		WireBytes:             atomic.LoadInt64(&sr.wireBytes),
+		DecodedBytes:          atomic.LoadInt64(&sr.decodedBytes),
+		TotalDecompressTime:   time.Duration(atomic.LoadInt64(&sr.decompressTime)),
+	}
+	if stats.WireBytes > 0 {
+		stats.Ratio = float64(stats.DecodedBytes) / float64(stats.WireBytes)
+	}
+	stats.AvgDecompressTime = stats.TotalDecompressTime / time.Duration(compressed)
+	return stats
+}
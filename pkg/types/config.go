@@ -6,22 +6,290 @@ import (
 
 // StressConfig 压测配置
 type StressConfig struct {
-	URL           string            `mapstructure:"url" json:"url" yaml:"url"`
-	Method        string            `mapstructure:"method" json:"method" yaml:"method"`
-	TotalRequests int               `mapstructure:"total_requests" json:"total_requests" yaml:"total_requests"`
-	Concurrency   int               `mapstructure:"concurrency" json:"concurrency" yaml:"concurrency"`
-	Duration      time.Duration     `mapstructure:"duration" json:"duration" yaml:"duration"`
-	Headers       map[string]string `mapstructure:"headers" json:"headers" yaml:"headers"`
-	Body          string            `mapstructure:"body" json:"body" yaml:"body"`
-	Timeout       time.Duration     `mapstructure:"timeout" json:"timeout" yaml:"timeout"`
-	KeepAlive     bool              `mapstructure:"keep_alive" json:"keep_alive" yaml:"keep_alive"`
-	CSVFile       string            `mapstructure:"csv_file" json:"csv_file" yaml:"csv_file"`
-	OutputFile    string            `mapstructure:"output_file" json:"output_file" yaml:"output_file"`
-	Verbose       bool              `mapstructure:"verbose" json:"verbose" yaml:"verbose"`
-	LogFile       string            `mapstructure:"log_file" json:"log_file" yaml:"log_file"`
-	ReportFormat  string            `mapstructure:"report_format" json:"report_format" yaml:"report_format"`
+	URL            string            `mapstructure:"url" json:"url" yaml:"url"`
+	Method         string            `mapstructure:"method" json:"method" yaml:"method"`
+	TotalRequests  int               `mapstructure:"total_requests" json:"total_requests" yaml:"total_requests"`
+	Concurrency    int               `mapstructure:"concurrency" json:"concurrency" yaml:"concurrency"`
+	Duration       time.Duration     `mapstructure:"duration" json:"duration" yaml:"duration"`
+	Successes      int               `mapstructure:"successes" json:"successes" yaml:"successes"`          // 持续发送直到成功请求数达到该值
+	MaxAttempts    int               `mapstructure:"max_attempts" json:"max_attempts" yaml:"max_attempts"` // -successes 模式下的请求数上限，0 表示目标的 10 倍
+	StopOn         string            `mapstructure:"stop_on" json:"stop_on" yaml:"stop_on"`                // 同时指定时长和请求数时的停止条件（first、both）
+	Headers        map[string]string `mapstructure:"headers" json:"headers" yaml:"headers"`
+	Body           string            `mapstructure:"body" json:"body" yaml:"body"`
+	Timeout        time.Duration     `mapstructure:"timeout" json:"timeout" yaml:"timeout"`
+	TimeoutAs      string            `mapstructure:"timeout_as" json:"timeout_as" yaml:"timeout_as"` // 超时是否计入错误率门限（fail、warn）
+	KeepAlive      bool              `mapstructure:"keep_alive" json:"keep_alive" yaml:"keep_alive"`
+	CSVFile        string            `mapstructure:"csv_file" json:"csv_file" yaml:"csv_file"`
+	CSVCoverage    bool              `mapstructure:"csv_coverage" json:"csv_coverage" yaml:"csv_coverage"` // 统计 CSV 各行的使用情况
+	CSVMode        string            `mapstructure:"csv_mode" json:"csv_mode" yaml:"csv_mode"`             // CSV 行的选择方式（sequential、random、shuffle-once）
+	BatchSize      int               `mapstructure:"batch_size" json:"batch_size" yaml:"batch_size"`       // 每个请求将 N 行 CSV 数据渲染为 JSON 数组请求体
+	BodyDir        string            `mapstructure:"body_dir" json:"body_dir" yaml:"body_dir"`             // 请求体目录，每个请求按顺序循环使用其中一个文件
+	BodyFile       string            `mapstructure:"body_file" json:"body_file" yaml:"body_file"`          // 从文件读取请求体
+	Binary         bool              `mapstructure:"binary" json:"binary" yaml:"binary"`                   // 请求体按原始字节发送，不做模板替换
+	OutputFile     string            `mapstructure:"output_file" json:"output_file" yaml:"output_file"`
+	OutputDir      string            `mapstructure:"output_dir" json:"output_dir" yaml:"output_dir"`
+	Verbose        bool              `mapstructure:"verbose" json:"verbose" yaml:"verbose"`
+	LogFile        string            `mapstructure:"log_file" json:"log_file" yaml:"log_file"`
+	LogMaxBackups  int               `mapstructure:"log_max_backups" json:"log_max_backups" yaml:"log_max_backups"`
+	ReportFormat   string            `mapstructure:"report_format" json:"report_format" yaml:"report_format"`
+	ReportTemplate string            `mapstructure:"report_template" json:"report_template" yaml:"report_template"` // 自定义报告的 Go text/template 文件
+	TimeUnit       string            `mapstructure:"time_unit" json:"time_unit" yaml:"time_unit"`
+	ExtractMetric  string            `mapstructure:"extract_metric" json:"extract_metric" yaml:"extract_metric"`
+	Trim           string            `mapstructure:"trim" json:"trim" yaml:"trim"` // 截尾统计两端各去掉的百分比，如 "1%"
+
+	// 从成功的 JSON 响应中提取变量（name=path），保存在各工作协程中，后续请求的模板以 {{name}} 引用
+	Extract []string `mapstructure:"extract" json:"extract,omitempty" yaml:"extract,omitempty"`
+
+	// 按顺序报告的分位数，如 "50,95,99,99.9"，未设置时报告 P50/P90/P99
+	Percentiles string `mapstructure:"percentiles" json:"percentiles" yaml:"percentiles"`
+
+	// 响应体中出现任一子串即判定请求失败，用于发现压力下泄漏的调试信息
+	ForbidBodyContains []string `mapstructure:"forbid_body_contains" json:"forbid_body_contains,omitempty" yaml:"forbid_body_contains,omitempty"`
+
+	// 写入报告时额外视为敏感信息的请求头和查询参数名（正则表达式），匹配的值替换为 ***
+	Redact []string `mapstructure:"redact" json:"redact,omitempty" yaml:"redact,omitempty"`
+
+	// 读取哪些响应的响应体（all、error、none），不读取的响应体仍会读完并丢弃以复用连接
+	ReadBodyOn string `mapstructure:"read_body_on" json:"read_body_on" yaml:"read_body_on"`
+
+	// 运行中通过 HTTP 提供实时指标的监听地址，如 ":9090"
+	MetricsAddr string `mapstructure:"metrics_addr" json:"metrics_addr" yaml:"metrics_addr"`
+
+	// 时间序列导出
+	TimeSeriesOut    string `mapstructure:"timeseries_out" json:"timeseries_out" yaml:"timeseries_out"`
+	TimeSeriesMetric string `mapstructure:"timeseries_metric" json:"timeseries_metric" yaml:"timeseries_metric"`
+
+	// HdrHistogram 日志格式的响应时间直方图导出
+	HistogramOut string `mapstructure:"histogram_out" json:"histogram_out,omitempty" yaml:"histogram_out,omitempty"`
+
+	// 响应时间累积分布（CDF）导出，两列 CSV：延迟毫秒数和不超过该延迟的请求比例
+	CDFOut string `mapstructure:"cdf_out" json:"cdf_out,omitempty" yaml:"cdf_out,omitempty"`
+
+	// 每个请求结果以 JSON 行写入该文件，不受内存中明细结果条数的限制
+	RawLog string `mapstructure:"raw_log" json:"raw_log,omitempty" yaml:"raw_log,omitempty"`
+
+	// 多目标配置，设置后每个请求按权重选择一个目标
+	Targets []TargetSpec `mapstructure:"targets" json:"targets,omitempty" yaml:"targets,omitempty"`
+	// 单独的目标列表文件（JSON 或 YAML，格式同配置文件的 targets 字段），便于在不同配置间复用流量组合
+	TargetsFile string `mapstructure:"targets_file" json:"targets_file,omitempty" yaml:"targets_file,omitempty"`
+	// 按权重为每个目标分配独立的工作协程，而不是由共享协程按权重选择目标
+	TargetPools bool `mapstructure:"target_pools" json:"target_pools" yaml:"target_pools"`
+
+	// A/B 对比：按比例将流量分给两个 URL 并对比延迟，内部转换为两个目标
+	CompareEndpoints string `mapstructure:"compare_endpoints" json:"compare_endpoints" yaml:"compare_endpoints"` // "urlA,urlB"
+	CompareSplit     int    `mapstructure:"compare_split" json:"compare_split" yaml:"compare_split"`             // 分给 A 的流量百分比
+
+	// CRUD 模式：每次迭代按顺序对同一资源执行多个方法
+	CRUD        bool   `mapstructure:"crud" json:"crud" yaml:"crud"`
+	CRUDMethods string `mapstructure:"crud_methods" json:"crud_methods" yaml:"crud_methods"`
+	CRUDIDPath  string `mapstructure:"crud_id_path" json:"crud_id_path" yaml:"crud_id_path"`
+
+	// 突发模式：同时释放 N 个请求，用于模拟惊群
+	Burst int `mapstructure:"burst" json:"burst" yaml:"burst"`
+
+	// 交互模式：运行中通过标准输入调整并发数和速率
+	Interactive bool `mapstructure:"interactive" json:"interactive" yaml:"interactive"`
+
+	// 实时面板：在终端中每秒刷新 RPS、成功率、分位数和状态码分布
+	Dashboard bool `mapstructure:"dashboard" json:"dashboard" yaml:"dashboard"`
+
+	// 快速失败：第一个请求失败时立即停止压测
+	FailFast bool `mapstructure:"fail_fast" json:"fail_fast" yaml:"fail_fast"`
+
+	// 压测停止时被取消的请求不计入请求数和失败率，仍单独报告数量
+	ExcludeShutdownCanceled bool `mapstructure:"exclude_shutdown_canceled" json:"exclude_shutdown_canceled" yaml:"exclude_shutdown_canceled"`
+
+	// 重复运行次数，多次运行时额外输出合并所有运行样本后的分位数
+	Repeat int `mapstructure:"repeat" json:"repeat" yaml:"repeat"`
+
+	// 分布式模式下由 rst control 下发配置的 agent 地址，逗号分隔
+	Agents string `mapstructure:"agents" json:"agents,omitempty" yaml:"agents,omitempty"`
+
+	// 速率控制：RateLimit 为所有工作协程合计的固定速率（请求/秒），0 表示不限速
+	RateLimit             int           `mapstructure:"rate" json:"rate,omitempty" yaml:"rate,omitempty"`
+	RateSchedule          string        `mapstructure:"rate_schedule" json:"rate_schedule" yaml:"rate_schedule"`
+	Spike                 bool          `mapstructure:"spike" json:"spike" yaml:"spike"`
+	SpikeBaselineRate     float64       `mapstructure:"spike_baseline_rate" json:"spike_baseline_rate" yaml:"spike_baseline_rate"`
+	SpikeRate             float64       `mapstructure:"spike_rate" json:"spike_rate" yaml:"spike_rate"`
+	SpikeBaselineDuration time.Duration `mapstructure:"spike_baseline_duration" json:"spike_baseline_duration" yaml:"spike_baseline_duration"`
+	SpikeDuration         time.Duration `mapstructure:"spike_duration" json:"spike_duration" yaml:"spike_duration"`
+	SpikeRecoveryDuration time.Duration `mapstructure:"spike_recovery_duration" json:"spike_recovery_duration" yaml:"spike_recovery_duration"`
+
+	// 到达过程：poisson:<rate> 按泊松过程发送请求，Seed 为到达间隔的随机数种子，0 表示随机
+	Arrival string `mapstructure:"arrival" json:"arrival,omitempty" yaml:"arrival,omitempty"`
+	Seed    int64  `mapstructure:"seed" json:"seed,omitempty" yaml:"seed,omitempty"`
+
+	// 开放模型：按 -rate、速率计划或 -arrival 的计划时间发出请求，不等待之前的请求完成，
+	// Concurrency 为同时在途请求数的上限
+	OpenModel bool `mapstructure:"open_model" json:"open_model" yaml:"open_model"`
+
+	// 每个工作协程在 [0, StartJitter) 内随机延迟后开始发送，避免工作协程同步成整齐的波峰，随机数种子同 Seed
+	StartJitter time.Duration `mapstructure:"start_jitter" json:"start_jitter,omitempty" yaml:"start_jitter,omitempty"`
+
+	// 工作协程在 RampUp 内均匀地陆续开始发送，并发数从 1 线性增长到 Concurrency
+	RampUp time.Duration `mapstructure:"ramp_up" json:"ramp_up,omitempty" yaml:"ramp_up,omitempty"`
+
+	// 每个请求完成后工作协程的等待时间，固定时长如 "500ms"，或范围如 "100ms-500ms" 按请求均匀随机
+	ThinkTime string `mapstructure:"think_time" json:"think_time,omitempty" yaml:"think_time,omitempty"`
+
+	// 重定向配置
+	FollowRedirects bool `mapstructure:"follow_redirects" json:"follow_redirects" yaml:"follow_redirects"`
+	MaxRedirects    int  `mapstructure:"max_redirects" json:"max_redirects" yaml:"max_redirects"`
+
+	// 重试配置：失败的请求最多重试 RetryCount 次，RetryOnStatus 为触发重试的状态码列表（逗号分隔），未指定时重试所有 5xx；
+	// RetryWait 和 RetryMaxWait 为指数退避的初始和最大等待时间，0 表示使用 resty 的默认值（100ms 和 2s）
+	RetryCount    int           `mapstructure:"retry_count" json:"retry_count" yaml:"retry_count"`
+	RetryOnStatus string        `mapstructure:"retry_on_status" json:"retry_on_status" yaml:"retry_on_status"`
+	RetryWait     time.Duration `mapstructure:"retry_wait" json:"retry_wait" yaml:"retry_wait"`
+	RetryMaxWait  time.Duration `mapstructure:"retry_max_wait" json:"retry_max_wait" yaml:"retry_max_wait"`
+
+	// 预热阶段平均延迟达到稳定阶段的倍数时给出提示，0 表示关闭
+	WarnSlowStart float64 `mapstructure:"warn_slow_start" json:"warn_slow_start" yaml:"warn_slow_start"`
+
+	// 错误率门限（百分比），超出时以非零退出码结束，0 表示使用默认的 10%
+	FailureThreshold float64 `mapstructure:"fail_threshold" json:"fail_threshold" yaml:"fail_threshold"`
+
+	// SLO 门限，超出时以非零退出码结束
+	MaxAvg time.Duration `mapstructure:"max_avg" json:"max_avg" yaml:"max_avg"`
+	MaxP50 time.Duration `mapstructure:"max_p50" json:"max_p50" yaml:"max_p50"`
+	MaxP90 time.Duration `mapstructure:"max_p90" json:"max_p90" yaml:"max_p90"`
+	MaxP99 time.Duration `mapstructure:"max_p99" json:"max_p99" yaml:"max_p99"`
+
+	// 延迟 SLA：成功且响应时间不超过该值的请求视为达标，报告按秒统计达标率，0 表示关闭
+	LatencySLA time.Duration `mapstructure:"latency_sla" json:"latency_sla,omitempty" yaml:"latency_sla,omitempty"`
+
+	// 连接配置
+	MaxRequestsPerConnection int           `mapstructure:"max_requests_per_connection" json:"max_requests_per_connection" yaml:"max_requests_per_connection"`
+	KeepAliveProbe           bool          `mapstructure:"keepalive_probe" json:"keepalive_probe" yaml:"keepalive_probe"` // 追踪连接并输出连接健康报告
+	PoolStats                bool          `mapstructure:"pool_stats" json:"pool_stats" yaml:"pool_stats"`                // 统计请求等待连接池分配连接的时间
+	IdleTimeout              time.Duration `mapstructure:"idle_timeout" json:"idle_timeout" yaml:"idle_timeout"`          // 空闲连接保留时长
+
+	// 连接池大小：MaxIdleConns 为保留的空闲连接总数，0 表示使用并发数的 2 倍；
+	// MaxConnsPerHost 限制每个主机的连接总数（含使用中的连接），0 表示不限制
+	MaxIdleConns    int `mapstructure:"max_idle_conns" json:"max_idle_conns,omitempty" yaml:"max_idle_conns,omitempty"`
+	MaxConnsPerHost int `mapstructure:"max_conns_per_host" json:"max_conns_per_host,omitempty" yaml:"max_conns_per_host,omitempty"`
+
+	// 每个请求都新建连接并完整进行 TLS 握手：关闭保持连接、空闲连接池和 TLS 会话恢复，用于测量冷连接开销
+	DisableKeepAliveReuse bool `mapstructure:"disable_keepalive_reuse" json:"disable_keepalive_reuse,omitempty" yaml:"disable_keepalive_reuse,omitempty"`
+
+	// 追踪每个请求的 DNS 解析、TCP 建连、TLS 握手和首字节耗时，报告中输出延迟分解
+	LatencyBreakdown bool `mapstructure:"latency_breakdown" json:"latency_breakdown" yaml:"latency_breakdown"`
+
+	// GraphQL：GraphQL 为查询文件，查询作为 POST 请求体发送，响应的 errors 非空时判定失败；
+	// GraphQLVars 为变量的 JSON 模板，可引用 CSV 列
+	GraphQL     string `mapstructure:"graphql" json:"graphql,omitempty" yaml:"graphql,omitempty"`
+	GraphQLVars string `mapstructure:"graphql_variables" json:"graphql_variables,omitempty" yaml:"graphql_variables,omitempty"`
+
+	// multipart/form-data 请求体：FormFields 为表单字段（key=value，值可引用 CSV 列），
+	// FormFiles 为文件字段（field=@path），文件在启动时读入内存，每个请求重复使用
+	FormFields []string `mapstructure:"form" json:"form,omitempty" yaml:"form,omitempty"`
+	FormFiles  []string `mapstructure:"form_file" json:"form_file,omitempty" yaml:"form_file,omitempty"`
+
+	// DNS 配置：逗号分隔的 DNS 服务器地址（ip 或 ip:port），设置后不使用系统解析器，并输出 DNS 解析耗时
+	DNSServers string `mapstructure:"dns_servers" json:"dns_servers,omitempty" yaml:"dns_servers,omitempty"`
+
+	// 主机地址覆盖（host:port:addr，同 curl --resolve）：连接 host:port 时直接连接 addr，Host 头和 TLS SNI 不变
+	Resolve []string `mapstructure:"resolve" json:"resolve,omitempty" yaml:"resolve,omitempty"`
+
+	// 代理服务器地址，支持 http://、https:// 和 socks5://，请求经由代理发送
+	Proxy string `mapstructure:"proxy" json:"proxy,omitempty" yaml:"proxy,omitempty"`
+
+	// TLS 配置，版本为 1.0 到 1.3，密码套件为逗号分隔的 Go crypto/tls 常量名
+	TLSMinVersion string `mapstructure:"tls_min_version" json:"tls_min_version" yaml:"tls_min_version"`
+	TLSMaxVersion string `mapstructure:"tls_max_version" json:"tls_max_version" yaml:"tls_max_version"`
+	CipherSuites  string `mapstructure:"cipher_suites" json:"cipher_suites" yaml:"cipher_suites"`
+	MinTLSLatency string `mapstructure:"min_tls_latency" json:"min_tls_latency" yaml:"min_tls_latency"` // TLS 握手占平均响应时间的比例达到该百分比时提示，如 "30%"
+
+	// 双向 TLS 的客户端证书和私钥（PEM 文件，须同时指定），CACert 为校验服务端证书使用的根证书，
+	// 默认校验服务端证书，Insecure 时跳过校验
+	ClientCert string `mapstructure:"client_cert" json:"client_cert,omitempty" yaml:"client_cert,omitempty"`
+	ClientKey  string `mapstructure:"client_key" json:"client_key,omitempty" yaml:"client_key,omitempty"`
+	CACert     string `mapstructure:"ca_cert" json:"ca_cert,omitempty" yaml:"ca_cert,omitempty"`
+	Insecure   bool   `mapstructure:"insecure" json:"insecure" yaml:"insecure"`
+
+	// 认证配置
+	TokenCommand         string        `mapstructure:"token_command" json:"token_command" yaml:"token_command"`
+	TokenRefreshInterval time.Duration `mapstructure:"token_refresh_interval" json:"token_refresh_interval" yaml:"token_refresh_interval"`
+	BasicAuthUser        string        `mapstructure:"basic_auth_user" json:"basic_auth_user,omitempty" yaml:"basic_auth_user,omitempty"`
+	BasicAuthPass        string        `mapstructure:"basic_auth_pass" json:"basic_auth_pass,omitempty" yaml:"basic_auth_pass,omitempty"`
+	BearerToken          string        `mapstructure:"bearer_token" json:"bearer_token,omitempty" yaml:"bearer_token,omitempty"`
+
+	// Cookie 配置：Cookies 为 true 时所有工作协程共享一个 cookie jar，响应设置的 cookie 在后续请求中发送，
+	// 默认不保存；Cookie 为每个请求都携带的初始 cookie，格式 name=value
+	Cookies bool     `mapstructure:"cookies" json:"cookies" yaml:"cookies"`
+	Cookie  []string `mapstructure:"cookie" json:"cookie,omitempty" yaml:"cookie,omitempty"`
+
+	// 请求签名，凭证和密钥从环境变量读取
+	AWSSigV4            string `mapstructure:"aws_sigv4" json:"aws_sigv4" yaml:"aws_sigv4"` // region:service
+	HMACHeader          string `mapstructure:"hmac_header" json:"hmac_header" yaml:"hmac_header"`
+	HMACAlgorithm       string `mapstructure:"hmac_algorithm" json:"hmac_algorithm" yaml:"hmac_algorithm"`
+	HMACTemplate        string `mapstructure:"hmac_template" json:"hmac_template" yaml:"hmac_template"`
+	HMACEncoding        string `mapstructure:"hmac_encoding" json:"hmac_encoding" yaml:"hmac_encoding"`
+	HMACTimestampHeader string `mapstructure:"hmac_timestamp_header" json:"hmac_timestamp_header" yaml:"hmac_timestamp_header"`
 }
 
+// HMAC 签名默认参数
+const (
+	DefaultHMACAlgorithm       = "sha256"
+	DefaultHMACTemplate        = `{method}\n{path}\n{timestamp}\n{body}`
+	DefaultHMACEncoding        = "hex"
+	DefaultHMACTimestampHeader = "X-Timestamp"
+)
+
+// A/B 对比中两个目标的名称与默认流量比例
+const (
+	CompareTargetA      = "A"
+	CompareTargetB      = "B"
+	DefaultCompareSplit = 50
+)
+
+// CRUD 模式默认参数
+const (
+	DefaultCRUDMethods = "POST,GET,DELETE"
+	DefaultCRUDIDPath  = "id"
+)
+
+// DefaultTimeSeriesMetric 时间序列导出的默认指标列
+const DefaultTimeSeriesMetric = "p99"
+
+// -read-body-on 的取值
+const (
+	ReadBodyAll   = "all"
+	ReadBodyError = "error" // 只读取状态码 >= 400 的响应体
+	ReadBodyNone  = "none"
+)
+
+// -csv-mode 的取值
+const (
+	CSVModeSequential  = "sequential"   // 按顺序循环使用
+	CSVModeRandom      = "random"       // 每次随机选取一行
+	CSVModeShuffleOnce = "shuffle-once" // 加载时打乱顺序后循环使用
+)
+
+// -stop-on 的取值
+const (
+	StopOnFirst = "first" // 时长或请求数任一达到即停止
+	StopOnBoth  = "both"  // 时长和请求数都达到才停止
+)
+
+// -timeout-as 的取值
+const (
+	TimeoutAsFail = "fail"
+	TimeoutAsWarn = "warn" // 超时只作提示，不计入错误率门限
+)
+
+// DefaultWarnSlowStart 默认的慢启动提示倍数
+const DefaultWarnSlowStart = 2.0
+
+// DefaultMaxRedirects 默认最大重定向次数，与 net/http 保持一致
+const DefaultMaxRedirects = 10
+
+// DefaultIdleTimeout 默认的空闲连接保留时长
+const DefaultIdleTimeout = 90 * time.Second
+
+// DefaultMaxAttemptsFactor -successes 模式下未指定上限时，请求数上限为成功目标的倍数
+const DefaultMaxAttemptsFactor = 10
+
 // DefaultConfig 返回默认配置
 func DefaultConfig() *StressConfig {
 	return &StressConfig{
@@ -31,5 +299,34 @@ func DefaultConfig() *StressConfig {
 		Timeout:       30 * time.Second,
 		KeepAlive:     true,
 		ReportFormat:  "console",
+
+		TimeSeriesMetric: DefaultTimeSeriesMetric,
+		ReadBodyOn:       ReadBodyAll,
+		CSVMode:          CSVModeSequential,
+		TimeoutAs:        TimeoutAsFail,
+
+		WarnSlowStart:    DefaultWarnSlowStart,
+		FailureThreshold: DefaultMaxErrorRate,
+
+		CompareSplit: DefaultCompareSplit,
+
+		CRUDMethods: DefaultCRUDMethods,
+		CRUDIDPath:  DefaultCRUDIDPath,
+
+		FollowRedirects: true,
+		MaxRedirects:    DefaultMaxRedirects,
+
+		IdleTimeout: DefaultIdleTimeout,
+
+		SpikeBaselineRate:     DefaultSpikeBaselineRate,
+		SpikeRate:             DefaultSpikeRate,
+		SpikeBaselineDuration: DefaultSpikeBaselineDuration,
+		SpikeDuration:         DefaultSpikeDuration,
+		SpikeRecoveryDuration: DefaultSpikeRecoveryDuration,
+
+		HMACAlgorithm:       DefaultHMACAlgorithm,
+		HMACTemplate:        DefaultHMACTemplate,
+		HMACEncoding:        DefaultHMACEncoding,
+		HMACTimestampHeader: DefaultHMACTimestampHeader,
 	}
 }
@@ -0,0 +1,40 @@
+package types
+
+import "sync/atomic"
+
+// ConnectionHealth 连接层面的健康状况，请求成功率可能掩盖服务端的连接不稳定
+type ConnectionHealth struct {
+	NewConnections int64 `json:"new_connections"`
+	Reconnects     int64 `json:"reconnects"` // 已有保持连接被关闭后重新建立连接
+	Resets         int64 `json:"connection_resets"`
+	Dropped        int64 `json:"connections_dropped"` // 请求过程中连接被关闭
+	IdleClosed     int64 `json:"idle_connections_closed"`
+}
+
+// Unhealthy 是否出现过连接被重置、中断或空闲关闭
+func (h *ConnectionHealth) Unhealthy() bool {
+	return h.Resets > 0 || h.Dropped > 0 || h.IdleClosed > 0
+}
+
+// EnableConnectionHealth 开启连接健康统计，需在压测开始前调用
+func (sr *StressResult) EnableConnectionHealth() {
+	atomic.StoreInt32(&sr.connHealthOn, 1)
+}
+
+// GetConnectionHealth 汇总连接健康统计，未开启时返回 nil
+func (sr *StressResult) GetConnectionHealth() *ConnectionHealth {
+	if atomic.LoadInt32(&sr.connHealthOn) == 0 {
+		return nil
+	}
+
+	sr.errorCountsLock.RLock()
+	defer sr.errorCountsLock.RUnlock()
+
+	return &ConnectionHealth{
+		NewConnections: atomic.LoadInt64(&sr.NewConnections),
+		Reconnects:     atomic.LoadInt64(&sr.reconnects),
+		Resets:         sr.errorCategories[ErrorCategoryConnectionReset],
+		Dropped:        sr.errorCategories[ErrorCategoryConnectionDropped],
+		IdleClosed:     sr.errorCategories[ErrorCategoryIdleConnClosed],
+	}
+}
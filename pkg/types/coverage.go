@@ -0,0 +1,31 @@
+package types
+
+// maxUnusedRows 覆盖率统计中列出的未使用行数上限
+const maxUnusedRows = 10
+
+// CSVCoverage CSV 参数数据的使用情况，行号从 1 开始且不含表头
+type CSVCoverage struct {
+	Rows       int   `json:"rows"`
+	Used       int   `json:"used"`
+	Unused     int   `json:"unused"`
+	Reused     int   `json:"reused"`                // 被使用超过一次的行数
+	UnusedRows []int `json:"unused_rows,omitempty"` // 前若干个未使用的行号
+}
+
+// NewCSVCoverage 根据每行的使用次数计算覆盖率
+func NewCSVCoverage(hits []int32) *CSVCoverage {
+	coverage := &CSVCoverage{Rows: len(hits)}
+	for i, n := range hits {
+		switch {
+		case n == 0:
+			coverage.Unused++
+			if len(coverage.UnusedRows) < maxUnusedRows {
+				coverage.UnusedRows = append(coverage.UnusedRows, i+1)
+			}
+		case n > 1:
+			coverage.Reused++
+		}
+	}
+	coverage.Used = coverage.Rows - coverage.Unused
+	return coverage
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DNSStats -dns-servers 指定的 DNS 服务器的解析统计，只有新建连接时才解析主机名
+type DNSStats struct {
+	Lookups   int64         `json:"lookups"`
+	AvgLookup time.Duration `json:"avg_lookup"`
+	MaxLookup time.Duration `json:"max_lookup"`
+	Failures  int64         `json:"failures"` // 错误分类为 dns_error 的请求数
+}
+
+// EnableDNSStats 开启 DNS 解析统计，需在压测开始前调用
+func (sr *StressResult) EnableDNSStats() {
+	atomic.StoreInt32(&sr.dnsOn, 1)
+}
+
+// addDNSResult 统计请求建立连接时解析主机名的耗时
+func (sr *StressResult) addDNSResult(result *RequestResult) {
+	if atomic.LoadInt32(&sr.dnsOn) == 0 || result.DNSLookup <= 0 {
+		return
+	}
+
+	atomic.AddInt64(&sr.dnsLookups, 1)
+	atomic.AddInt64(&sr.dnsTime, int64(result.DNSLookup))
+	for {
+		current := atomic.LoadInt64(&sr.dnsMaxLookup)
+		if int64(result.DNSLookup) <= current || atomic.CompareAndSwapInt64(&sr.dnsMaxLookup, current, int64(result.DNSLookup)) {
+			break
+		}
+	}
+}
+
+// GetDNSStats 汇总 DNS 解析统计，未开启时返回 nil
+func (sr *StressResult) GetDNSStats() *DNSStats {
+	if atomic.LoadInt32(&sr.dnsOn) == 0 {
+		return nil
+	}
+
+	sr.errorCountsLock.RLock()
+	failures := sr.errorCategories[ErrorCategoryDNSError]
+	sr.errorCountsLock.RUnlock()
+
+	stats := &DNSStats{
+		Lookups:   atomic.LoadInt64(&sr.dnsLookups),
+		MaxLookup: time.Duration(atomic.LoadInt64(&sr.dnsMaxLookup)),
+		Failures:  failures,
+	}
+	if stats.Lookups > 0 {
+		stats.AvgLookup = time.Duration(atomic.LoadInt64(&sr.dnsTime) / stats.Lookups)
+	}
+	return stats
+}
@@ -0,0 +1,40 @@
+package types
+
+// EnableFailFast 开启快速失败：第一个失败的请求被记录到 FailFast 并调用 stop，之后的失败不再触发。
+// 需在压测开始前调用
+func (sr *StressResult) EnableFailFast(stop func()) {
+	sr.failFastStop = stop
+}
+
+// checkFailFast 在开启快速失败时记录第一个失败的请求并停止压测
+func (sr *StressResult) checkFailFast(result *RequestResult) {
+	if sr.failFastStop == nil || result.Success {
+		return
+	}
+
+	sr.failFastOnce.Do(func() {
+		sr.failFastLock.Lock()
+		sr.FailFast = result
+		sr.failFastLock.Unlock()
+		sr.failFastStop()
+	})
+}
+
+// GetFailFast 返回触发快速失败的请求，未触发时返回 nil
+func (sr *StressResult) GetFailFast() *RequestResult {
+	sr.failFastLock.Lock()
+	defer sr.failFastLock.Unlock()
+	return sr.FailFast
+}
+
+// ExitStatus 返回压测的退出状态，触发快速失败或没有完成的请求时优先于门限检查结果。
+// 没有完成的请求时使用单独的退出码 2，与门限未通过区分
+func (sr *StressResult) ExitStatus(slo SLOReport) ExitStatus {
+	if sr.GetFailFast() != nil {
+		return ExitStatus{Reason: ExitReasonFailFast, Code: 1}
+	}
+	if sr.TotalRequests == 0 {
+		return ExitStatus{Reason: ExitReasonNoRequests, Code: 2}
+	}
+	return slo.ExitStatus()
+}
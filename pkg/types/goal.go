@@ -0,0 +1,31 @@
+package types
+
+import "sync/atomic"
+
+// SuccessGoal 成功目标模式的执行结果，失败的请求不计入目标
+type SuccessGoal struct {
+	Goal      int64 `json:"goal"`
+	Successes int64 `json:"successes"`
+	Attempts  int64 `json:"attempts"`
+	Reached   bool  `json:"reached"`
+}
+
+// SetSuccessGoal 设置成功目标，需在压测开始前调用
+func (sr *StressResult) SetSuccessGoal(goal int) {
+	sr.successGoal = int64(goal)
+}
+
+// GetSuccessGoal 汇总成功目标的完成情况，未设置目标时返回 nil
+func (sr *StressResult) GetSuccessGoal() *SuccessGoal {
+	if sr.successGoal == 0 {
+		return nil
+	}
+
+	successes := atomic.LoadInt64(&sr.SuccessfulRequests)
+	return &SuccessGoal{
+		Goal:      sr.successGoal,
+		Successes: successes,
+		Attempts:  atomic.LoadInt64(&sr.TotalRequests),
+		Reached:   successes >= sr.successGoal,
+	}
+}
@@ -0,0 +1,104 @@
+package types
+
+import (
+	"time"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// HdrHistogram 参数：以微秒记录，范围 1µs 到 1 小时，3 位有效数字
+const (
+	HdrLowestValue     = 1
+	HdrHighestValue    = int64(time.Hour / time.Microsecond)
+	HdrSignificantFigs = 3
+)
+
+// newLatencyHistogram 创建记录响应时间（微秒）的直方图
+func newLatencyHistogram() *hdrhistogram.Histogram {
+	return hdrhistogram.New(HdrLowestValue, HdrHighestValue, HdrSignificantFigs)
+}
+
+// recordLatency 将成功请求的响应时间记入直方图，超出范围的值记为上限；设置了预期间隔时
+// 同时按间隔补入样本记入校正直方图。调用方需持有 resultsLock
+func (sr *StressResult) recordLatency(d time.Duration) {
+	if sr.latencyHist == nil {
+		sr.latencyHist = newLatencyHistogram()
+	}
+	value := histogramValue(d)
+	sr.latencyHist.RecordValue(value)
+
+	if sr.expectedInterval > 0 {
+		if sr.correctedHist == nil {
+			sr.correctedHist = newLatencyHistogram()
+		}
+		sr.correctedHist.RecordCorrectedValue(value, int64(sr.expectedInterval/time.Microsecond))
+	}
+}
+
+// histogramValue 将响应时间换算为直方图记录的微秒数，超出范围的值记为上下限
+func histogramValue(d time.Duration) int64 {
+	return min(max(int64(d/time.Microsecond), 0), HdrHighestValue)
+}
+
+// LatencyHistogram 以微秒为单位返回全部成功请求响应时间的直方图，不受详细记录数量上限的影响。
+// 返回的是副本，没有成功请求时为空直方图
+func (sr *StressResult) LatencyHistogram() *hdrhistogram.Histogram {
+	if h := sr.fullHistogram(); h != nil {
+		return h
+	}
+	return newLatencyHistogram()
+}
+
+// fullHistogram 返回全部成功请求直方图的副本：压测中记录的直方图，或从 JSON 报告中的编码还原，都没有时返回 nil
+func (sr *StressResult) fullHistogram() *hdrhistogram.Histogram {
+	sr.resultsLock.RLock()
+	defer sr.resultsLock.RUnlock()
+
+	if sr.latencyHist != nil {
+		return hdrhistogram.Import(sr.latencyHist.Export())
+	}
+	if sr.Histogram != "" {
+		if h, err := hdrhistogram.Decode([]byte(sr.Histogram)); err == nil {
+			return h
+		}
+	}
+	return nil
+}
+
+// encodedHistogram 返回写入 JSON 报告的直方图编码
+func (sr *StressResult) encodedHistogram() string {
+	sr.resultsLock.RLock()
+	defer sr.resultsLock.RUnlock()
+
+	if sr.latencyHist == nil {
+		return sr.Histogram
+	}
+	encoded, err := EncodeHistogram(sr.latencyHist)
+	if err != nil {
+		return ""
+	}
+	return encoded
+}
+
+// percentileFunc 返回计算分位数 p（0-1）的函数。详细记录只保留最近的请求，样本少于直方图记录的请求数时
+// 由直方图计算，避免只反映最后一段时间；否则由排好序的样本精确计算。hist 为 nil 时只使用样本
+func percentileFunc(sorted []time.Duration, hist *hdrhistogram.Histogram) func(p float64) time.Duration {
+	if hist != nil && hist.TotalCount() > int64(len(sorted)) {
+		return func(p float64) time.Duration { return histogramPercentile(hist, p) }
+	}
+	return func(p float64) time.Duration { return calculatePercentile(sorted, p) }
+}
+
+// histogramPercentile 返回直方图中分位数 p（0-1）所在槽位的上限
+func histogramPercentile(h *hdrhistogram.Histogram, p float64) time.Duration {
+	return time.Duration(h.ValueAtQuantile(p*100)) * time.Microsecond
+}
+
+// EncodeHistogram 返回 V2 压缩编码（zlib）后再经 base64 编码的直方图，即日志文件中 "HISTFAAA..." 形式的字符串
+func EncodeHistogram(h *hdrhistogram.Histogram) (string, error) {
+	encoded, err := h.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// LiveMetrics 压测进行中的指标快照，可在 AddResult 并发执行时读取
+type LiveMetrics struct {
+	Elapsed            time.Duration    `json:"elapsed_duration"`
+	TotalRequests      int64            `json:"total_requests"`
+	SuccessfulRequests int64            `json:"successful_requests"`
+	FailedRequests     int64            `json:"failed_requests"`
+	RequestsPerSecond  float64          `json:"requests_per_second"`
+	AvgResponseTime    time.Duration    `json:"avg_response_time"`
+	MinResponseTime    time.Duration    `json:"min_response_time"`
+	MaxResponseTime    time.Duration    `json:"max_response_time"`
+	P50ResponseTime    time.Duration    `json:"p50_response_time"`
+	P90ResponseTime    time.Duration    `json:"p90_response_time"`
+	P99ResponseTime    time.Duration    `json:"p99_response_time"`
+	StatusCodes        map[int]int64    `json:"status_codes"`
+	ErrorCategories    map[string]int64 `json:"error_categories"`
+}
+
+// Snapshot 返回当前的实时指标。分位数由已保留的详细记录计算，
+// StartTime 需在压测开始前设置
+func (sr *StressResult) Snapshot(now time.Time) LiveMetrics {
+	total := atomic.LoadInt64(&sr.TotalRequests)
+	metrics := LiveMetrics{
+		Elapsed:            now.Sub(sr.StartTime),
+		TotalRequests:      total,
+		SuccessfulRequests: atomic.LoadInt64(&sr.SuccessfulRequests),
+		FailedRequests:     atomic.LoadInt64(&sr.FailedRequests),
+		MinResponseTime:    sr.GetMinResponseTime(),
+		MaxResponseTime:    sr.GetMaxResponseTime(),
+		StatusCodes:        sr.GetStatusCodes(),
+		ErrorCategories:    sr.GetErrorCategories(),
+	}
+	if total > 0 {
+		metrics.AvgResponseTime = time.Duration(atomic.LoadInt64(&sr.TotalResponseTime) / total)
+	}
+	if metrics.Elapsed > 0 {
+		metrics.RequestsPerSecond = float64(total) / metrics.Elapsed.Seconds()
+	}
+
+	durations := sr.successfulDurations()
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	metrics.P50ResponseTime = calculatePercentile(durations, 0.50)
+	metrics.P90ResponseTime = calculatePercentile(durations, 0.90)
+	metrics.P99ResponseTime = calculatePercentile(durations, 0.99)
+	return metrics
+}
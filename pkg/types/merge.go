@@ -0,0 +1,92 @@
+package types
+
+// Merge 将另一份结果合并到当前结果，用于汇总分布式压测中多台机器的报告。
+// other 需已调用 CalculateMetrics（或从 JSON 报告中读取），合并后需重新调用
+// CalculateMetrics。双方的响应时间直方图按槽位累加，分位数按各自的全部请求加权计算。
+// 目标、方法、TLS 等扩展统计不参与合并
+func (sr *StressResult) Merge(other *StressResult) {
+	sr.TotalRequests += other.TotalRequests
+	sr.SuccessfulRequests += other.SuccessfulRequests
+	sr.FailedRequests += other.FailedRequests
+	sr.ShutdownCanceled += other.ShutdownCanceled
+	sr.Interrupted = sr.Interrupted || other.Interrupted
+	sr.Timeouts += other.Timeouts
+	sr.TotalResponseTime += other.TotalResponseTime
+	sr.TotalBytesSent += other.TotalBytesSent
+	sr.TotalBytesReceived += other.TotalBytesReceived
+	sr.NewConnections += other.NewConnections
+	sr.ReusedConns += other.ReusedConns
+	sr.DroppedArrivals += other.DroppedArrivals
+
+	// 各机器使用相同的配置，沿用第一份校正结果的预期间隔，开放模型的实测结果由合并的校正直方图重新计算
+	if other.Corrected != nil && other.Corrected.Measured {
+		sr.openModel = true
+	} else if sr.expectedInterval == 0 && other.Corrected != nil {
+		sr.expectedInterval = other.Corrected.ExpectedInterval
+	}
+
+	// 各机器并行运行，合并后的时长取最早开始到最晚结束
+	if sr.StartTime.IsZero() || (!other.StartTime.IsZero() && other.StartTime.Before(sr.StartTime)) {
+		sr.StartTime = other.StartTime
+	}
+	if other.EndTime.After(sr.EndTime) {
+		sr.EndTime = other.EndTime
+	}
+
+	sr.statusCodesLock.Lock()
+	for code, count := range other.StatusCodes {
+		sr.statusCodes[code] += count
+	}
+	sr.statusCodesLock.Unlock()
+
+	sr.errorCountsLock.Lock()
+	for errorMsg, count := range other.Errors {
+		sr.errorCounts[errorMsg] += count
+	}
+	for category, count := range other.ErrorCategories {
+		sr.errorCategories[category] += count
+	}
+	for errorMsg, example := range other.ErrorExamples {
+		if _, ok := sr.errorExamples[errorMsg]; !ok {
+			sr.errorExamples[errorMsg] = example
+		}
+	}
+	sr.errorCountsLock.Unlock()
+
+	sr.resultsLock.Lock()
+	if other.TotalRequests > 0 {
+		if other.MinResponseTime < sr.MinResponseTime {
+			sr.MinResponseTime = other.MinResponseTime
+		}
+		if other.MaxResponseTime > sr.MaxResponseTime {
+			sr.MaxResponseTime = other.MaxResponseTime
+		}
+	}
+	// 直方图记录了全部请求，没有直方图的报告以保留的详细记录代替
+	if hist := other.fullHistogram(); hist != nil {
+		if sr.latencyHist == nil {
+			sr.latencyHist = newLatencyHistogram()
+		}
+		sr.latencyHist.Merge(hist)
+		if corrected := other.correctedHistogram(); corrected != nil && (sr.expectedInterval > 0 || sr.openModel) {
+			if sr.correctedHist == nil {
+				sr.correctedHist = newLatencyHistogram()
+			}
+			sr.correctedHist.Merge(corrected)
+		}
+	} else {
+		for _, d := range other.successfulDurations() {
+			sr.recordLatency(d)
+		}
+		for _, d := range other.scheduledLatencies() {
+			sr.recordScheduledLatency(d)
+		}
+	}
+
+	// 保留双方的全部样本，避免环形缓冲区丢弃先合并的报告
+	sr.DetailedResults = append(sr.DetailedResults, other.DetailedResults...)
+	if len(sr.DetailedResults) > sr.maxResults {
+		sr.maxResults = len(sr.DetailedResults)
+	}
+	sr.resultsLock.Unlock()
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// MethodStats 按 HTTP 方法统计的结果
+type MethodStats struct {
+	Method             string        `json:"method"`
+	TotalRequests      int64         `json:"total_requests"`
+	SuccessfulRequests int64         `json:"successful_requests"`
+	FailedRequests     int64         `json:"failed_requests"`
+	AvgResponseTime    time.Duration `json:"avg_response_time"`
+	P50ResponseTime    time.Duration `json:"p50_response_time"`
+	P99ResponseTime    time.Duration `json:"p99_response_time"`
+
+	totalTime time.Duration
+	samples   []time.Duration
+}
+
+// GetSuccessRate 计算该方法的成功率
+func (ms *MethodStats) GetSuccessRate() float64 {
+	if ms.TotalRequests == 0 {
+		return 0
+	}
+	return float64(ms.SuccessfulRequests) / float64(ms.TotalRequests) * 100
+}
+
+// RegisterMethods 按给定顺序开启分方法统计
+func (sr *StressResult) RegisterMethods(methods []string) {
+	sr.methodLock.Lock()
+	defer sr.methodLock.Unlock()
+
+	if sr.methodStats == nil {
+		sr.methodStats = make(map[string]*MethodStats)
+	}
+	for _, method := range methods {
+		if _, ok := sr.methodStats[method]; !ok {
+			sr.methodStats[method] = &MethodStats{Method: method}
+			sr.methodOrder = append(sr.methodOrder, method)
+		}
+	}
+}
+
+// addMethodResult 更新分方法统计，未开启时忽略
+func (sr *StressResult) addMethodResult(result *RequestResult) {
+	sr.methodLock.Lock()
+	defer sr.methodLock.Unlock()
+
+	stats, ok := sr.methodStats[result.Method]
+	if !ok {
+		return
+	}
+
+	stats.TotalRequests++
+	if !result.Success {
+		stats.FailedRequests++
+		return
+	}
+	stats.SuccessfulRequests++
+	stats.totalTime += result.Duration
+	if len(stats.samples) < sr.maxResults {
+		stats.samples = append(stats.samples, result.Duration)
+	}
+}
+
+// GetMethodStats 获取分方法统计的快照
+func (sr *StressResult) GetMethodStats() []MethodStats {
+	sr.methodLock.Lock()
+	defer sr.methodLock.Unlock()
+
+	stats := make([]MethodStats, 0, len(sr.methodOrder))
+	for _, method := range sr.methodOrder {
+		ms := sr.methodStats[method]
+		snapshot := MethodStats{
+			Method:             ms.Method,
+			TotalRequests:      ms.TotalRequests,
+			SuccessfulRequests: ms.SuccessfulRequests,
+			FailedRequests:     ms.FailedRequests,
+		}
+		if ms.SuccessfulRequests > 0 {
+			snapshot.AvgResponseTime = ms.totalTime / time.Duration(ms.SuccessfulRequests)
+
+			times := make([]time.Duration, len(ms.samples))
+			copy(times, ms.samples)
+			sort.Slice(times, func(i, j int) bool {
+				return times[i] < times[j]
+			})
+			snapshot.P50ResponseTime = calculatePercentile(times, 0.50)
+			snapshot.P99ResponseTime = calculatePercentile(times, 0.99)
+		}
+		stats = append(stats, snapshot)
+	}
+	return stats
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// maxMetricSamples 自定义指标保留的最大样本数
+const maxMetricSamples = 10000
+
+// MetricStats 从响应中提取的自定义数值指标统计
+type MetricStats struct {
+	Name    string  `json:"name"`
+	Count   int64   `json:"count"`
+	Missing int64   `json:"missing"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Avg     float64 `json:"avg"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	P99     float64 `json:"p99"`
+
+	mu      sync.Mutex
+	sum     float64
+	samples []float64
+	index   int
+}
+
+// NewMetricStats 创建自定义指标统计
+func NewMetricStats(name string) *MetricStats {
+	return &MetricStats{
+		Name:    name,
+		Min:     math.MaxFloat64,
+		samples: make([]float64, 0, 1000),
+	}
+}
+
+// Add 添加一个样本
+func (m *MetricStats) Add(value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Count++
+	m.sum += value
+	if value < m.Min {
+		m.Min = value
+	}
+	if value > m.Max {
+		m.Max = value
+	}
+
+	// 使用环形缓冲区限制样本数量
+	if len(m.samples) < maxMetricSamples {
+		m.samples = append(m.samples, value)
+	} else {
+		m.samples[m.index] = value
+		m.index = (m.index + 1) % maxMetricSamples
+	}
+}
+
+// AddMissing 记录一次未能提取到指标的响应
+func (m *MetricStats) AddMissing() {
+	m.mu.Lock()
+	m.Missing++
+	m.mu.Unlock()
+}
+
+// Calculate 计算平均值和分位数
+func (m *MetricStats) Calculate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Count == 0 {
+		m.Min = 0
+		return
+	}
+
+	m.Avg = m.sum / float64(m.Count)
+
+	sorted := make([]float64, len(m.samples))
+	copy(sorted, m.samples)
+	sort.Float64s(sorted)
+
+	m.P50 = percentileFloat(sorted, 0.50)
+	m.P90 = percentileFloat(sorted, 0.90)
+	m.P99 = percentileFloat(sorted, 0.99)
+}
+
+// percentileFloat 计算已排序数据的分位数
+func percentileFloat(sortedData []float64, percentile float64) float64 {
+	if len(sortedData) == 0 {
+		return 0
+	}
+
+	index := percentile * float64(len(sortedData)-1)
+	lower := int(index)
+	upper := lower + 1
+
+	if upper >= len(sortedData) {
+		return sortedData[lower]
+	}
+
+	weight := index - float64(lower)
+	return sortedData[lower]*(1-weight) + sortedData[upper]*weight
+}
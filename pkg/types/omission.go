@@ -0,0 +1,216 @@
+package types
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// CorrectedLatency 协调遗漏校正后的分位数。服务端停顿时工作协程无法按计划发出请求，
+// 停顿期间本应发出的请求没有被记录，未校正的分位数会低估尾延迟。校正方法与 HdrHistogram 的
+// recordValueWithExpectedInterval 相同：响应时间超过预期间隔时，按间隔补入 d-i、d-2i……直到不小于 i 的样本
+type CorrectedLatency struct {
+	ExpectedInterval  time.Duration `json:"expected_interval"`  // 每个工作协程发出请求的预期间隔
+	BackfilledPercent float64       `json:"backfilled_percent"` // 校正后样本中补入样本所占的百分比
+	Measured          bool          `json:"measured,omitempty"` // 开放模型下按计划发送时间实测，不补入样本
+	P50ResponseTime   time.Duration `json:"p50_response_time"`
+	P90ResponseTime   time.Duration `json:"p90_response_time"`
+	P99ResponseTime   time.Duration `json:"p99_response_time"`
+
+	// 校正直方图的 V2 压缩编码，合并报告时由此还原
+	Histogram string `json:"histogram,omitempty"`
+}
+
+// SetExpectedInterval 设置每个工作协程发出请求的预期间隔，大于 0 时计算协调遗漏校正后的分位数
+func (sr *StressResult) SetExpectedInterval(interval time.Duration) {
+	sr.expectedInterval = interval
+}
+
+// EnableOpenModel 开启开放模型统计：校正分位数改为按每个请求的计划发送时间实测
+func (sr *StressResult) EnableOpenModel() {
+	sr.openModel = true
+}
+
+// AddDroppedArrival 记录一次因在途请求达到上限而未发送的到达
+func (sr *StressResult) AddDroppedArrival() {
+	atomic.AddInt64(&sr.DroppedArrivals, 1)
+}
+
+// recordScheduledLatency 开放模型下将成功请求从计划发送时间到完成的耗时记入校正直方图。调用方需持有 resultsLock
+func (sr *StressResult) recordScheduledLatency(d time.Duration) {
+	if !sr.openModel || d <= 0 {
+		return
+	}
+	if sr.correctedHist == nil {
+		sr.correctedHist = newLatencyHistogram()
+	}
+	sr.correctedHist.RecordValue(histogramValue(d))
+}
+
+// measuredLatency 计算成功请求从计划发送时间到完成的分位数。开放模型下请求按计划时间发出，
+// 不会因服务端变慢而推迟，因此可以直接测量，不需要估计补入的样本。与未校正的分位数一样，
+// 详细记录覆盖全部请求时由样本精确计算，否则由记录了全部请求的校正直方图计算
+func (sr *StressResult) measuredLatency(hist *hdrhistogram.Histogram) *CorrectedLatency {
+	latencies := sr.scheduledLatencies()
+	measured := sr.correctedHistogram()
+
+	var latency *CorrectedLatency
+	if hist != nil && measured != nil && hist.TotalCount() > int64(len(latencies)) {
+		latency = &CorrectedLatency{
+			Measured:        true,
+			P50ResponseTime: histogramPercentile(measured, 0.50),
+			P90ResponseTime: histogramPercentile(measured, 0.90),
+			P99ResponseTime: histogramPercentile(measured, 0.99),
+		}
+	} else if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool {
+			return latencies[i] < latencies[j]
+		})
+		latency = &CorrectedLatency{
+			Measured:        true,
+			P50ResponseTime: calculatePercentile(latencies, 0.50),
+			P90ResponseTime: calculatePercentile(latencies, 0.90),
+			P99ResponseTime: calculatePercentile(latencies, 0.99),
+		}
+	} else {
+		return nil
+	}
+
+	if measured != nil {
+		latency.Histogram, _ = EncodeHistogram(measured)
+	}
+	return latency
+}
+
+// scheduledLatencies 返回保留的详细记录中成功请求从计划发送时间到完成的耗时
+func (sr *StressResult) scheduledLatencies() []time.Duration {
+	sr.resultsLock.RLock()
+	defer sr.resultsLock.RUnlock()
+
+	var latencies []time.Duration
+	for _, result := range sr.DetailedResults {
+		if result.Success && result.ScheduledLatency > 0 {
+			latencies = append(latencies, result.ScheduledLatency)
+		}
+	}
+	return latencies
+}
+
+// correctedLatency 计算协调遗漏校正后的分位数，未设置预期间隔时返回 nil。与未校正的分位数一样，
+// 详细记录覆盖全部请求时由样本精确计算，否则由压测中逐个请求补入样本的校正直方图计算，
+// 两者始终基于同一批请求
+func (sr *StressResult) correctedLatency(sorted []time.Duration, hist *hdrhistogram.Histogram) *CorrectedLatency {
+	if sr.expectedInterval <= 0 {
+		return nil
+	}
+
+	corrected := sr.correctedHistogram()
+	var latency *CorrectedLatency
+	if hist != nil && corrected != nil && hist.TotalCount() > int64(len(sorted)) {
+		latency = &CorrectedLatency{
+			ExpectedInterval:  sr.expectedInterval,
+			BackfilledPercent: float64(corrected.TotalCount()-hist.TotalCount()) / float64(corrected.TotalCount()) * 100,
+			P50ResponseTime:   histogramPercentile(corrected, 0.50),
+			P90ResponseTime:   histogramPercentile(corrected, 0.90),
+			P99ResponseTime:   histogramPercentile(corrected, 0.99),
+		}
+	} else if latency = correctLatency(sorted, sr.expectedInterval); latency == nil {
+		return nil
+	}
+
+	if corrected != nil {
+		latency.Histogram, _ = EncodeHistogram(corrected)
+	}
+	return latency
+}
+
+// correctedHistogram 返回校正直方图的副本：压测中记录的直方图，或从 JSON 报告中的编码还原，都没有时返回 nil
+func (sr *StressResult) correctedHistogram() *hdrhistogram.Histogram {
+	sr.resultsLock.RLock()
+	defer sr.resultsLock.RUnlock()
+
+	if sr.correctedHist != nil {
+		return hdrhistogram.Import(sr.correctedHist.Export())
+	}
+	if sr.Corrected != nil && sr.Corrected.Histogram != "" {
+		if h, err := hdrhistogram.Decode([]byte(sr.Corrected.Histogram)); err == nil {
+			return h
+		}
+	}
+	return nil
+}
+
+// correctLatency 对已排序的响应时间计算校正后的分位数，interval 为 0 时返回 nil。
+// 补入的样本不实际生成（单次长时间停顿可能对应大量样本），而是按值二分查找第 k 小的样本
+func correctLatency(sorted []time.Duration, interval time.Duration) *CorrectedLatency {
+	if interval <= 0 || len(sorted) == 0 {
+		return nil
+	}
+
+	var total int64
+	for _, d := range sorted {
+		total += backfilled(d, interval) + 1
+	}
+
+	percentile := func(p float64) time.Duration {
+		index := p * float64(total-1)
+		lower := int64(index)
+		value := correctedKth(sorted, interval, lower)
+		if lower+1 >= total {
+			return value
+		}
+		weight := index - float64(lower)
+		upper := correctedKth(sorted, interval, lower+1)
+		return time.Duration(float64(value)*(1-weight) + float64(upper)*weight)
+	}
+
+	return &CorrectedLatency{
+		ExpectedInterval:  interval,
+		BackfilledPercent: float64(total-int64(len(sorted))) / float64(total) * 100,
+		P50ResponseTime:   percentile(0.50),
+		P90ResponseTime:   percentile(0.90),
+		P99ResponseTime:   percentile(0.99),
+	}
+}
+
+// backfilled 返回响应时间 d 需要补入的样本数
+func backfilled(d, interval time.Duration) int64 {
+	if d <= interval {
+		return 0
+	}
+	return int64(d/interval) - 1
+}
+
+// correctedAtMost 返回响应时间 d 及其补入样本中不超过 t 的个数
+func correctedAtMost(d, t, interval time.Duration) int64 {
+	n := backfilled(d, interval)
+	if t >= d {
+		return n + 1
+	}
+	// 补入样本为 d-j*i (j = 1..n)，不超过 t 要求 j >= ceil((d-t)/i)
+	first := int64((d - t + interval - 1) / interval)
+	if first > n {
+		return 0
+	}
+	return n - first + 1
+}
+
+// correctedKth 返回校正后第 k 小（从 0 开始）的样本
+func correctedKth(sorted []time.Duration, interval time.Duration, k int64) time.Duration {
+	lo, hi := time.Duration(0), sorted[len(sorted)-1]
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		var count int64
+		for _, d := range sorted {
+			count += correctedAtMost(d, mid, interval)
+		}
+		if count > k {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
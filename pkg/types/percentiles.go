@@ -0,0 +1,35 @@
+package types
+
+import (
+	"strconv"
+	"time"
+)
+
+// PercentileValue 单个 -percentiles 分位数的结果
+type PercentileValue struct {
+	Percentile   float64
+	ResponseTime time.Duration
+}
+
+// SetPercentiles 设置额外计算的分位数（百分数，如 99.9），报告按给定顺序输出
+func (sr *StressResult) SetPercentiles(percentiles []float64) {
+	sr.percentileList = percentiles
+}
+
+// GetPercentiles 按配置顺序返回分位数结果，未配置或没有成功请求时返回 nil
+func (sr *StressResult) GetPercentiles() []PercentileValue {
+	if len(sr.Percentiles) == 0 {
+		return nil
+	}
+
+	values := make([]PercentileValue, 0, len(sr.percentileList))
+	for _, percentile := range sr.percentileList {
+		values = append(values, PercentileValue{Percentile: percentile, ResponseTime: sr.Percentiles[percentile]})
+	}
+	return values
+}
+
+// PercentileLabel 分位数的显示名称，如 99.9 显示为 "P99.9"
+func PercentileLabel(percentile float64) string {
+	return "P" + strconv.FormatFloat(percentile, 'f', -1, 64)
+}
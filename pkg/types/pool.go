@@ -0,0 +1,72 @@
+package types
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// PoolWaitThreshold 等待连接超过该时长的请求计为等待过连接池，过滤掉正常的加锁和调度开销
+const PoolWaitThreshold = time.Millisecond
+
+// PoolSaturatedPercent 等待过连接池的请求超过该百分比时认为连接池不足
+const PoolSaturatedPercent = 10.0
+
+// PoolStats 连接池等待统计：请求等待连接池分配连接说明连接池对当前负载过小，瓶颈在客户端
+type PoolStats struct {
+	Requests      int64         `json:"requests"`
+	Waited        int64         `json:"waited"` // 等待超过 PoolWaitThreshold 的请求数
+	WaitedPercent float64       `json:"waited_percent"`
+	AvgWait       time.Duration `json:"avg_wait"` // 等待过的请求的平均等待时间
+	MaxWait       time.Duration `json:"max_wait"`
+}
+
+// Saturated 是否有较多请求等待连接池
+func (p *PoolStats) Saturated() bool {
+	return p.WaitedPercent > PoolSaturatedPercent
+}
+
+// EnablePoolStats 开启连接池等待统计，需在压测开始前调用
+func (sr *StressResult) EnablePoolStats() {
+	atomic.StoreInt32(&sr.poolOn, 1)
+}
+
+// addPoolResult 统计请求等待连接的时间
+func (sr *StressResult) addPoolResult(result *RequestResult) {
+	if atomic.LoadInt32(&sr.poolOn) == 0 {
+		return
+	}
+
+	atomic.AddInt64(&sr.poolRequests, 1)
+	if result.ConnWait < PoolWaitThreshold {
+		return
+	}
+
+	atomic.AddInt64(&sr.poolWaited, 1)
+	atomic.AddInt64(&sr.poolWaitTime, int64(result.ConnWait))
+	for {
+		current := atomic.LoadInt64(&sr.poolMaxWait)
+		if int64(result.ConnWait) <= current || atomic.CompareAndSwapInt64(&sr.poolMaxWait, current, int64(result.ConnWait)) {
+			break
+		}
+	}
+}
+
+// GetPoolStats 汇总连接池等待统计，未开启时返回 nil
+func (sr *StressResult) GetPoolStats() *PoolStats {
+	if atomic.LoadInt32(&sr.poolOn) == 0 {
+		return nil
+	}
+
+	stats := &PoolStats{
+		Requests: atomic.LoadInt64(&sr.poolRequests),
+		Waited:   atomic.LoadInt64(&sr.poolWaited),
+		MaxWait:  time.Duration(atomic.LoadInt64(&sr.poolMaxWait)),
+	}
+	if stats.Requests > 0 {
+		stats.WaitedPercent = float64(stats.Waited) / float64(stats.Requests) * 100
+	}
+	if stats.Waited > 0 {
+		stats.AvgWait = time.Duration(atomic.LoadInt64(&sr.poolWaitTime) / stats.Waited)
+	}
+	return stats
+}
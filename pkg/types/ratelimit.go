@@ -0,0 +1,27 @@
+package types
+
+// RateLimitStats 固定速率模式下目标速率与实际速率的对比
+type RateLimitStats struct {
+	TargetRate   float64 `json:"target_rate"`   // 目标速率（请求/秒）
+	AchievedRate float64 `json:"achieved_rate"` // 实际速率（请求/秒）
+	Percent      float64 `json:"percent"`       // 实际速率占目标速率的百分比
+}
+
+// SetRateLimit 设置固定速率模式的目标速率，需在压测开始前调用
+func (sr *StressResult) SetRateLimit(rate int) {
+	sr.rateLimit = rate
+}
+
+// GetRateLimitStats 计算实际速率与目标速率的对比，未设置目标速率时返回 nil
+func (sr *StressResult) GetRateLimitStats() *RateLimitStats {
+	if sr.rateLimit <= 0 {
+		return nil
+	}
+
+	achieved := sr.GetRequestsPerSecond()
+	return &RateLimitStats{
+		TargetRate:   float64(sr.rateLimit),
+		AchievedRate: achieved,
+		Percent:      achieved / float64(sr.rateLimit) * 100,
+	}
+}
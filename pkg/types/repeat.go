@@ -0,0 +1,82 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// RunSummary 重复运行中单次运行的摘要
+type RunSummary struct {
+	Run                int           `json:"run"`
+	TotalRequests      int64         `json:"total_requests"`
+	SuccessfulRequests int64         `json:"successful_requests"`
+	P50ResponseTime    time.Duration `json:"p50_response_time"`
+	P90ResponseTime    time.Duration `json:"p90_response_time"`
+	P99ResponseTime    time.Duration `json:"p99_response_time"`
+}
+
+// RepeatSummary 多次运行的汇总。合并分位数由各次运行记录全部成功请求的直方图累加后计算，
+// 反映真实的整体分布；各次 P99 的平均值仅用于对比，短测试的尾部延迟取平均会失真
+type RepeatSummary struct {
+	Runs               []RunSummary  `json:"runs"`
+	TotalRequests      int64         `json:"total_requests"`
+	SuccessfulRequests int64         `json:"successful_requests"`
+	MeanP99            time.Duration `json:"mean_p99_response_time"`
+	P50ResponseTime    time.Duration `json:"p50_response_time"`
+	P90ResponseTime    time.Duration `json:"p90_response_time"`
+	P99ResponseTime    time.Duration `json:"p99_response_time"`
+}
+
+// CombineRuns 汇总多次运行的结果，各结果需已调用 CalculateMetrics。
+// 每次运行的全部成功请求按实际数量计入合并分位数
+func CombineRuns(results []*StressResult) *RepeatSummary {
+	summary := &RepeatSummary{Runs: make([]RunSummary, 0, len(results))}
+	if len(results) == 0 {
+		return summary
+	}
+
+	hist := newLatencyHistogram()
+	var samples []time.Duration
+	var p99Sum time.Duration
+	for i, result := range results {
+		summary.Runs = append(summary.Runs, RunSummary{
+			Run:                i + 1,
+			TotalRequests:      result.TotalRequests,
+			SuccessfulRequests: result.SuccessfulRequests,
+			P50ResponseTime:    result.P50ResponseTime,
+			P90ResponseTime:    result.P90ResponseTime,
+			P99ResponseTime:    result.P99ResponseTime,
+		})
+		summary.TotalRequests += result.TotalRequests
+		summary.SuccessfulRequests += result.SuccessfulRequests
+		p99Sum += result.P99ResponseTime
+		if runHist := result.fullHistogram(); runHist != nil {
+			hist.Merge(runHist)
+		}
+		samples = append(samples, result.successfulDurations()...)
+	}
+	summary.MeanP99 = p99Sum / time.Duration(len(results))
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+	percentile := percentileFunc(samples, hist)
+	summary.P50ResponseTime = percentile(0.50)
+	summary.P90ResponseTime = percentile(0.90)
+	summary.P99ResponseTime = percentile(0.99)
+	return summary
+}
+
+// successfulDurations 返回详细记录中成功请求的响应时间
+func (sr *StressResult) successfulDurations() []time.Duration {
+	sr.resultsLock.RLock()
+	defer sr.resultsLock.RUnlock()
+
+	var durations []time.Duration
+	for _, result := range sr.DetailedResults {
+		if result.Success {
+			durations = append(durations, result.Duration)
+		}
+	}
+	return durations
+}
@@ -5,19 +5,70 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
 )
 
 // RequestResult 单个请求结果
 type RequestResult struct {
-	Timestamp    time.Time     `json:"timestamp"`
-	Duration     time.Duration `json:"duration"`
-	StatusCode   int           `json:"status_code"`
-	Success      bool          `json:"success"`
-	Error        string        `json:"error,omitempty"`
-	ResponseSize int           `json:"response_size"`
-	CSVData      interface{}   `json:"csv_data,omitempty"`
+	Timestamp      time.Time     `json:"timestamp"`
+	Method         string        `json:"method,omitempty"`
+	URL            string        `json:"url,omitempty"`
+	Duration       time.Duration `json:"duration"`
+	StatusCode     int           `json:"status_code"`
+	Success        bool          `json:"success"`
+	Error          string        `json:"error,omitempty"`
+	ResponseSize   int           `json:"response_size"`
+	RequestSize    int64         `json:"request_size,omitempty"`    // 已发送的请求体字节数，包括重试
+	Compressed     bool          `json:"compressed,omitempty"`      // 响应使用 gzip 压缩传输
+	WireSize       int64         `json:"wire_size,omitempty"`       // 压缩响应体的传输大小
+	DecompressTime time.Duration `json:"decompress_time,omitempty"` // 解压响应体的耗时
+	CSVData        interface{}   `json:"csv_data,omitempty"`
+	NewConnection  bool          `json:"new_connection,omitempty"` // 仅在启用连接追踪时设置
+	ReusedConn     bool          `json:"reused_connection,omitempty"`
+	TLSVersion     string        `json:"tls_version,omitempty"`
+	TLSCipher      string        `json:"tls_cipher_suite,omitempty"`
+	TLSHandshake   time.Duration `json:"tls_handshake,omitempty"` // 仅在启用连接追踪时设置，复用连接时为 0
+	DNSLookup      time.Duration `json:"dns_lookup,omitempty"`    // 仅在启用连接追踪时设置，复用连接或 IP 地址时为 0
+	ConnectTime    time.Duration `json:"connect_time,omitempty"`  // TCP 建连耗时，仅在开启 -latency-breakdown 时设置，复用连接时为 0
+	ServerTime     time.Duration `json:"server_time,omitempty"`   // 从获得连接到收到首字节的耗时（TTFB），仅在开启 -latency-breakdown 时设置
+	Target         string        `json:"target,omitempty"`
+	ErrorCategory  string        `json:"error_category,omitempty"`
+	Reconnect      bool          `json:"reconnect,omitempty"`  // 仅在启用连接健康统计时设置
+	BytesSent      int64         `json:"bytes_sent,omitempty"` // 上传中途失败时已发送的请求体字节数
+	BatchRows      int           `json:"batch_rows,omitempty"` // 批量请求体包含的 CSV 行数
+	Retries        int           `json:"retries,omitempty"`    // 重试次数，Duration 包含所有尝试
+	ConnWait       time.Duration `json:"conn_wait,omitempty"`  // 等待连接池分配连接的时间，仅在开启 -pool-stats 时设置
+	// 开放模型下从计划发送时间到请求完成的耗时，包括调度延迟，用于计算实测的校正分位数
+	ScheduledLatency time.Duration `json:"scheduled_latency,omitempty"`
 }
 
+// 错误分类
+const (
+	// ErrorCategoryRedirectLoop 重定向循环或超过最大跳转次数
+	ErrorCategoryRedirectLoop = "redirect_loop"
+	// ErrorCategoryGraphQLError GraphQL 响应的 errors 非空，或响应不是 JSON
+	ErrorCategoryGraphQLError = "graphql_error"
+	// ErrorCategoryForbiddenContent 响应体包含 -forbid-body-contains 指定的内容
+	ErrorCategoryForbiddenContent = "forbidden_content"
+	// ErrorCategoryConnectionReset 连接被对端重置
+	ErrorCategoryConnectionReset = "connection_reset"
+	// ErrorCategoryConnectionDropped 请求过程中连接被关闭
+	ErrorCategoryConnectionDropped = "connection_dropped"
+	// ErrorCategoryIdleConnClosed 复用的空闲连接已被服务端关闭
+	ErrorCategoryIdleConnClosed = "idle_connection_closed"
+	// ErrorCategoryUploadError 发送请求体的过程中失败
+	ErrorCategoryUploadError = "upload_error"
+	// ErrorCategoryDNSError 解析主机名失败
+	ErrorCategoryDNSError = "dns_error"
+	// ErrorCategoryTLSError 服务端证书校验失败或 TLS 握手失败
+	ErrorCategoryTLSError = "tls_error"
+	// ErrorCategoryTimeout 请求超过 -timeout 仍未完成
+	ErrorCategoryTimeout = "timeout"
+	// ErrorCategoryShutdownCanceled 压测停止时仍在进行中、被取消的请求
+	ErrorCategoryShutdownCanceled = "canceled_by_shutdown"
+)
+
 // ErrorItem 错误项
 type ErrorItem struct {
 	Error string
@@ -33,19 +84,190 @@ type StressResult struct {
 	StartTime          time.Time     `json:"start_time"`
 	EndTime            time.Time     `json:"end_time"`
 
+	// 压测停止时被取消的请求数，排除时不计入上面的请求数
+	ShutdownCanceled        int64 `json:"shutdown_canceled,omitempty"`
+	excludeShutdownCanceled bool
+
+	// 压测被信号中断，结果只包含中断前完成的请求
+	Interrupted bool `json:"interrupted,omitempty"`
+
+	// 超时的请求数，计入失败数；-timeout-as warn 时不计入错误率门限
+	Timeouts     int64 `json:"timeouts"`
+	timeoutsWarn bool
+
+	// 判定失败的错误率（百分比），0 表示使用 DefaultMaxErrorRate
+	failureThreshold float64
+
 	// 响应时间统计
 	MinResponseTime   time.Duration `json:"min_response_time"`
 	MaxResponseTime   time.Duration `json:"max_response_time"`
-	TotalResponseTime int64         `json:"-"` // 用于计算平均值
+	TotalResponseTime int64         `json:"total_response_time"` // 用于计算平均值，合并多份报告时需要
+
+	// 所有请求已发送的请求体字节数之和（包括重试）和收到的响应体字节数之和（压缩响应按解压后的大小）
+	TotalBytesSent     int64 `json:"total_bytes_sent,omitempty"`
+	TotalBytesReceived int64 `json:"total_bytes_received,omitempty"`
 
 	// 分位数统计
 	P50ResponseTime time.Duration `json:"p50_response_time"`
 	P90ResponseTime time.Duration `json:"p90_response_time"`
 	P99ResponseTime time.Duration `json:"p99_response_time"`
 
+	// -percentiles 配置的分位数，键为百分数（如 99.9）；浮点键无法编码为 JSON，
+	// JSON 报告在摘要中按配置顺序输出
+	Percentiles    map[float64]time.Duration `json:"-"`
+	percentileList []float64
+
+	// 成功请求响应时间的标准差
+	StdDevResponseTime time.Duration `json:"stddev_response_time"`
+
+	// 去掉两端极值后的统计，分位数不受影响
+	Trimmed     *TrimmedStats `json:"trimmed,omitempty"`
+	trimPercent float64
+
+	// 配置到达速率时的协调遗漏校正
+	Corrected        *CorrectedLatency `json:"corrected,omitempty"`
+	expectedInterval time.Duration
+
+	// 开放模型：按计划发送时间实测校正分位数，DroppedArrivals 为在途请求达到上限而未发送的到达数
+	DroppedArrivals int64 `json:"dropped_arrivals,omitempty"`
+	openModel       bool
+
+	// 连接统计（仅在启用连接追踪时有效）
+	NewConnections   int64             `json:"new_connections,omitempty"`
+	ReusedConns      int64             `json:"reused_connections,omitempty"`
+	ConnectionHealth *ConnectionHealth `json:"connection_health,omitempty"`
+	reconnects       int64
+	connHealthOn     int32
+
+	// 连接池等待统计（仅在开启 -pool-stats 时有效）
+	ConnectionPool *PoolStats `json:"connection_pool,omitempty"`
+	poolOn         int32
+	poolRequests   int64
+	poolWaited     int64
+	poolWaitTime   int64
+	poolMaxWait    int64
+
+	// DNS 解析统计（仅在指定 -dns-servers 时有效）
+	DNS          *DNSStats `json:"dns,omitempty"`
+	dnsOn        int32
+	dnsLookups   int64
+	dnsTime      int64
+	dnsMaxLookup int64
+
+	// 延迟分解（仅在开启 -latency-breakdown 时有效）
+	LatencyBreakdown *LatencyBreakdown `json:"latency_breakdown,omitempty"`
+	breakdownOn      int32
+
+	// 延迟 SLA 的整体和最差一秒的达标率，未设置 -sla 时为空
+	SLACompliance *SLACompliance `json:"sla_compliance,omitempty"`
+
+	// 协商的 TLS 版本和密码套件（仅 HTTPS 请求）
+	TLS       []TLSNegotiation `json:"tls,omitempty"`
+	tlsCounts map[tlsKey]int64
+	tlsLock   sync.Mutex
+
+	// TLS 会话恢复统计（仅 HTTPS 请求）
+	TLSResumption *TLSResumption `json:"tls_resumption,omitempty"`
+	tlsHandshakes int64
+	tlsResumed    int64
+	tlsTime       int64 // 所有请求的 TLS 握手耗时之和
+
+	// gzip 压缩响应统计
+	Compression           *CompressionStats `json:"compression,omitempty"`
+	compressedResponses   int64
+	uncompressedResponses int64
+	wireBytes             int64
+	decodedBytes          int64
+	decompressTime        int64
+
+	// -batch-size 批量请求统计
+	Batch          *BatchStats `json:"batch,omitempty"`
+	batchRequests  int64
+	batchRows      int64
+	batchSucceeded int64
+
+	// -retry-count 重试统计
+	Retry            *RetryStats `json:"retry,omitempty"`
+	retriedRequests  int64
+	retries          int64
+	retriedSucceeded int64
+
+	// 快速失败模式下触发停止的请求
+	FailFast     *RequestResult `json:"fail_fast,omitempty"`
+	failFastStop func()
+	failFastOnce sync.Once
+	failFastLock sync.Mutex
+
+	// 成功目标模式的执行结果
+	SuccessGoal *SuccessGoal `json:"success_goal,omitempty"`
+	successGoal int64
+
+	// 突发模式统计
+	Burst *BurstStats `json:"burst,omitempty"`
+	burst *BurstStats
+
+	// CSV 参数数据的使用情况
+	CSVCoverage *CSVCoverage `json:"csv_coverage,omitempty"`
+
+	// 请求体目录中实际被使用过的文件数
+	DistinctBodies int `json:"distinct_bodies,omitempty"`
+
+	// 从响应中提取的自定义指标
+	CustomMetric *MetricStats `json:"custom_metric,omitempty"`
+
+	// 多目标压测的分目标统计
+	Targets     []TargetStats `json:"targets,omitempty"`
+	targetStats map[string]*TargetStats
+	targetOrder []string
+	targetLock  sync.Mutex
+
+	// A/B 对比结果
+	Comparison     *Comparison `json:"comparison,omitempty"`
+	compareTargets [2]string
+
+	// CRUD 模式的分方法统计
+	Methods     []MethodStats `json:"methods,omitempty"`
+	methodStats map[string]*MethodStats
+	methodOrder []string
+	methodLock  sync.Mutex
+
+	// 按状态码的响应时间，包括判定为失败的响应
+	StatusLatency     []StatusLatency `json:"status_latency,omitempty"`
+	statusSamples     map[int]*statusSamples
+	statusLatencyLock sync.Mutex
+
+	// 固定速率模式的目标速率与实际速率
+	RateLimit *RateLimitStats `json:"rate_limit,omitempty"`
+	rateLimit int
+
+	// 速率计划各阶段的执行结果
+	RateSegments []RateSegment `json:"rate_segments,omitempty"`
+	segmentLock  sync.Mutex
+	segmentOn    int32
+	segmentTimes []time.Duration
+
+	// 按秒统计的时间序列（仅在启用时收集）
+	TimeSeries    []TimeSeriesPoint `json:"time_series,omitempty"`
+	seriesBuckets map[int]*timeSeriesBucket
+	latencySLA    time.Duration
+	seriesSamples bool
+	seriesLock    sync.Mutex
+
+	// 状态码分布和按错误信息统计的失败请求数
+	StatusCodes map[int]int64    `json:"status_codes,omitempty"`
+	Errors      map[string]int64 `json:"errors,omitempty"`
+
+	// 按类别统计的失败请求数
+	ErrorCategories map[string]int64 `json:"error_categories,omitempty"`
+
+	// 每种错误第一次出现时的请求，便于复现
+	ErrorExamples map[string]*RequestResult `json:"error_examples,omitempty"`
+
 	// 分布统计 - 使用更高效的数据结构
 	statusCodes     map[int]int64
 	errorCounts     map[string]int64
+	errorCategories map[string]int64
+	errorExamples   map[string]*RequestResult
 	statusCodesLock sync.RWMutex
 	errorCountsLock sync.RWMutex
 
@@ -54,6 +276,15 @@ type StressResult struct {
 	resultsLock     sync.RWMutex
 	resultIndex     int
 	maxResults      int
+
+	// 全部成功请求的响应时间直方图，不受 maxResults 限制，由 resultsLock 保护。
+	// 报告中以 V2 压缩编码写入，合并报告时由此还原
+	Histogram   string `json:"latency_histogram,omitempty"`
+	latencyHist *hdrhistogram.Histogram
+
+	// 协调遗漏校正直方图：设置预期间隔时逐个请求补入样本，开放模型下记录从计划发送时间到完成的耗时，
+	// 由 resultsLock 保护
+	correctedHist *hdrhistogram.Histogram
 }
 
 // NewStressResult 创建新的结果统计器
@@ -61,6 +292,8 @@ func NewStressResult() *StressResult {
 	return &StressResult{
 		statusCodes:     make(map[int]int64),
 		errorCounts:     make(map[string]int64),
+		errorCategories: make(map[string]int64),
+		errorExamples:   make(map[string]*RequestResult),
 		DetailedResults: make([]*RequestResult, 0, 1000), // 预分配容量
 		MinResponseTime: time.Hour,
 		maxResults:      10000, // 限制最大记录数
@@ -69,8 +302,67 @@ func NewStressResult() *StressResult {
 
 // AddResult 添加请求结果
 func (sr *StressResult) AddResult(result *RequestResult) {
+	if result.ErrorCategory == ErrorCategoryShutdownCanceled {
+		atomic.AddInt64(&sr.ShutdownCanceled, 1)
+		if sr.excludeShutdownCanceled {
+			return
+		}
+	}
+
 	atomic.AddInt64(&sr.TotalRequests, 1)
 	atomic.AddInt64(&sr.TotalResponseTime, int64(result.Duration))
+	if result.RequestSize > 0 {
+		atomic.AddInt64(&sr.TotalBytesSent, result.RequestSize)
+	}
+	if result.ResponseSize > 0 {
+		atomic.AddInt64(&sr.TotalBytesReceived, int64(result.ResponseSize))
+	}
+
+	if result.NewConnection {
+		atomic.AddInt64(&sr.NewConnections, 1)
+	}
+	if result.ReusedConn {
+		atomic.AddInt64(&sr.ReusedConns, 1)
+	}
+	if result.Reconnect {
+		atomic.AddInt64(&sr.reconnects, 1)
+	}
+
+	if result.Target != "" {
+		sr.addTargetResult(result)
+	}
+
+	if result.TLSCipher != "" {
+		sr.addTLSResult(result)
+	}
+	if result.TLSHandshake > 0 {
+		atomic.AddInt64(&sr.tlsTime, int64(result.TLSHandshake))
+	}
+
+	sr.addCompressionResult(result)
+	sr.addBatchResult(result)
+	sr.addRetryResult(result)
+	sr.addPoolResult(result)
+	sr.addDNSResult(result)
+
+	if result.Method != "" {
+		sr.addMethodResult(result)
+	}
+
+	if result.StatusCode > 0 {
+		sr.addStatusLatency(result)
+	}
+
+	sr.addTimeSeriesResult(result)
+
+	// 记录当前速率阶段的响应时间
+	if result.Success && atomic.LoadInt32(&sr.segmentOn) == 1 {
+		sr.segmentLock.Lock()
+		if len(sr.segmentTimes) < sr.maxResults {
+			sr.segmentTimes = append(sr.segmentTimes, result.Duration)
+		}
+		sr.segmentLock.Unlock()
+	}
 
 	if result.Success {
 		atomic.AddInt64(&sr.SuccessfulRequests, 1)
@@ -81,10 +373,20 @@ func (sr *StressResult) AddResult(result *RequestResult) {
 		sr.statusCodesLock.Unlock()
 	} else {
 		atomic.AddInt64(&sr.FailedRequests, 1)
+		if result.ErrorCategory == ErrorCategoryTimeout {
+			atomic.AddInt64(&sr.Timeouts, 1)
+		}
+		sr.checkFailFast(result)
 
 		// 更新错误统计
 		sr.errorCountsLock.Lock()
 		sr.errorCounts[result.Error]++
+		if _, ok := sr.errorExamples[result.Error]; !ok {
+			sr.errorExamples[result.Error] = result
+		}
+		if result.ErrorCategory != "" {
+			sr.errorCategories[result.ErrorCategory]++
+		}
 		sr.errorCountsLock.Unlock()
 	}
 
@@ -98,6 +400,10 @@ func (sr *StressResult) AddResult(result *RequestResult) {
 	if result.Duration > sr.MaxResponseTime {
 		sr.MaxResponseTime = result.Duration
 	}
+	if result.Success {
+		sr.recordLatency(result.Duration)
+		sr.recordScheduledLatency(result.ScheduledLatency)
+	}
 
 	// 记录详细结果（使用环形缓冲区逻辑）
 	if len(sr.DetailedResults) < sr.maxResults {
@@ -110,6 +416,130 @@ func (sr *StressResult) AddResult(result *RequestResult) {
 	}
 }
 
+// addTargetResult 更新分目标统计
+func (sr *StressResult) addTargetResult(result *RequestResult) {
+	sr.targetLock.Lock()
+	defer sr.targetLock.Unlock()
+
+	if sr.targetStats == nil {
+		sr.targetStats = make(map[string]*TargetStats)
+	}
+
+	stats, ok := sr.targetStats[result.Target]
+	if !ok {
+		stats = &TargetStats{Name: result.Target}
+		sr.targetStats[result.Target] = stats
+		sr.targetOrder = append(sr.targetOrder, result.Target)
+	}
+
+	stats.TotalRequests++
+	if !result.Success {
+		stats.FailedRequests++
+		return
+	}
+	stats.SuccessfulRequests++
+	stats.totalTime += result.Duration
+	if len(stats.samples) < sr.maxResults {
+		stats.samples = append(stats.samples, result.Duration)
+	}
+}
+
+// RegisterTargets 按配置顺序登记目标，保证报告中的目标顺序稳定
+func (sr *StressResult) RegisterTargets(names []string) {
+	sr.targetLock.Lock()
+	defer sr.targetLock.Unlock()
+
+	if sr.targetStats == nil {
+		sr.targetStats = make(map[string]*TargetStats)
+	}
+	for _, name := range names {
+		if _, ok := sr.targetStats[name]; !ok {
+			sr.targetStats[name] = &TargetStats{Name: name}
+			sr.targetOrder = append(sr.targetOrder, name)
+		}
+	}
+}
+
+// SetTargetConcurrency 记录目标独占的工作协程数
+func (sr *StressResult) SetTargetConcurrency(name string, workers int) {
+	sr.targetLock.Lock()
+	defer sr.targetLock.Unlock()
+
+	if stats, ok := sr.targetStats[name]; ok {
+		stats.Concurrency = workers
+	}
+}
+
+// GetTargetStats 获取分目标统计的快照
+func (sr *StressResult) GetTargetStats() []TargetStats {
+	sr.targetLock.Lock()
+	defer sr.targetLock.Unlock()
+
+	stats := make([]TargetStats, 0, len(sr.targetOrder))
+	for _, name := range sr.targetOrder {
+		ts := sr.targetStats[name]
+		snapshot := TargetStats{
+			Name:               ts.Name,
+			TotalRequests:      ts.TotalRequests,
+			SuccessfulRequests: ts.SuccessfulRequests,
+			FailedRequests:     ts.FailedRequests,
+			Concurrency:        ts.Concurrency,
+		}
+		if ts.SuccessfulRequests > 0 {
+			snapshot.AvgResponseTime = ts.totalTime / time.Duration(ts.SuccessfulRequests)
+
+			times := ts.sortedSamples()
+			snapshot.P50ResponseTime = calculatePercentile(times, 0.50)
+			snapshot.P99ResponseTime = calculatePercentile(times, 0.99)
+		}
+		stats = append(stats, snapshot)
+	}
+	return stats
+}
+
+// StartRateSegment 开始收集新速率阶段的响应时间
+func (sr *StressResult) StartRateSegment() {
+	sr.segmentLock.Lock()
+	sr.segmentTimes = sr.segmentTimes[:0]
+	sr.segmentLock.Unlock()
+	atomic.StoreInt32(&sr.segmentOn, 1)
+}
+
+// AddRateSegment 结束当前速率阶段并记录其执行结果
+func (sr *StressResult) AddRateSegment(step RateStep, duration time.Duration, requests int64) {
+	segment := RateSegment{
+		Label:      step.Label,
+		TargetRate: step.Rate,
+		Duration:   duration,
+		Requests:   requests,
+	}
+	if duration > 0 {
+		segment.AchievedRate = float64(requests) / duration.Seconds()
+	}
+
+	sr.segmentLock.Lock()
+	times := make([]time.Duration, len(sr.segmentTimes))
+	copy(times, sr.segmentTimes)
+	sr.segmentLock.Unlock()
+
+	if len(times) > 0 {
+		var total time.Duration
+		for _, d := range times {
+			total += d
+		}
+		sort.Slice(times, func(i, j int) bool {
+			return times[i] < times[j]
+		})
+		segment.AvgResponseTime = total / time.Duration(len(times))
+		segment.P50ResponseTime = calculatePercentile(times, 0.50)
+		segment.P99ResponseTime = calculatePercentile(times, 0.99)
+	}
+
+	sr.resultsLock.Lock()
+	sr.RateSegments = append(sr.RateSegments, segment)
+	sr.resultsLock.Unlock()
+}
+
 // GetSortedStatusCodes 获取排序后的状态码列表
 func (sr *StressResult) GetSortedStatusCodes() []int {
 	sr.statusCodesLock.RLock()
@@ -130,6 +560,30 @@ func (sr *StressResult) GetStatusCodeCount(code int) int64 {
 	return sr.statusCodes[code]
 }
 
+// GetStatusCodes 获取状态码分布
+func (sr *StressResult) GetStatusCodes() map[int]int64 {
+	sr.statusCodesLock.RLock()
+	defer sr.statusCodesLock.RUnlock()
+
+	codes := make(map[int]int64, len(sr.statusCodes))
+	for code, count := range sr.statusCodes {
+		codes[code] = count
+	}
+	return codes
+}
+
+// GetErrorCounts 获取按错误信息统计的失败请求数
+func (sr *StressResult) GetErrorCounts() map[string]int64 {
+	sr.errorCountsLock.RLock()
+	defer sr.errorCountsLock.RUnlock()
+
+	counts := make(map[string]int64, len(sr.errorCounts))
+	for errorMsg, count := range sr.errorCounts {
+		counts[errorMsg] = count
+	}
+	return counts
+}
+
 // GetSortedErrors 获取排序后的错误列表
 func (sr *StressResult) GetSortedErrors() ([]ErrorItem, int64) {
 	sr.errorCountsLock.RLock()
@@ -151,31 +605,76 @@ func (sr *StressResult) GetSortedErrors() ([]ErrorItem, int64) {
 	return errorList, totalErrors
 }
 
+// GetErrorExamples 获取每种错误的示例请求，每种错误只保留第一次出现的请求
+func (sr *StressResult) GetErrorExamples() map[string]*RequestResult {
+	sr.errorCountsLock.RLock()
+	defer sr.errorCountsLock.RUnlock()
+
+	examples := make(map[string]*RequestResult, len(sr.errorExamples))
+	for errorMsg, example := range sr.errorExamples {
+		examples[errorMsg] = example
+	}
+	return examples
+}
+
+// GetErrorCategories 获取按类别统计的失败请求数
+func (sr *StressResult) GetErrorCategories() map[string]int64 {
+	sr.errorCountsLock.RLock()
+	defer sr.errorCountsLock.RUnlock()
+
+	categories := make(map[string]int64, len(sr.errorCategories))
+	for category, count := range sr.errorCategories {
+		categories[category] = count
+	}
+	return categories
+}
+
 // CalculateMetrics 计算最终指标
 func (sr *StressResult) CalculateMetrics() {
 	sr.TotalDuration = sr.EndTime.Sub(sr.StartTime)
 
 	// 计算分位数
 	sr.calculatePercentiles()
-}
+	sr.Histogram = sr.encodedHistogram()
 
-// calculatePercentiles 计算响应时间分位数
-func (sr *StressResult) calculatePercentiles() {
-	sr.resultsLock.RLock()
-	defer sr.resultsLock.RUnlock()
-
-	if len(sr.DetailedResults) == 0 {
-		return
+	if sr.CustomMetric != nil {
+		sr.CustomMetric.Calculate()
 	}
 
-	// 提取所有成功的响应时间
-	var responseTimes []time.Duration
-	for _, result := range sr.DetailedResults {
-		if result.Success {
-			responseTimes = append(responseTimes, result.Duration)
-		}
+	sr.Targets = sr.GetTargetStats()
+	sr.Comparison = sr.compare(sr.Targets)
+	sr.Methods = sr.GetMethodStats()
+	sr.StatusLatency = sr.GetStatusLatency()
+	sr.ConnectionHealth = sr.GetConnectionHealth()
+	sr.Burst = sr.calculateBurst()
+	sr.SuccessGoal = sr.GetSuccessGoal()
+	sr.RateLimit = sr.GetRateLimitStats()
+	sr.Compression = sr.GetCompressionStats()
+	sr.Batch = sr.GetBatchStats()
+	sr.Retry = sr.GetRetryStats()
+	sr.ConnectionPool = sr.GetPoolStats()
+	sr.DNS = sr.GetDNSStats()
+	sr.LatencyBreakdown = sr.GetLatencyBreakdown()
+	sr.SLACompliance = sr.GetSLACompliance()
+	sr.TLS = sr.GetTLSNegotiations()
+	sr.TLSResumption = sr.GetTLSResumption()
+	// 仅统计平均值的时间序列（用于慢启动检测）不写入报告
+	sr.seriesLock.Lock()
+	sampled := sr.seriesSamples
+	sr.seriesLock.Unlock()
+	if sampled {
+		sr.TimeSeries = sr.GetTimeSeries()
 	}
+	sr.StatusCodes = sr.GetStatusCodes()
+	sr.Errors = sr.GetErrorCounts()
+	sr.ErrorCategories = sr.GetErrorCategories()
+	sr.ErrorExamples = sr.GetErrorExamples()
+}
 
+// calculatePercentiles 计算响应时间分位数
+func (sr *StressResult) calculatePercentiles() {
+	// 提取所有成功的响应时间
+	responseTimes := sr.successfulDurations()
 	if len(responseTimes) == 0 {
 		return
 	}
@@ -196,9 +695,24 @@ func (sr *StressResult) calculatePercentiles() {
 	})
 
 	// 计算分位数
-	sr.P50ResponseTime = calculatePercentile(responseTimes, 0.50)
-	sr.P90ResponseTime = calculatePercentile(responseTimes, 0.90)
-	sr.P99ResponseTime = calculatePercentile(responseTimes, 0.99)
+	hist := sr.fullHistogram()
+	percentile := percentileFunc(responseTimes, hist)
+	sr.P50ResponseTime = percentile(0.50)
+	sr.P90ResponseTime = percentile(0.90)
+	sr.P99ResponseTime = percentile(0.99)
+	if len(sr.percentileList) > 0 {
+		sr.Percentiles = make(map[float64]time.Duration, len(sr.percentileList))
+		for _, p := range sr.percentileList {
+			sr.Percentiles[p] = percentile(p / 100)
+		}
+	}
+
+	_, sr.StdDevResponseTime = meanStdDev(responseTimes)
+	sr.Trimmed = trimStats(responseTimes, sr.trimPercent)
+	sr.Corrected = sr.correctedLatency(responseTimes, hist)
+	if sr.openModel {
+		sr.Corrected = sr.measuredLatency(hist)
+	}
 }
 
 // calculatePercentile 计算分位数
@@ -219,13 +733,26 @@ func calculatePercentile(sortedData []time.Duration, percentile float64) time.Du
 	return time.Duration(float64(sortedData[lower])*(1-weight) + float64(sortedData[upper])*weight)
 }
 
+// SetFailureThreshold 设置判定失败的错误率（百分比），0 表示使用 DefaultMaxErrorRate
+func (sr *StressResult) SetFailureThreshold(percent float64) {
+	sr.failureThreshold = percent
+}
+
+// FailureThreshold 返回判定失败的错误率（百分比）
+func (sr *StressResult) FailureThreshold() float64 {
+	if sr.failureThreshold <= 0 {
+		return DefaultMaxErrorRate
+	}
+	return sr.failureThreshold
+}
+
 // ShouldFail 根据错误率决定是否应该失败
 func (sr *StressResult) ShouldFail() bool {
 	if sr.TotalRequests == 0 {
 		return false
 	}
-	failureRate := float64(sr.FailedRequests) / float64(sr.TotalRequests)
-	return failureRate > 0.1 // 10% 错误率阈值
+	failureRate := float64(sr.gatedFailures()) / float64(sr.TotalRequests) * 100
+	return failureRate > sr.FailureThreshold()
 }
 
 // GetRequestsPerSecond 计算每秒请求数
@@ -236,6 +763,22 @@ func (sr *StressResult) GetRequestsPerSecond() float64 {
 	return float64(sr.TotalRequests) / sr.TotalDuration.Seconds()
 }
 
+// GetBytesSentPerSecond 计算每秒发送的请求体字节数
+func (sr *StressResult) GetBytesSentPerSecond() float64 {
+	if sr.TotalDuration == 0 {
+		return 0
+	}
+	return float64(sr.TotalBytesSent) / sr.TotalDuration.Seconds()
+}
+
+// GetBytesReceivedPerSecond 计算每秒收到的响应体字节数
+func (sr *StressResult) GetBytesReceivedPerSecond() float64 {
+	if sr.TotalDuration == 0 {
+		return 0
+	}
+	return float64(sr.TotalBytesReceived) / sr.TotalDuration.Seconds()
+}
+
 // GetAverageResponseTime 计算平均响应时间
 func (sr *StressResult) GetAverageResponseTime() time.Duration {
 	if sr.TotalRequests == 0 {
@@ -244,6 +787,15 @@ func (sr *StressResult) GetAverageResponseTime() time.Duration {
 	return time.Duration(sr.TotalResponseTime / sr.TotalRequests)
 }
 
+// GetRequestsPerConnection 计算每个连接平均承载的请求数
+func (sr *StressResult) GetRequestsPerConnection() float64 {
+	newConns := atomic.LoadInt64(&sr.NewConnections)
+	if newConns == 0 {
+		return 0
+	}
+	return float64(atomic.LoadInt64(&sr.TotalRequests)) / float64(newConns)
+}
+
 // GetSuccessRate 计算成功率
 func (sr *StressResult) GetSuccessRate() float64 {
 	if sr.TotalRequests == 0 {
@@ -269,6 +821,16 @@ func (sr *StressResult) GetMaxResponseTime() time.Duration {
 	return sr.MaxResponseTime
 }
 
+// SetExcludeShutdownCanceled 设置是否将压测停止时被取消的请求排除在统计之外，需在添加结果前调用
+func (sr *StressResult) SetExcludeShutdownCanceled(exclude bool) {
+	sr.excludeShutdownCanceled = exclude
+}
+
+// ShutdownCanceledExcluded 返回被取消的请求是否已排除在统计之外
+func (sr *StressResult) ShutdownCanceledExcluded() bool {
+	return sr.excludeShutdownCanceled
+}
+
 // SetMaxResults 设置最大结果记录数
 func (sr *StressResult) SetMaxResults(max int) {
 	sr.resultsLock.Lock()
@@ -0,0 +1,37 @@
+package types
+
+import "sync/atomic"
+
+// RetryStats -retry-count 重试统计，成功率按最终结果计算，重试后成功的请求单独统计
+type RetryStats struct {
+	RetriedRequests  int64 `json:"retried_requests"`  // 至少重试过一次的请求数
+	Retries          int64 `json:"retries"`           // 重试的总次数
+	RetriedSucceeded int64 `json:"retried_succeeded"` // 重试后成功的请求数
+}
+
+// addRetryResult 统计重试过的请求
+func (sr *StressResult) addRetryResult(result *RequestResult) {
+	if result.Retries == 0 {
+		return
+	}
+
+	atomic.AddInt64(&sr.retriedRequests, 1)
+	atomic.AddInt64(&sr.retries, int64(result.Retries))
+	if result.Success {
+		atomic.AddInt64(&sr.retriedSucceeded, 1)
+	}
+}
+
+// GetRetryStats 汇总重试统计，没有重试过的请求时返回 nil
+func (sr *StressResult) GetRetryStats() *RetryStats {
+	retried := atomic.LoadInt64(&sr.retriedRequests)
+	if retried == 0 {
+		return nil
+	}
+
+	return &RetryStats{
+		RetriedRequests:  retried,
+		Retries:          atomic.LoadInt64(&sr.retries),
+		RetriedSucceeded: atomic.LoadInt64(&sr.retriedSucceeded),
+	}
+}
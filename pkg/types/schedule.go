@@ -0,0 +1,49 @@
+package types
+
+import "time"
+
+// 突刺测试默认参数
+const (
+	DefaultSpikeBaselineRate     = 10.0
+	DefaultSpikeRate             = 100.0
+	DefaultSpikeBaselineDuration = 30 * time.Second
+	DefaultSpikeDuration         = 10 * time.Second
+	DefaultSpikeRecoveryDuration = 30 * time.Second
+)
+
+// RateStep 速率计划中的一个阶段
+type RateStep struct {
+	Label    string        `json:"label,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Rate     float64       `json:"rate"` // 目标速率（请求/秒）
+}
+
+// RateSegment 速率计划阶段的实际执行结果
+type RateSegment struct {
+	Label           string        `json:"label,omitempty"`
+	TargetRate      float64       `json:"target_rate"`
+	Duration        time.Duration `json:"duration"`
+	Requests        int64         `json:"requests"`
+	AchievedRate    float64       `json:"achieved_rate"`
+	AvgResponseTime time.Duration `json:"avg_response_time"`
+	P50ResponseTime time.Duration `json:"p50_response_time"`
+	P99ResponseTime time.Duration `json:"p99_response_time"`
+}
+
+// SpikeSteps 生成突刺测试的速率计划：基线、突刺、恢复三个阶段
+func SpikeSteps(baselineRate, spikeRate float64, baselineDuration, spikeDuration, recoveryDuration time.Duration) []RateStep {
+	return []RateStep{
+		{Label: "baseline", Duration: baselineDuration, Rate: baselineRate},
+		{Label: "spike", Duration: spikeDuration, Rate: spikeRate},
+		{Label: "recovery", Duration: recoveryDuration, Rate: baselineRate},
+	}
+}
+
+// TotalScheduleDuration 计算速率计划的总时长
+func TotalScheduleDuration(steps []RateStep) time.Duration {
+	var total time.Duration
+	for _, step := range steps {
+		total += step.Duration
+	}
+	return total
+}
@@ -0,0 +1,56 @@
+package types
+
+import "time"
+
+// SLACompliance 延迟 SLA 的达标情况：成功且响应时间不超过 SLA 的请求所占百分比。
+// 整体达标率可能掩盖短时间的不达标，因此同时给出达标率最低的一秒
+type SLACompliance struct {
+	SLA             time.Duration `json:"sla"`
+	Compliance      float64       `json:"compliance"`       // 整体达标百分比
+	WorstSecond     int           `json:"worst_second"`     // 达标率最低的一秒（相对测试开始），相同时取最早的一秒
+	WorstCompliance float64       `json:"worst_compliance"` // 该秒的达标百分比
+	WorstRequests   int64         `json:"worst_requests"`   // 该秒完成的请求数
+}
+
+// SetLatencySLA 设置延迟 SLA，需在压测开始前调用，并开启时间序列统计
+func (sr *StressResult) SetLatencySLA(sla time.Duration) {
+	sr.seriesLock.Lock()
+	defer sr.seriesLock.Unlock()
+	sr.latencySLA = sla
+}
+
+// GetSLACompliance 按秒汇总延迟 SLA 达标率，未设置 SLA 或没有请求时返回 nil
+func (sr *StressResult) GetSLACompliance() *SLACompliance {
+	sr.seriesLock.Lock()
+	sla := sr.latencySLA
+	sr.seriesLock.Unlock()
+	if sla <= 0 {
+		return nil
+	}
+
+	var requests, met int64
+	var worst *TimeSeriesPoint
+	points := sr.GetTimeSeries()
+	for i := range points {
+		point := &points[i]
+		if point.Requests == 0 {
+			continue
+		}
+		requests += point.Requests
+		met += point.SLAMet
+		if worst == nil || point.SLACompliance() < worst.SLACompliance() {
+			worst = point
+		}
+	}
+	if requests == 0 {
+		return nil
+	}
+
+	return &SLACompliance{
+		SLA:             sla,
+		Compliance:      float64(met) / float64(requests) * 100,
+		WorstSecond:     worst.Second,
+		WorstCompliance: worst.SLACompliance(),
+		WorstRequests:   worst.Requests,
+	}
+}
@@ -0,0 +1,122 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultMaxErrorRate 默认允许的最大错误率（百分比）
+const DefaultMaxErrorRate = 10.0
+
+// SLOThresholds 压测通过条件，延迟门限为 0 表示不检查
+type SLOThresholds struct {
+	MaxErrorRate float64 // 百分比
+	MaxAvg       time.Duration
+	MaxP50       time.Duration
+	MaxP90       time.Duration
+	MaxP99       time.Duration
+}
+
+// 进程退出原因，门限未通过时为 "<门限 ID>_exceeded"
+const (
+	ExitReasonOK          = "ok"
+	ExitReasonConfigError = "config_error"
+	ExitReasonEngineError = "engine_error"
+	ExitReasonFailFast    = "fail_fast"
+	ExitReasonNoRequests  = "no_requests"
+)
+
+// ExitStatus 进程退出原因与退出码
+type ExitStatus struct {
+	Reason string `json:"exit_reason"`
+	Code   int    `json:"exit_code"`
+}
+
+// SLOGate 单个门限的检查结果
+type SLOGate struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Actual    string `json:"actual"`
+	Threshold string `json:"threshold"`
+	Passed    bool   `json:"passed"`
+}
+
+// SLOReport 所有门限的检查结果
+type SLOReport struct {
+	Passed bool      `json:"passed"`
+	Gates  []SLOGate `json:"gates"`
+}
+
+// FailedGates 返回未通过的门限
+func (r SLOReport) FailedGates() []SLOGate {
+	var failed []SLOGate
+	for _, gate := range r.Gates {
+		if !gate.Passed {
+			failed = append(failed, gate)
+		}
+	}
+	return failed
+}
+
+// ExitStatus 根据门限检查结果返回退出状态，多个门限未通过时以第一个为准
+func (r SLOReport) ExitStatus() ExitStatus {
+	for _, gate := range r.Gates {
+		if !gate.Passed {
+			return ExitStatus{Reason: gate.ID + "_exceeded", Code: 1}
+		}
+	}
+	return ExitStatus{Reason: ExitReasonOK, Code: 0}
+}
+
+// EvaluateSLOs 按门限检查压测结果，需在 CalculateMetrics 之后调用
+func (sr *StressResult) EvaluateSLOs(thresholds SLOThresholds) SLOReport {
+	report := SLOReport{Passed: true}
+	add := func(gate SLOGate) {
+		report.Gates = append(report.Gates, gate)
+		if !gate.Passed {
+			report.Passed = false
+		}
+	}
+
+	maxErrorRate := thresholds.MaxErrorRate
+	if maxErrorRate <= 0 {
+		maxErrorRate = DefaultMaxErrorRate
+	}
+	var errorRate float64
+	if sr.TotalRequests > 0 {
+		errorRate = float64(sr.gatedFailures()) / float64(sr.TotalRequests) * 100
+	}
+	add(SLOGate{
+		ID:        "error_rate",
+		Name:      "error rate",
+		Actual:    fmt.Sprintf("%.2f%%", errorRate),
+		Threshold: fmt.Sprintf("<= %.2f%%", maxErrorRate),
+		Passed:    errorRate <= maxErrorRate,
+	})
+
+	latencyGates := []struct {
+		id        string
+		name      string
+		actual    time.Duration
+		threshold time.Duration
+	}{
+		{"avg", "avg response time", sr.GetAverageResponseTime(), thresholds.MaxAvg},
+		{"p50", "p50 response time", sr.P50ResponseTime, thresholds.MaxP50},
+		{"p90", "p90 response time", sr.P90ResponseTime, thresholds.MaxP90},
+		{"p99", "p99 response time", sr.P99ResponseTime, thresholds.MaxP99},
+	}
+	for _, g := range latencyGates {
+		if g.threshold <= 0 {
+			continue
+		}
+		add(SLOGate{
+			ID:        g.id,
+			Name:      g.name,
+			Actual:    g.actual.String(),
+			Threshold: "<= " + g.threshold.String(),
+			Passed:    g.actual <= g.threshold,
+		})
+	}
+
+	return report
+}
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// StatusLatency 某个状态码的响应时间。限流等快速返回的响应会拉低整体分位数，按状态码分开统计才有意义
+type StatusLatency struct {
+	StatusCode      int           `json:"status_code"`
+	Count           int64         `json:"count"`
+	P50ResponseTime time.Duration `json:"p50_response_time"`
+	P99ResponseTime time.Duration `json:"p99_response_time"`
+}
+
+// statusSamples 某个状态码的请求数和响应时间样本，样本数达到上限后按环形缓冲区覆盖最旧的样本
+type statusSamples struct {
+	count   int64
+	samples []time.Duration
+	next    int
+}
+
+// addStatusLatency 按状态码记录响应时间
+func (sr *StressResult) addStatusLatency(result *RequestResult) {
+	sr.statusLatencyLock.Lock()
+	defer sr.statusLatencyLock.Unlock()
+
+	if sr.statusSamples == nil {
+		sr.statusSamples = make(map[int]*statusSamples)
+	}
+	stats, ok := sr.statusSamples[result.StatusCode]
+	if !ok {
+		stats = &statusSamples{}
+		sr.statusSamples[result.StatusCode] = stats
+	}
+
+	stats.count++
+	if len(stats.samples) < sr.maxResults {
+		stats.samples = append(stats.samples, result.Duration)
+		return
+	}
+	stats.samples[stats.next] = result.Duration
+	stats.next = (stats.next + 1) % len(stats.samples)
+}
+
+// GetStatusLatency 按状态码升序返回各状态码的响应时间分位数，没有收到响应时返回 nil
+func (sr *StressResult) GetStatusLatency() []StatusLatency {
+	sr.statusLatencyLock.Lock()
+	defer sr.statusLatencyLock.Unlock()
+
+	if len(sr.statusSamples) == 0 {
+		return nil
+	}
+
+	codes := make([]int, 0, len(sr.statusSamples))
+	for code := range sr.statusSamples {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	latency := make([]StatusLatency, 0, len(codes))
+	for _, code := range codes {
+		stats := sr.statusSamples[code]
+		times := make([]time.Duration, len(stats.samples))
+		copy(times, stats.samples)
+		sort.Slice(times, func(i, j int) bool {
+			return times[i] < times[j]
+		})
+		latency = append(latency, StatusLatency{
+			StatusCode:      code,
+			Count:           stats.count,
+			P50ResponseTime: calculatePercentile(times, 0.50),
+			P99ResponseTime: calculatePercentile(times, 0.99),
+		})
+	}
+	return latency
+}
+
+// GetResponseStatusCounts 返回收到的各状态码的响应数，包括被判定为失败的响应，可在压测进行中调用
+func (sr *StressResult) GetResponseStatusCounts() map[int]int64 {
+	sr.statusLatencyLock.Lock()
+	defer sr.statusLatencyLock.Unlock()
+
+	counts := make(map[int]int64, len(sr.statusSamples))
+	for code, stats := range sr.statusSamples {
+		counts[code] = stats.count
+	}
+	return counts
+}
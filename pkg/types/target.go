@@ -0,0 +1,101 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TargetSpec 多目标压测中的单个目标
+type TargetSpec struct {
+	Name    string            `mapstructure:"name" json:"name,omitempty" yaml:"name,omitempty"`
+	Method  string            `mapstructure:"method" json:"method,omitempty" yaml:"method,omitempty"`
+	URL     string            `mapstructure:"url" json:"url" yaml:"url"`
+	Weight  int               `mapstructure:"weight" json:"weight,omitempty" yaml:"weight,omitempty"`
+	Body    string            `mapstructure:"body" json:"body,omitempty" yaml:"body,omitempty"`
+	Headers map[string]string `mapstructure:"headers" json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// 目标自身的成功判定条件，未设置时使用全局规则（状态码小于 400）
+	ExpectStatus       []int  `mapstructure:"expect_status" json:"expect_status,omitempty" yaml:"expect_status,omitempty"`
+	ExpectBodyContains string `mapstructure:"expect_body_contains" json:"expect_body_contains,omitempty" yaml:"expect_body_contains,omitempty"`
+}
+
+// DisplayName 返回目标在报告中的名称
+func (t *TargetSpec) DisplayName() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	method := t.Method
+	if method == "" {
+		method = "GET"
+	}
+	return strings.ToUpper(method) + " " + t.URL
+}
+
+// CheckResponse 按目标的判定条件检查响应，返回失败原因，通过时返回空字符串
+func (t *TargetSpec) CheckResponse(statusCode int, body []byte) string {
+	if len(t.ExpectStatus) > 0 {
+		matched := false
+		for _, code := range t.ExpectStatus {
+			if code == statusCode {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Sprintf("HTTP %d: unexpected status (expected %s)", statusCode, formatStatusList(t.ExpectStatus))
+		}
+	} else if statusCode >= 400 {
+		return fmt.Sprintf("HTTP %d", statusCode)
+	}
+
+	if t.ExpectBodyContains != "" && !strings.Contains(string(body), t.ExpectBodyContains) {
+		return fmt.Sprintf("response body does not contain %q", t.ExpectBodyContains)
+	}
+
+	return ""
+}
+
+// TargetStats 单个目标的统计
+type TargetStats struct {
+	Name               string `json:"name"`
+	TotalRequests      int64  `json:"total_requests"`
+	SuccessfulRequests int64  `json:"successful_requests"`
+	FailedRequests     int64  `json:"failed_requests"`
+	Concurrency        int    `json:"concurrency,omitempty"` // 独立工作协程数，共享协程时为 0
+
+	AvgResponseTime time.Duration `json:"avg_response_time"`
+	P50ResponseTime time.Duration `json:"p50_response_time"`
+	P99ResponseTime time.Duration `json:"p99_response_time"`
+
+	totalTime time.Duration
+	samples   []time.Duration
+}
+
+// GetSuccessRate 计算目标的成功率
+func (ts *TargetStats) GetSuccessRate() float64 {
+	if ts.TotalRequests == 0 {
+		return 0
+	}
+	return float64(ts.SuccessfulRequests) / float64(ts.TotalRequests) * 100
+}
+
+// sortedSamples 返回成功请求响应时间样本的有序副本
+func (ts *TargetStats) sortedSamples() []time.Duration {
+	times := make([]time.Duration, len(ts.samples))
+	copy(times, ts.samples)
+	sort.Slice(times, func(i, j int) bool {
+		return times[i] < times[j]
+	})
+	return times
+}
+
+// formatStatusList 格式化状态码列表
+func formatStatusList(codes []int) string {
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = fmt.Sprintf("%d", code)
+	}
+	return strings.Join(parts, ",")
+}
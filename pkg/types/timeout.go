@@ -0,0 +1,28 @@
+package types
+
+// SetTimeoutsAsWarnings 设置超时是否只作提示：超时通常意味着服务端过载而非出错，
+// 开启后超时仍计入失败数，但不计入错误率门限和高失败率警告。需在压测开始前调用
+func (sr *StressResult) SetTimeoutsAsWarnings(warn bool) {
+	sr.timeoutsWarn = warn
+}
+
+// TimeoutsAsWarnings 返回超时是否只作提示
+func (sr *StressResult) TimeoutsAsWarnings() bool {
+	return sr.timeoutsWarn
+}
+
+// GetTimeoutRate 返回超时请求占总请求数的百分比
+func (sr *StressResult) GetTimeoutRate() float64 {
+	if sr.TotalRequests == 0 {
+		return 0
+	}
+	return float64(sr.Timeouts) / float64(sr.TotalRequests) * 100
+}
+
+// gatedFailures 返回计入错误率门限的失败请求数
+func (sr *StressResult) gatedFailures() int64 {
+	if sr.timeoutsWarn {
+		return sr.FailedRequests - sr.Timeouts
+	}
+	return sr.FailedRequests
+}
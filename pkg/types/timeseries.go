@@ -0,0 +1,191 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// maxSamplesPerSecond 每秒保留的响应时间样本上限
+const maxSamplesPerSecond = 1000
+
+// TimeSeriesPoint 每秒统计数据
+type TimeSeriesPoint struct {
+	Second          int           `json:"second"`
+	Requests        int64         `json:"requests"`
+	Errors          int64         `json:"errors"`
+	AvgResponseTime time.Duration `json:"avg_response_time"`
+	P50ResponseTime time.Duration `json:"p50_response_time"`
+	P90ResponseTime time.Duration `json:"p90_response_time"`
+	P99ResponseTime time.Duration `json:"p99_response_time"`
+	SLAMet          int64         `json:"sla_met,omitempty"` // 满足 -sla 的请求数
+}
+
+// SLACompliance 该秒满足延迟 SLA 的请求百分比，没有请求时返回 0
+func (p TimeSeriesPoint) SLACompliance() float64 {
+	if p.Requests == 0 {
+		return 0
+	}
+	return float64(p.SLAMet) / float64(p.Requests) * 100
+}
+
+// timeSeriesBucket 单个秒级桶
+type timeSeriesBucket struct {
+	requests  int64
+	errors    int64
+	totalTime time.Duration // 成功请求的响应时间之和
+	slaMet    int64
+	samples   []time.Duration
+}
+
+// EnableTimeSeries 开启按秒统计，需在压测开始前调用
+// percentiles 为 false 时只统计请求数与平均响应时间，不保留样本，适合长时间运行
+func (sr *StressResult) EnableTimeSeries(percentiles bool) {
+	sr.seriesLock.Lock()
+	defer sr.seriesLock.Unlock()
+
+	if sr.seriesBuckets == nil {
+		sr.seriesBuckets = make(map[int]*timeSeriesBucket)
+	}
+	sr.seriesSamples = sr.seriesSamples || percentiles
+}
+
+// addTimeSeriesResult 将请求结果计入所在秒的桶
+func (sr *StressResult) addTimeSeriesResult(result *RequestResult) {
+	sr.seriesLock.Lock()
+	defer sr.seriesLock.Unlock()
+
+	if sr.seriesBuckets == nil {
+		return
+	}
+
+	second := 0
+	if !sr.StartTime.IsZero() && result.Timestamp.After(sr.StartTime) {
+		second = int(result.Timestamp.Sub(sr.StartTime) / time.Second)
+	}
+
+	bucket, ok := sr.seriesBuckets[second]
+	if !ok {
+		bucket = &timeSeriesBucket{}
+		sr.seriesBuckets[second] = bucket
+	}
+
+	bucket.requests++
+	if !result.Success {
+		bucket.errors++
+		return
+	}
+	bucket.totalTime += result.Duration
+	if sr.latencySLA > 0 && result.Duration <= sr.latencySLA {
+		bucket.slaMet++
+	}
+	if sr.seriesSamples && len(bucket.samples) < maxSamplesPerSecond {
+		bucket.samples = append(bucket.samples, result.Duration)
+	}
+}
+
+// GetTimeSeries 返回按秒排序的统计数据，没有请求的秒以零值补齐
+func (sr *StressResult) GetTimeSeries() []TimeSeriesPoint {
+	sr.seriesLock.Lock()
+	defer sr.seriesLock.Unlock()
+
+	if len(sr.seriesBuckets) == 0 {
+		return nil
+	}
+
+	last := 0
+	for second := range sr.seriesBuckets {
+		if second > last {
+			last = second
+		}
+	}
+
+	points := make([]TimeSeriesPoint, 0, last+1)
+	for second := 0; second <= last; second++ {
+		point := TimeSeriesPoint{Second: second}
+		if bucket, ok := sr.seriesBuckets[second]; ok {
+			point.Requests = bucket.requests
+			point.Errors = bucket.errors
+			point.SLAMet = bucket.slaMet
+			if successes := bucket.requests - bucket.errors; successes > 0 {
+				point.AvgResponseTime = bucket.totalTime / time.Duration(successes)
+			}
+
+			times := make([]time.Duration, len(bucket.samples))
+			copy(times, bucket.samples)
+			sort.Slice(times, func(i, j int) bool {
+				return times[i] < times[j]
+			})
+			point.P50ResponseTime = calculatePercentile(times, 0.50)
+			point.P90ResponseTime = calculatePercentile(times, 0.90)
+			point.P99ResponseTime = calculatePercentile(times, 0.99)
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// SlowStart 预热阶段延迟明显高于稳定阶段的检测结果
+type SlowStart struct {
+	Window        time.Duration `json:"window"`
+	WarmupAvg     time.Duration `json:"warmup_avg"`
+	SteadyAvg     time.Duration `json:"steady_avg"`
+	Ratio         float64       `json:"ratio"`
+	OverallSkew   float64       `json:"overall_skew"` // 预热使整体平均响应时间偏高的比例
+	WarmupSamples int64         `json:"warmup_samples"`
+}
+
+// DefaultSlowStartWindow 视为预热阶段的起始时间窗口
+const DefaultSlowStartWindow = 3 * time.Second
+
+// minSlowStartSkew 预热对整体平均值的影响低于该比例时不提示
+const minSlowStartSkew = 0.1
+
+// DetectSlowStart 比较起始窗口与之后各秒的平均响应时间，
+// 当起始窗口平均值达到稳定阶段的 ratio 倍且明显拉高整体平均值时返回检测结果，否则返回 nil
+func (sr *StressResult) DetectSlowStart(window time.Duration, ratio float64) *SlowStart {
+	if ratio <= 0 {
+		return nil
+	}
+
+	sr.seriesLock.Lock()
+	defer sr.seriesLock.Unlock()
+
+	windowSeconds := int(window / time.Second)
+	var warmupTime, steadyTime time.Duration
+	var warmupCount, steadyCount int64
+	for second, bucket := range sr.seriesBuckets {
+		successes := bucket.requests - bucket.errors
+		if second < windowSeconds {
+			warmupTime += bucket.totalTime
+			warmupCount += successes
+		} else {
+			steadyTime += bucket.totalTime
+			steadyCount += successes
+		}
+	}
+
+	if warmupCount == 0 || steadyCount == 0 {
+		return nil
+	}
+
+	warmupAvg := warmupTime / time.Duration(warmupCount)
+	steadyAvg := steadyTime / time.Duration(steadyCount)
+	if steadyAvg <= 0 || float64(warmupAvg) < float64(steadyAvg)*ratio {
+		return nil
+	}
+
+	overallAvg := (warmupTime + steadyTime) / time.Duration(warmupCount+steadyCount)
+	skew := float64(overallAvg-steadyAvg) / float64(steadyAvg)
+	if skew < minSlowStartSkew {
+		return nil
+	}
+
+	return &SlowStart{
+		Window:        window,
+		WarmupAvg:     warmupAvg,
+		SteadyAvg:     steadyAvg,
+		Ratio:         float64(warmupAvg) / float64(steadyAvg),
+		OverallSkew:   skew,
+		WarmupSamples: warmupCount,
+	}
+}
@@ -0,0 +1,117 @@
+package types
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// TLSNegotiation 协商得到的 TLS 版本和密码套件及使用该组合的请求数
+type TLSNegotiation struct {
+	Version     string `json:"version"`
+	CipherSuite string `json:"cipher_suite"`
+	Requests    int64  `json:"requests"`
+}
+
+// tlsKey 协商结果的统计键
+type tlsKey struct {
+	version     string
+	cipherSuite string
+}
+
+// addTLSResult 按协商的 TLS 版本和密码套件计数
+func (sr *StressResult) addTLSResult(result *RequestResult) {
+	sr.tlsLock.Lock()
+	defer sr.tlsLock.Unlock()
+
+	if sr.tlsCounts == nil {
+		sr.tlsCounts = make(map[tlsKey]int64)
+	}
+	sr.tlsCounts[tlsKey{result.TLSVersion, result.TLSCipher}]++
+}
+
+// GetTLSNegotiations 获取协商的 TLS 版本和密码套件，按请求数降序排列
+func (sr *StressResult) GetTLSNegotiations() []TLSNegotiation {
+	sr.tlsLock.Lock()
+	defer sr.tlsLock.Unlock()
+
+	if len(sr.tlsCounts) == 0 {
+		return nil
+	}
+
+	negotiations := make([]TLSNegotiation, 0, len(sr.tlsCounts))
+	for key, count := range sr.tlsCounts {
+		negotiations = append(negotiations, TLSNegotiation{Version: key.version, CipherSuite: key.cipherSuite, Requests: count})
+	}
+	sort.Slice(negotiations, func(i, j int) bool {
+		if negotiations[i].Requests != negotiations[j].Requests {
+			return negotiations[i].Requests > negotiations[j].Requests
+		}
+		return negotiations[i].CipherSuite < negotiations[j].CipherSuite
+	})
+	return negotiations
+}
+
+// TLSResumption TLS 会话恢复统计，恢复率低说明完整握手占用了大量开销
+type TLSResumption struct {
+	Handshakes     int64   `json:"handshakes"`
+	Resumed        int64   `json:"resumed"`
+	FullHandshakes int64   `json:"full_handshakes"`
+	ResumptionRate float64 `json:"resumption_rate"` // 百分比
+}
+
+// AddTLSHandshake 记录一次 TLS 握手，resumed 表示复用了之前的会话
+func (sr *StressResult) AddTLSHandshake(resumed bool) {
+	atomic.AddInt64(&sr.tlsHandshakes, 1)
+	if resumed {
+		atomic.AddInt64(&sr.tlsResumed, 1)
+	}
+}
+
+// GetTLSResumption 汇总 TLS 会话恢复统计，没有发生 TLS 握手时返回 nil
+func (sr *StressResult) GetTLSResumption() *TLSResumption {
+	handshakes := atomic.LoadInt64(&sr.tlsHandshakes)
+	if handshakes == 0 {
+		return nil
+	}
+
+	resumed := atomic.LoadInt64(&sr.tlsResumed)
+	return &TLSResumption{
+		Handshakes:     handshakes,
+		Resumed:        resumed,
+		FullHandshakes: handshakes - resumed,
+		ResumptionRate: float64(resumed) / float64(handshakes) * 100,
+	}
+}
+
+// TLSOverhead TLS 握手在平均响应时间中的占比，按所有请求平均，复用连接的请求握手耗时为 0
+type TLSOverhead struct {
+	AvgHandshake time.Duration `json:"avg_handshake"`
+	AvgResponse  time.Duration `json:"avg_response"`
+	Percent      float64       `json:"percent"`
+}
+
+// DetectTLSOverhead 当 TLS 握手占平均响应时间的比例达到 percent 时返回检测结果，否则返回 nil
+func (sr *StressResult) DetectTLSOverhead(percent float64) *TLSOverhead {
+	if percent <= 0 {
+		return nil
+	}
+
+	requests := atomic.LoadInt64(&sr.TotalRequests)
+	totalTime := atomic.LoadInt64(&sr.TotalResponseTime)
+	tlsTime := atomic.LoadInt64(&sr.tlsTime)
+	if requests == 0 || totalTime == 0 || tlsTime == 0 {
+		return nil
+	}
+
+	share := float64(tlsTime) / float64(totalTime) * 100
+	if share < percent {
+		return nil
+	}
+
+	return &TLSOverhead{
+		AvgHandshake: time.Duration(tlsTime / requests),
+		AvgResponse:  time.Duration(totalTime / requests),
+		Percent:      share,
+	}
+}
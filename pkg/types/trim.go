@@ -0,0 +1,63 @@
+package types
+
+import (
+	"math"
+	"time"
+)
+
+// TrimmedStats 去掉最快和最慢各 Percent% 的样本后计算的平均值和标准差，
+// 用于降低少量异常值在网络抖动环境下对集中趋势的影响
+type TrimmedStats struct {
+	Percent            float64       `json:"percent"`
+	Excluded           int           `json:"excluded"` // 两端共去掉的样本数
+	AvgResponseTime    time.Duration `json:"avg_response_time"`
+	StdDevResponseTime time.Duration `json:"stddev_response_time"`
+}
+
+// SetTrimPercent 设置计算截尾统计时两端各去掉的百分比，0 表示不计算
+func (sr *StressResult) SetTrimPercent(percent float64) {
+	sr.trimPercent = percent
+}
+
+// trimStats 对已排序的响应时间计算截尾统计，percent 为 0 时返回 nil
+func trimStats(sorted []time.Duration, percent float64) *TrimmedStats {
+	if percent <= 0 || len(sorted) == 0 {
+		return nil
+	}
+
+	k := int(float64(len(sorted)) * percent / 100)
+	if 2*k >= len(sorted) {
+		k = (len(sorted) - 1) / 2
+	}
+
+	trimmed := sorted[k : len(sorted)-k]
+	avg, stddev := meanStdDev(trimmed)
+	return &TrimmedStats{
+		Percent:            percent,
+		Excluded:           2 * k,
+		AvgResponseTime:    avg,
+		StdDevResponseTime: stddev,
+	}
+}
+
+// meanStdDev 计算平均值和总体标准差
+func meanStdDev(data []time.Duration) (time.Duration, time.Duration) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, d := range data {
+		sum += float64(d)
+	}
+	mean := sum / float64(len(data))
+
+	var variance float64
+	for _, d := range data {
+		diff := float64(d) - mean
+		variance += diff * diff
+	}
+	variance /= float64(len(data))
+
+	return time.Duration(mean), time.Duration(math.Sqrt(variance))
+}
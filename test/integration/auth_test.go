@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 4)
+	cfg.TokenCommand = "echo secret-token"
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(4), result.SuccessfulRequests)
+}
+
+func TestStaticAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); ok && user == "admin" && pass == "p:ss" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if r.Header.Get("Authorization") == "Bearer static-token" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	t.Run("basic", func(t *testing.T) {
+		cfg := newLocalConfig(server.URL, 4)
+		cfg.BasicAuthUser = "admin"
+		cfg.BasicAuthPass = "p:ss"
+
+		tester, err := engine.NewStressEngine(cfg)
+		require.NoError(t, err)
+		defer tester.Cleanup()
+
+		result := tester.Run()
+		assert.Equal(t, int64(4), result.SuccessfulRequests)
+	})
+
+	t.Run("bearer", func(t *testing.T) {
+		cfg := newLocalConfig(server.URL, 4)
+		cfg.BearerToken = "static-token"
+
+		tester, err := engine.NewStressEngine(cfg)
+		require.NoError(t, err)
+		defer tester.Cleanup()
+
+		result := tester.Run()
+		assert.Equal(t, int64(4), result.SuccessfulRequests)
+	})
+}
+
+func TestTokenCommand_Failure(t *testing.T) {
+	cfg := newLocalConfig("http://127.0.0.1:1", 1)
+	cfg.TokenCommand = "echo boom >&2; exit 3"
+
+	_, err := engine.NewStressEngine(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "token command failed")
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestTokenRefreshInterval(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.Header.Get("Authorization")]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 每次执行命令都会生成一个新的令牌
+	counter := filepath.Join(t.TempDir(), "counter")
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Duration = 400 * time.Millisecond
+	cfg.TokenCommand = fmt.Sprintf(`n=$(cat %[1]s 2>/dev/null || echo 0); n=$((n+1)); echo $n > %[1]s; echo token-$n`, counter)
+	cfg.TokenRefreshInterval = 50 * time.Millisecond
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Greater(t, result.SuccessfulRequests, int64(0))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Greater(t, seen["Bearer token-1"], 0)
+	assert.Greater(t, len(seen), 1, "token should have been rotated during the run")
+}
+
+func TestCookies(t *testing.T) {
+	// 没有会话 cookie 的请求返回 401 并设置会话
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1", Path: "/"})
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	run := func(t *testing.T, setup func(cfg *config.Config)) int64 {
+		cfg := newLocalConfig(server.URL, 4)
+		cfg.Concurrency = 1
+		setup(cfg)
+		require.NoError(t, cfg.Validate())
+
+		tester, err := engine.NewStressEngine(cfg)
+		require.NoError(t, err)
+		defer tester.Cleanup()
+
+		return tester.Run().SuccessfulRequests
+	}
+
+	t.Run("jar disabled by default", func(t *testing.T) {
+		assert.Equal(t, int64(0), run(t, func(cfg *config.Config) {}))
+	})
+
+	t.Run("jar", func(t *testing.T) {
+		assert.Equal(t, int64(3), run(t, func(cfg *config.Config) { cfg.Cookies = true }))
+	})
+
+	t.Run("initial cookie", func(t *testing.T) {
+		assert.Equal(t, int64(4), run(t, func(cfg *config.Config) { cfg.Cookie = []string{"session=abc"} }))
+	})
+}
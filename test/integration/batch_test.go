@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSize(t *testing.T) {
+	var mu sync.Mutex
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		for _, item := range batch {
+			ids = append(ids, item["id"])
+		}
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	csvFile := filepath.Join(t.TempDir(), "users.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("id\n1\n2\n3\n4\n5\n6\n"), 0644))
+
+	cfg := newLocalConfig(server.URL+"/users/bulk", 2)
+	cfg.Method = "POST"
+	cfg.Concurrency = 1
+	cfg.CSVFile = csvFile
+	cfg.Body = `{"id":"{{id}}"}`
+	cfg.BatchSize = 3
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(2), result.SuccessfulRequests)
+
+	// 两个请求各携带 3 行，覆盖全部 CSV 数据
+	sort.Strings(ids)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6"}, ids)
+
+	require.NotNil(t, result.Batch)
+	assert.Equal(t, int64(2), result.Batch.Requests)
+	assert.Equal(t, int64(6), result.Batch.Rows)
+	assert.Equal(t, int64(6), result.Batch.SucceededRows)
+	assert.Equal(t, 3.0, result.Batch.RowsPerRequest)
+	assert.Greater(t, result.Batch.RowsPerSecond, 0.0)
+}
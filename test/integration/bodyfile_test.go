@@ -0,0 +1,228 @@
+package integration
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVBodyFile(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		sizes = append(sizes, len(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	files := map[string]int{
+		"small.bin":  16,
+		"medium.bin": 64 * 1024,
+		"large.bin":  2 * 1024 * 1024,
+	}
+	for name, size := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), bytes.Repeat([]byte("x"), size), 0644))
+	}
+
+	// 相对路径以 CSV 所在目录为基准，也支持绝对路径
+	csv := strings.Join([]string{
+		"id,__body_file",
+		"1,small.bin",
+		"2,medium.bin",
+		"3," + filepath.Join(dir, "large.bin"),
+		"4,missing.bin",
+	}, "\n")
+	csvFile := filepath.Join(dir, "uploads.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte(csv), 0644))
+
+	cfg := newLocalConfig(server.URL+"/upload/{{id}}", 4)
+	cfg.Method = "POST"
+	cfg.Concurrency = 1
+	cfg.CSVFile = csvFile
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+
+	assert.Equal(t, int64(4), result.TotalRequests)
+	assert.Equal(t, int64(3), result.SuccessfulRequests)
+	assert.Equal(t, int64(1), result.FailedRequests)
+
+	errors, _ := result.GetSortedErrors()
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Error, "Failed to open body file")
+
+	sort.Ints(sizes)
+	assert.Equal(t, []int{16, 64 * 1024, 2 * 1024 * 1024}, sizes)
+}
+
+func TestBodyDir(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		seen[string(body)]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	// 子目录和隐藏文件不作为请求体
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "b.json", "c.json"} {
+		body := `{"file":"` + name + `","user":"{{id}}"}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden"), []byte("ignored"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0755))
+
+	csvFile := filepath.Join(t.TempDir(), "users.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("id\n1\n"), 0644))
+
+	cfg := newLocalConfig(server.URL, 6)
+	cfg.Method = "POST"
+	cfg.Concurrency = 1
+	cfg.CSVFile = csvFile
+	cfg.BodyDir = dir
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+
+	assert.Equal(t, int64(6), result.SuccessfulRequests)
+	assert.Equal(t, 3, result.DistinctBodies)
+	assert.Equal(t, map[string]int{
+		`{"file":"a.json","user":"1"}`: 2,
+		`{"file":"b.json","user":"1"}`: 2,
+		`{"file":"c.json","user":"1"}`: 2,
+	}, seen)
+}
+
+func TestBodyDirEmpty(t *testing.T) {
+	cfg := newLocalConfig("http://localhost", 1)
+	cfg.BodyDir = t.TempDir()
+
+	_, err := engine.NewStressEngine(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "contains no files")
+}
+
+func TestBinaryBodyFile(t *testing.T) {
+	// 包含无效 UTF-8 和模板语法的字节必须原样到达服务端
+	payload := []byte{0x0a, 0x03, 'a', 'b', 'c', 0xff, 0xfe, 0x00, '{', '{', 'i', 'd', '}', '}', 0x80}
+
+	var mu sync.Mutex
+	var bodies [][]byte
+	var contentTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	bodyFile := filepath.Join(t.TempDir(), "message.pb")
+	require.NoError(t, os.WriteFile(bodyFile, payload, 0644))
+
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		contentType string
+	}{
+		{"default content type", nil, "application/octet-stream"},
+		{"explicit content type", map[string]string{"Content-Type": "application/x-protobuf"}, "application/x-protobuf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodies, contentTypes = nil, nil
+
+			cfg := newLocalConfig(server.URL, 3)
+			cfg.Method = "POST"
+			cfg.BodyFile = bodyFile
+			cfg.Binary = true
+			cfg.Headers = tt.headers
+			require.NoError(t, cfg.Validate())
+
+			tester, err := engine.NewStressEngine(cfg)
+			require.NoError(t, err)
+			defer tester.Cleanup()
+
+			result := tester.Run()
+			assert.Equal(t, int64(3), result.SuccessfulRequests)
+
+			require.Len(t, bodies, 3)
+			for i := range bodies {
+				assert.Equal(t, payload, bodies[i])
+				assert.Equal(t, tt.contentType, contentTypes[i])
+			}
+		})
+	}
+}
+
+func TestTemplatedBodyFile(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	bodyFile := filepath.Join(dir, "order.json")
+	require.NoError(t, os.WriteFile(bodyFile, []byte(`{"sku":"{{sku}}"}`), 0644))
+	csvFile := filepath.Join(dir, "orders.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("sku\nA1\nB2\n"), 0644))
+
+	cfg := newLocalConfig(server.URL, 4)
+	cfg.Method = "POST"
+	cfg.Concurrency = 1
+	cfg.BodyFile = bodyFile
+	cfg.CSVFile = csvFile
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	// 文件只在启动时读取一次，之后修改不影响请求
+	require.NoError(t, os.WriteFile(bodyFile, []byte(`{"changed": true}`), 0644))
+
+	result := tester.Run()
+	assert.Equal(t, int64(4), result.SuccessfulRequests)
+	assert.ElementsMatch(t, []string{`{"sku":"A1"}`, `{"sku":"B2"}`, `{"sku":"A1"}`, `{"sku":"B2"}`}, bodies)
+}
+
+func TestBodyFile_Missing(t *testing.T) {
+	cfg := newLocalConfig("http://127.0.0.1:1", 1)
+	cfg.Method = "POST"
+	cfg.BodyFile = filepath.Join(t.TempDir(), "missing.json")
+
+	_, err := engine.NewStressEngine(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read body file")
+}
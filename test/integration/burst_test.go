@@ -0,0 +1,54 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBurst(t *testing.T) {
+	// 统计服务端同时处理的最大请求数
+	var inflight, peak int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&inflight, 1)
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+		atomic.AddInt64(&inflight, -1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 20)
+	cfg.Concurrency = 20
+	cfg.Burst = 20
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(20), result.SuccessfulRequests)
+	assert.Equal(t, int64(20), atomic.LoadInt64(&peak), "all burst requests should be in flight at once")
+
+	require.NotNil(t, result.Burst)
+	assert.Equal(t, 20, result.Burst.Size)
+	assert.GreaterOrEqual(t, result.Burst.Completion, result.Burst.FirstResponse)
+	assert.GreaterOrEqual(t, result.Burst.FirstResponse, 100*time.Millisecond)
+
+	var total int64
+	for _, bucket := range result.Burst.Histogram {
+		total += bucket.Count
+	}
+	assert.Equal(t, int64(20), total)
+}
@@ -0,0 +1,131 @@
+package integration
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/cluster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAgent 启动一个 agent 并返回其地址
+func newTestAgent(t *testing.T) string {
+	agent := cluster.NewAgent()
+	agent.SetOutput(io.Discard)
+	server := httptest.NewServer(agent.Handler())
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func newTestController(agents ...string) *cluster.Controller {
+	controller := cluster.NewController(agents)
+	controller.SetOutput(io.Discard)
+	controller.StartDelay = 100 * time.Millisecond
+	controller.PollInterval = 20 * time.Millisecond
+	return controller
+}
+
+func TestDistributedRun(t *testing.T) {
+	var count int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1)%10 == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 50)
+	cfg.OutputFile = "controller-only.json"
+
+	merged, result, err := newTestController(newTestAgent(t), newTestAgent(t)).Run(cfg)
+	require.NoError(t, err)
+
+	// 每个 agent 执行完整的配置
+	assert.Equal(t, int64(100), atomic.LoadInt64(&count))
+	assert.Equal(t, int64(100), result.TotalRequests)
+	assert.Equal(t, int64(90), result.SuccessfulRequests)
+	assert.Equal(t, int64(10), result.FailedRequests)
+	assert.Equal(t, int64(90), result.GetStatusCodeCount(http.StatusOK))
+	assert.Greater(t, result.P50ResponseTime, time.Duration(0))
+
+	assert.Equal(t, 4, merged.Concurrency)
+	assert.Equal(t, 100, merged.TotalRequests)
+	assert.Equal(t, "controller-only.json", merged.OutputFile)
+}
+
+func TestDistributedRun_PercentilesCoverAllRequests(t *testing.T) {
+	// 最早的 600 个请求较慢，占全部 24000 个请求的 2.5%，但不在各 agent 保留的最近 10000 条详细记录中
+	var count int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1) <= 600 {
+			time.Sleep(5 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, result, err := newTestController(newTestAgent(t), newTestAgent(t)).Run(newLocalConfig(server.URL, 12000))
+	require.NoError(t, err)
+	require.Equal(t, int64(24000), result.SuccessfulRequests)
+
+	// 分位数由各 agent 记录全部请求的直方图合并计算
+	assert.Equal(t, int64(24000), result.LatencyHistogram().TotalCount())
+	assert.GreaterOrEqual(t, result.P99ResponseTime, 5*time.Millisecond)
+}
+
+func TestDistributedRun_IgnoresControllerPaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 已被占用的地址：agent 若按 controller 的配置启动实时指标服务会失败
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	rawLog := filepath.Join(t.TempDir(), "raw.jsonl")
+	cfg := newLocalConfig(server.URL, 10)
+	cfg.RawLog = rawLog
+	cfg.MetricsAddr = listener.Addr().String()
+
+	_, result, err := newTestController(newTestAgent(t)).Run(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), result.SuccessfulRequests)
+
+	// agent 不创建 controller 指定的原始日志
+	assert.NoFileExists(t, rawLog)
+}
+
+func TestDistributedRun_RejectedConfig(t *testing.T) {
+	cfg := newLocalConfig("http://127.0.0.1:1", 10)
+	cfg.Concurrency = 0
+
+	agent := newTestAgent(t)
+	_, _, err := newTestController(agent).Run(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "concurrency must be positive")
+
+	// 配置被拒绝时 agent 没有可启动的压测
+	resp, err := http.Get(agent + "/result")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestDistributedRun_UnreachableAgent(t *testing.T) {
+	cfg := newLocalConfig("http://127.0.0.1:1", 10)
+
+	_, _, err := newTestController("127.0.0.1:1").Run(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "agent 127.0.0.1:1")
+}
@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCompression(t *testing.T) {
+	body := strings.Repeat(`{"name":"value"}`, 256)
+
+	// 偶数请求返回 gzip 压缩的响应，奇数请求返回未压缩的响应
+	var count int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1)%2 == 0 && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			defer gz.Close()
+			_, _ = gz.Write([]byte(body))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 10)
+	cfg.Concurrency = 1
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(10), result.SuccessfulRequests)
+
+	stats := result.Compression
+	require.NotNil(t, stats)
+	assert.Equal(t, int64(5), stats.CompressedResponses)
+	assert.Equal(t, int64(5), stats.UncompressedResponses)
+	assert.Equal(t, int64(5*len(body)), stats.DecodedBytes)
+	assert.Less(t, stats.WireBytes, stats.DecodedBytes)
+	assert.Greater(t, stats.Ratio, 1.0)
+
+	for _, request := range result.DetailedResults {
+		assert.Equal(t, len(body), request.ResponseSize)
+	}
+}
+
+func TestResponseCompression_ExplicitAcceptEncoding(t *testing.T) {
+	// 调用方自行指定 Accept-Encoding 时同样解压并统计
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip, deflate", r.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		_, _ = gz.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 3)
+	cfg.Headers = map[string]string{"Accept-Encoding": "gzip, deflate"}
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(3), result.SuccessfulRequests)
+	require.NotNil(t, result.Compression)
+	assert.Equal(t, int64(3), result.Compression.CompressedResponses)
+	assert.Equal(t, int64(15), result.Compression.DecodedBytes)
+}
+
+func TestResponseCompression_Uncompressed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("plain"))
+	}))
+	defer server.Close()
+
+	tester, err := engine.NewStressEngine(newLocalConfig(server.URL, 3))
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Nil(t, result.Compression)
+}
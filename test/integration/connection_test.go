@@ -0,0 +1,239 @@
+package integration
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newConnCountingServer 创建统计新建连接数的测试服务器
+func newConnCountingServer(conns *int64) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(conns, 1)
+		}
+	}
+	server.Start()
+	return server
+}
+
+func TestMaxRequestsPerConnection(t *testing.T) {
+	var conns int64
+	server := newConnCountingServer(&conns)
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 20)
+	cfg.Concurrency = 1
+	cfg.MaxRequestsPerConnection = 5
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(20), result.SuccessfulRequests)
+
+	assert.Equal(t, int64(4), atomic.LoadInt64(&conns))
+	assert.Equal(t, int64(4), result.NewConnections)
+	assert.InDelta(t, 5.0, result.GetRequestsPerConnection(), 0.01)
+}
+
+func TestMaxConnsPerHost(t *testing.T) {
+	var conns int64
+	server := newConnCountingServer(&conns)
+	defer server.Close()
+
+	// 4 个工作协程共用 1 个连接，其余请求排队等待
+	cfg := newLocalConfig(server.URL, 20)
+	cfg.Concurrency = 4
+	cfg.MaxConnsPerHost = 1
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(20), result.SuccessfulRequests)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&conns))
+}
+
+func TestIdleTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		idleTimeout time.Duration
+		newConns    int64
+		reused      int64
+	}{
+		// 请求间隔超过空闲超时，每个请求都重新建立连接
+		{"short", 20 * time.Millisecond, 3, 0},
+		{"long", time.Minute, 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conns int64
+			server := newConnCountingServer(&conns)
+			defer server.Close()
+
+			cfg := newLocalConfig(server.URL, 3)
+			cfg.Concurrency = 1
+			cfg.IdleTimeout = tt.idleTimeout
+
+			tester, err := engine.NewStressEngine(cfg)
+			require.NoError(t, err)
+			defer tester.Cleanup()
+
+			var sent int64
+			tester.OnBeforeRequest(func(_ *resty.Client, _ *resty.Request) error {
+				if atomic.AddInt64(&sent, 1) > 1 {
+					time.Sleep(100 * time.Millisecond)
+				}
+				return nil
+			})
+
+			result := tester.Run()
+			require.Equal(t, int64(3), result.SuccessfulRequests)
+
+			assert.Equal(t, tt.newConns, atomic.LoadInt64(&conns))
+			assert.Equal(t, tt.newConns, result.NewConnections)
+			assert.Equal(t, tt.reused, result.ReusedConns)
+		})
+	}
+}
+
+// newUnstableServer /drop 读取请求后直接关闭连接，/reset 以 RST 重置连接，
+// /close 正常响应但要求关闭保持连接
+func newUnstableServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/drop", "/reset":
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			if tcp, ok := conn.(*net.TCPConn); ok && r.URL.Path == "/reset" {
+				tcp.SetLinger(0)
+			}
+			conn.Close()
+		case "/close":
+			w.Header().Set("Connection", "close")
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+}
+
+func TestKeepAliveProbe(t *testing.T) {
+	server := newUnstableServer()
+	defer server.Close()
+
+	tests := []struct {
+		path           string
+		wantReconnects int64
+		wantResets     int64
+		wantDropped    int64
+		wantCategory   string
+	}{
+		{path: "/ok"},
+		{path: "/close", wantReconnects: 4},
+		{path: "/drop", wantReconnects: 4, wantDropped: 5, wantCategory: types.ErrorCategoryConnectionDropped},
+		{path: "/reset", wantReconnects: 4, wantResets: 5, wantCategory: types.ErrorCategoryConnectionReset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			// POST 请求不会被 Transport 自动重试，连接错误能直接暴露出来
+			cfg := newLocalConfig(server.URL+tt.path, 5)
+			cfg.Method = "POST"
+			cfg.Body = "payload"
+			cfg.Concurrency = 1
+			cfg.KeepAliveProbe = true
+
+			tester, err := engine.NewStressEngine(cfg)
+			require.NoError(t, err)
+			defer tester.Cleanup()
+
+			result := tester.Run()
+			health := result.ConnectionHealth
+			require.NotNil(t, health)
+
+			assert.Equal(t, tt.wantReconnects, health.Reconnects)
+			assert.Equal(t, tt.wantResets, health.Resets)
+			assert.Equal(t, tt.wantDropped, health.Dropped)
+			assert.Equal(t, tt.wantResets > 0 || tt.wantDropped > 0, health.Unhealthy())
+			if tt.wantCategory != "" {
+				assert.Equal(t, int64(5), result.GetErrorCategories()[tt.wantCategory])
+			}
+		})
+	}
+}
+
+func TestPoolStats(t *testing.T) {
+	var conns int64
+	server := newConnCountingServer(&conns)
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 40)
+	cfg.Concurrency = 4
+	cfg.PoolStats = true
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(40), result.SuccessfulRequests)
+
+	// 每个工作协程各有一个连接，新建连接的拨号时间不计为等待
+	pool := result.ConnectionPool
+	require.NotNil(t, pool)
+	assert.Equal(t, int64(40), pool.Requests)
+	assert.Equal(t, int64(0), pool.Waited)
+	assert.False(t, pool.Saturated())
+}
+
+func TestLatencyBreakdown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 10)
+	cfg.LatencyBreakdown = true
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(10), result.SuccessfulRequests)
+
+	breakdown := result.LatencyBreakdown
+	require.NotNil(t, breakdown)
+	assert.Equal(t, int64(10), breakdown.Requests)
+
+	// 两个工作协程各建立一次连接，地址为 IP 无需解析，HTTP 没有 TLS 握手
+	assert.Equal(t, int64(2), breakdown.Connect.Count)
+	assert.Positive(t, breakdown.Connect.Avg)
+	assert.Zero(t, breakdown.DNS.Count)
+	assert.Zero(t, breakdown.TLS.Count)
+
+	// 服务端处理时间体现在首字节耗时中
+	assert.Equal(t, int64(10), breakdown.TTFB.Count)
+	assert.GreaterOrEqual(t, breakdown.TTFB.P50, 20*time.Millisecond)
+	assert.LessOrEqual(t, breakdown.TTFB.P50, result.P50ResponseTime)
+}
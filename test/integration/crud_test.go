@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newResourceServer 内存中的 /items 资源：POST 创建，GET/PUT/DELETE 操作 /items/{id}
+func newResourceServer() (*httptest.Server, func() int) {
+	var mu sync.Mutex
+	items := make(map[string]bool)
+	nextID := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path == "/items" && r.Method == http.MethodPost {
+			nextID++
+			id := fmt.Sprintf("item-%d", nextID)
+			items[id] = true
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"data": {"id": %q}}`, id)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/items/")
+		if !items[id] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodPut:
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			delete(items, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+
+	remaining := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(items)
+	}
+	return server, remaining
+}
+
+func TestCRUDMode(t *testing.T) {
+	server, remaining := newResourceServer()
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL+"/items/", 6)
+	cfg.CRUD = true
+	cfg.CRUDMethods = "POST,GET,PUT,DELETE"
+	cfg.CRUDIDPath = "data.id"
+	cfg.Body = `{"name": "test"}`
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+
+	assert.Equal(t, int64(24), result.TotalRequests)
+	assert.Equal(t, int64(24), result.SuccessfulRequests)
+	assert.Equal(t, 0, remaining(), "every created resource should have been deleted")
+
+	methods := result.GetMethodStats()
+	require.Len(t, methods, 4)
+	for i, name := range []string{"POST", "GET", "PUT", "DELETE"} {
+		assert.Equal(t, name, methods[i].Method)
+		assert.Equal(t, int64(6), methods[i].TotalRequests)
+		assert.Equal(t, int64(6), methods[i].SuccessfulRequests)
+		assert.Greater(t, methods[i].AvgResponseTime.Nanoseconds(), int64(0))
+	}
+}
+
+func TestCRUDMode_MissingID(t *testing.T) {
+	server, _ := newResourceServer()
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL+"/items", 3)
+	cfg.CRUD = true
+	cfg.CRUDMethods = "POST,GET,DELETE"
+	cfg.CRUDIDPath = "id"
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+
+	// 创建响应中找不到 ID 时放弃后续步骤
+	assert.Equal(t, int64(3), result.TotalRequests)
+	assert.Equal(t, int64(3), result.FailedRequests)
+
+	errors, _ := result.GetSortedErrors()
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Error, `resource id not found at "id"`)
+
+	methods := result.GetMethodStats()
+	require.Len(t, methods, 3)
+	assert.Equal(t, int64(3), methods[0].FailedRequests)
+	assert.Equal(t, int64(0), methods[1].TotalRequests)
+}
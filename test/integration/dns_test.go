@@ -0,0 +1,156 @@
+package integration
+
+import (
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startDNSServer 启动只回答 A 记录的 UDP DNS 服务器：以 missing. 开头的名称返回 NXDOMAIN，
+// 其他名称都解析为 127.0.0.1。返回服务器地址和收到的查询数
+func startDNSServer(t *testing.T) (string, *int64) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	var queries int64
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			atomic.AddInt64(&queries, 1)
+			if reply := dnsReply(buf[:n]); reply != nil {
+				conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().String(), &queries
+}
+
+// dnsReply 按查询构造应答，A 记录的 TTL 为 60 秒，其他类型返回空应答
+func dnsReply(query []byte) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+
+	// 读取问题部分的名称，名称之后是 2 字节类型和 2 字节类别
+	var labels []string
+	offset := 12
+	for offset < len(query) && query[offset] != 0 {
+		length := int(query[offset])
+		if offset+1+length > len(query) {
+			return nil
+		}
+		labels = append(labels, string(query[offset+1:offset+1+length]))
+		offset += 1 + length
+	}
+	end := offset + 5
+	if end > len(query) {
+		return nil
+	}
+	qtype := binary.BigEndian.Uint16(query[offset+1:])
+
+	reply := make([]byte, 12, 64)
+	copy(reply, query[:2])
+	flags := uint16(0x8180) // 应答、期望递归、支持递归
+	var answers uint16
+	if len(labels) > 0 && labels[0] == "missing" {
+		flags |= 3 // NXDOMAIN
+	} else if qtype == 1 {
+		answers = 1
+	}
+	binary.BigEndian.PutUint16(reply[2:], flags)
+	binary.BigEndian.PutUint16(reply[4:], 1)
+	binary.BigEndian.PutUint16(reply[6:], answers)
+	reply = append(reply, query[12:end]...)
+	if answers > 0 {
+		// 指向问题中的名称，类型 A，类别 IN，TTL 60，4 字节地址
+		reply = append(reply, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 127, 0, 0, 1)
+	}
+	return reply
+}
+
+func TestDNSServers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	dnsAddr, queries := startDNSServer(t)
+
+	t.Run("resolves through the given server", func(t *testing.T) {
+		cfg := newLocalConfig("http://api.stress.test:"+port+"/", 20)
+		cfg.DNSServers = dnsAddr
+
+		tester, err := engine.NewStressEngine(cfg)
+		require.NoError(t, err)
+		defer tester.Cleanup()
+
+		result := tester.Run()
+		assert.Equal(t, int64(20), result.SuccessfulRequests)
+		assert.NotZero(t, atomic.LoadInt64(queries))
+
+		// 只有新建连接时解析，两个工作协程的连接被复用
+		dns := result.DNS
+		require.NotNil(t, dns)
+		assert.Equal(t, int64(2), dns.Lookups)
+		assert.Positive(t, dns.AvgLookup)
+		assert.Zero(t, dns.Failures)
+	})
+
+	t.Run("resolver failures are categorized", func(t *testing.T) {
+		cfg := newLocalConfig("http://missing.stress.test:"+port+"/", 4)
+		cfg.DNSServers = dnsAddr
+
+		tester, err := engine.NewStressEngine(cfg)
+		require.NoError(t, err)
+		defer tester.Cleanup()
+
+		result := tester.Run()
+		assert.Equal(t, int64(4), result.FailedRequests)
+		assert.Equal(t, int64(4), result.GetErrorCategories()[types.ErrorCategoryDNSError])
+		require.NotNil(t, result.DNS)
+		assert.Equal(t, int64(4), result.DNS.Failures)
+	})
+}
+
+func TestResolveOverride(t *testing.T) {
+	var wrongHost int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(strings.ToLower(r.Host), "api.example.invalid:") {
+			atomic.AddInt64(&wrongHost, 1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	// .invalid 不会被解析，请求只能通过 -resolve 连接到本地服务器，Host 头保持原主机名
+	cfg := newLocalConfig("http://API.example.invalid:"+port+"/users", 5)
+	cfg.Resolve = []string{"api.example.invalid:" + port + ":127.0.0.1"}
+	require.NoError(t, cfg.Validate())
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(5), result.SuccessfulRequests)
+	assert.Zero(t, atomic.LoadInt64(&wrongHost))
+}
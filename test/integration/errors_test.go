@@ -0,0 +1,55 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorExamples(t *testing.T) {
+	// 用户 2 返回 500，用户 3 返回 404
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/2"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case strings.HasSuffix(r.URL.Path, "/3"):
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	csvFile := filepath.Join(t.TempDir(), "users.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("id\n1\n2\n3\n"), 0644))
+
+	cfg := newLocalConfig(server.URL+"/users/{{id}}", 9)
+	cfg.Concurrency = 1
+	cfg.CSVFile = csvFile
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(6), result.FailedRequests)
+
+	// 每种错误只保留一个示例
+	require.Len(t, result.ErrorExamples, 2)
+
+	serverError := result.ErrorExamples["HTTP 500: 500 Internal Server Error"]
+	require.NotNil(t, serverError)
+	assert.Equal(t, server.URL+"/users/2", serverError.URL)
+	assert.Equal(t, http.StatusInternalServerError, serverError.StatusCode)
+	assert.Equal(t, map[string]string{"id": "2"}, serverError.CSVData)
+
+	notFound := result.ErrorExamples["HTTP 404: 404 Not Found"]
+	require.NotNil(t, notFound)
+	assert.Equal(t, server.URL+"/users/3", notFound.URL)
+	assert.Equal(t, map[string]string{"id": "3"}, notFound.CSVData)
+}
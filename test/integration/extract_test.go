@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get("X-Token"))
+		n := len(seen)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		// 偶数次响应不含令牌，工作协程保留之前提取的值
+		if n%2 == 0 {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"token": "tok-%d"}}`, n)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 4)
+	cfg.Concurrency = 1
+	cfg.Headers = map[string]string{"X-Token": "{{token}}"}
+	cfg.Extract = []string{"token=$.data.token"}
+	require.NoError(t, cfg.Validate())
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(4), result.SuccessfulRequests)
+	// 第一次提取之前占位符保持原样
+	assert.Equal(t, []string{"{{token}}", "tok-1", "tok-1", "tok-3"}, seen)
+	// 提取的变量不写入请求结果
+	for _, detail := range result.DetailedResults {
+		assert.NotContains(t, detail.CSVData, "token")
+	}
+}
@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailFast(t *testing.T) {
+	// 第 5 个请求失败
+	var count int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1) == 5 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Concurrency = 1
+	cfg.Duration = 10 * time.Second
+	cfg.FailFast = true
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	start := time.Now()
+	result := tester.Run()
+	assert.Less(t, time.Since(start), 5*time.Second, "test should stop on the first failure")
+
+	failed := result.GetFailFast()
+	require.NotNil(t, failed)
+	assert.Equal(t, http.StatusInternalServerError, failed.StatusCode)
+	assert.Equal(t, server.URL, failed.URL)
+	assert.Equal(t, int64(4), result.SuccessfulRequests)
+	assert.Equal(t, types.ExitStatus{Reason: types.ExitReasonFailFast, Code: 1}, result.ExitStatus(types.SLOReport{Passed: true}))
+}
+
+func TestFailFast_NoFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 10)
+	cfg.FailFast = true
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(10), result.SuccessfulRequests)
+	assert.Nil(t, result.GetFailFast())
+}
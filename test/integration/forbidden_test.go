@@ -0,0 +1,42 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForbidBodyContains(t *testing.T) {
+	// 每隔一个请求返回带堆栈信息的 200 响应
+	var count int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1)%2 == 0 {
+			w.Write([]byte(`{"error":"Traceback (most recent call last): ..."}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 10)
+	cfg.ForbidBodyContains = []string{"stack trace", "Traceback"}
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(5), result.SuccessfulRequests)
+	assert.Equal(t, int64(5), result.FailedRequests)
+	assert.Equal(t, int64(5), result.GetErrorCategories()[types.ErrorCategoryForbiddenContent])
+
+	errorList, _ := result.GetSortedErrors()
+	require.Len(t, errorList, 1)
+	assert.Equal(t, `forbidden content in response body: "Traceback"`, errorList[0].Error)
+}
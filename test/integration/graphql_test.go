@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQL(t *testing.T) {
+	const query = "query User($id: ID!) { user(id: $id) { name } }"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" ||
+			json.NewDecoder(r.Body).Decode(&req) != nil || req.Query != query {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// GraphQL 出错时仍返回 200
+		w.Header().Set("Content-Type", "application/json")
+		if req.Variables["id"] == "3" {
+			w.Write([]byte(`{"data":{"user":null},"errors":[{"message":"user 3 not found"}]}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"user":{"name":"alice"}}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	queryFile := filepath.Join(dir, "user.gql")
+	require.NoError(t, os.WriteFile(queryFile, []byte(query), 0644))
+	csvFile := filepath.Join(dir, "users.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("user_id\n1\n2\n3\n4\n"), 0644))
+
+	cfg := newLocalConfig(server.URL+"/graphql", 8)
+	cfg.Method = "POST"
+	cfg.Concurrency = 1
+	cfg.CSVFile = csvFile
+	cfg.GraphQL = queryFile
+	cfg.GraphQLVars = `{"id": "{{user_id}}"}`
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(8), result.TotalRequests)
+	assert.Equal(t, int64(6), result.SuccessfulRequests)
+	assert.Equal(t, int64(2), result.FailedRequests)
+	assert.Equal(t, int64(2), result.GetErrorCategories()[types.ErrorCategoryGraphQLError])
+	assert.Equal(t, int64(2), result.GetErrorCounts()["GraphQL error: user 3 not found"])
+}
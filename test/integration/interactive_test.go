@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInteractive(t *testing.T) {
+	var inflight, peak, served int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&inflight, 1)
+		defer atomic.AddInt64(&inflight, -1)
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+		atomic.AddInt64(&served, 1)
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Duration = 10 * time.Second
+	cfg.Concurrency = 1
+	cfg.Interactive = true
+
+	input, commands := io.Pipe()
+	defer commands.Close()
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+	tester.SetInput(input)
+
+	done := make(chan *types.StressResult)
+	go func() { done <- tester.Run() }()
+
+	send := func(cmd string) {
+		_, err := io.WriteString(commands, cmd+"\n")
+		require.NoError(t, err)
+	}
+
+	// 扩容
+	send("c 4")
+	assert.Eventually(t, func() bool { return atomic.LoadInt64(&peak) == 4 }, 2*time.Second, 10*time.Millisecond)
+
+	// 无效命令不影响运行，缩容后同时处理的请求数回落
+	send("x")
+	send("- 3")
+	time.Sleep(100 * time.Millisecond)
+	atomic.StoreInt64(&peak, 0)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&peak))
+
+	// 限速
+	send("r 10")
+	time.Sleep(100 * time.Millisecond)
+	before := atomic.LoadInt64(&served)
+	time.Sleep(time.Second)
+	assert.LessOrEqual(t, atomic.LoadInt64(&served)-before, int64(13))
+
+	send("q")
+	select {
+	case result := <-done:
+		assert.Greater(t, result.TotalRequests, int64(0))
+		assert.Less(t, result.TotalDuration, 5*time.Second)
+	case <-time.After(5 * time.Second):
+		t.Fatal("interactive run did not stop on q")
+	}
+}
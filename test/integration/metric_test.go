@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractMetric(t *testing.T) {
+	var counter int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&counter, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if n > 10 {
+			// 缺少指标字段的响应
+			fmt.Fprint(w, `{"status": "ok"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"stats": {"queue_depth": %d}}`, n)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 12)
+	cfg.Concurrency = 1
+	cfg.ExtractMetric = "stats.queue_depth"
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.NotNil(t, result.CustomMetric)
+
+	metric := result.CustomMetric
+	assert.Equal(t, int64(10), metric.Count)
+	assert.Equal(t, int64(2), metric.Missing)
+	assert.Equal(t, 1.0, metric.Min)
+	assert.Equal(t, 10.0, metric.Max)
+	assert.InDelta(t, 5.5, metric.Avg, 0.001)
+	assert.InDelta(t, 5.5, metric.P50, 0.001)
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Duration = 2 * time.Second
+	cfg.MetricsAddr = "127.0.0.1:0"
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+	addr := tester.MetricsAddr()
+	require.NotEmpty(t, addr)
+
+	done := make(chan *types.StressResult)
+	go func() { done <- tester.Run() }()
+
+	// 压测进行中读取指标
+	var live types.LiveMetrics
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/metrics.json")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return json.NewDecoder(resp.Body).Decode(&live) == nil && live.SuccessfulRequests > 0
+	}, time.Second, 20*time.Millisecond)
+	assert.Greater(t, live.P50ResponseTime, 5*time.Millisecond)
+	assert.Equal(t, live.SuccessfulRequests, live.StatusCodes[http.StatusOK])
+
+	resp, err := http.Get("http://" + addr + "/metrics")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "# TYPE rst_requests_total counter")
+	assert.Contains(t, string(body), `rst_responses_total{code="200"}`)
+	assert.Contains(t, string(body), `rst_response_time_seconds{quantile="0.99"}`)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not finish")
+	}
+
+	// 清理后释放端口
+	tester.Cleanup()
+	_, err = http.Get("http://" + addr + "/metrics")
+	assert.Error(t, err)
+}
+
+func TestMetricsEndpoint_AddressInUse(t *testing.T) {
+	cfg := newLocalConfig("http://127.0.0.1:1", 1)
+	cfg.MetricsAddr = "127.0.0.1:0"
+
+	first, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer first.Cleanup()
+
+	cfg.MetricsAddr = first.MetricsAddr()
+	_, err = engine.NewStressEngine(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metrics-addr")
+}
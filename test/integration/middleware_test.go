@@ -0,0 +1,169 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Echo", r.Header.Get("X-Custom"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL+"/items", 10)
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	var responses int64
+	tester.
+		OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+			// 中间件看到的是模板展开后的最终 URL
+			req.SetHeader("X-Custom", req.URL)
+			return nil
+		}).
+		OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+			atomic.AddInt64(&responses, 1)
+			if resp.Header().Get("X-Echo") != server.URL+"/items" {
+				return errors.New("header not echoed")
+			}
+			return nil
+		})
+
+	result := tester.Run()
+	assert.Equal(t, int64(10), result.SuccessfulRequests)
+	assert.Equal(t, int64(10), atomic.LoadInt64(&responses))
+}
+
+func TestEngineMiddleware_AfterResponseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 4)
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	tester.OnAfterResponse(func(_ *resty.Client, _ *resty.Response) error {
+		return errors.New("rejected by middleware")
+	})
+
+	result := tester.Run()
+	assert.Equal(t, int64(4), result.FailedRequests)
+
+	errorList, _ := result.GetSortedErrors()
+	require.Len(t, errorList, 1)
+	assert.Equal(t, "rejected by middleware", errorList[0].Error)
+}
+
+type tenantKey struct{}
+
+func TestEngineRequestContext(t *testing.T) {
+	var mu sync.Mutex
+	tenants := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		tenants[r.Header.Get("X-Tenant")]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	csvFile := filepath.Join(t.TempDir(), "tenants.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("tenant\nacme\nglobex\n"), 0644))
+
+	cfg := newLocalConfig(server.URL, 10)
+	cfg.Concurrency = 1
+	cfg.CSVFile = csvFile
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	tester.
+		SetRequestContext(func(ctx context.Context, info engine.RequestInfo) context.Context {
+			return context.WithValue(ctx, tenantKey{}, info.CSVData["tenant"])
+		}).
+		OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+			tenant, _ := req.Context().Value(tenantKey{}).(string)
+			req.SetHeader("X-Tenant", tenant)
+			return nil
+		})
+
+	result := tester.Run()
+	assert.Equal(t, int64(10), result.SuccessfulRequests)
+	assert.Equal(t, map[string]int{"acme": 5, "globex": 5}, tenants)
+}
+
+// countingTransport 统计经过的请求数
+type countingTransport struct {
+	requests int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(&t.requests, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestEngineWithClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 8)
+	cfg.BearerToken = "secret"
+
+	transport := &countingTransport{}
+	tester, err := engine.NewStressEngine(cfg, engine.WithClient(resty.New().SetTransport(transport)))
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	// 请求经过注入客户端的传输层，配置中的认证仍然生效
+	result := tester.Run()
+	assert.Equal(t, int64(8), result.SuccessfulRequests)
+	assert.Equal(t, int64(8), atomic.LoadInt64(&transport.requests))
+}
+
+func TestEngineRunContext(t *testing.T) {
+	server := newOKServer()
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Duration = time.Minute
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result := tester.RunContext(ctx)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.Greater(t, result.SuccessfulRequests, int64(0))
+}
@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSlowServer 每个请求等待 delay 后返回 200
+func newSlowServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestOpenModel(t *testing.T) {
+	server := newSlowServer(100 * time.Millisecond)
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 50)
+	cfg.Concurrency = 100
+	cfg.RateLimit = 100
+	cfg.OpenModel = true
+	require.NoError(t, cfg.Validate())
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	// 封闭模型下慢响应会拖慢发送，开放模型仍按 100 req/sec 发出
+	start := time.Now()
+	result := tester.Run()
+	elapsed := time.Since(start)
+
+	assert.Equal(t, int64(50), result.SuccessfulRequests)
+	assert.Zero(t, result.DroppedArrivals)
+	assert.InDelta(t, 0.6, elapsed.Seconds(), 0.4, "50 arrivals at 100 req/sec plus one response time")
+
+	require.NotNil(t, result.Corrected)
+	assert.True(t, result.Corrected.Measured)
+	assert.GreaterOrEqual(t, result.Corrected.P50ResponseTime, 100*time.Millisecond)
+	assert.GreaterOrEqual(t, result.Corrected.P99ResponseTime, result.P99ResponseTime)
+}
+
+func TestOpenModel_DropsArrivalsAtCapacity(t *testing.T) {
+	server := newSlowServer(200 * time.Millisecond)
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 20)
+	cfg.Concurrency = 2
+	cfg.RateLimit = 100
+	cfg.OpenModel = true
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	// 两个请求在途时其余到达被丢弃，不排队等待
+	result := tester.Run()
+	assert.Greater(t, result.DroppedArrivals, int64(0))
+	assert.Equal(t, int64(20), result.TotalRequests+result.DroppedArrivals)
+}
@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxy(t *testing.T) {
+	// 转发代理收到的请求行为绝对 URL，这里直接应答而不转发
+	var proxied int64
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "api.stress.test" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		atomic.AddInt64(&proxied, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	// 目标主机名无法解析，只有经由代理才能成功
+	cfg := newLocalConfig("http://api.stress.test/users", 10)
+	cfg.Proxy = proxy.URL
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(10), result.SuccessfulRequests)
+	assert.Equal(t, int64(10), atomic.LoadInt64(&proxied))
+}
+
+func TestProxy_Refused(t *testing.T) {
+	// 取得一个空闲端口后关闭，代理连接被拒绝
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	cfg := newLocalConfig("http://api.stress.test/users", 4)
+	cfg.Proxy = "http://" + addr
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(4), result.FailedRequests)
+	errors := result.GetErrorCounts()
+	require.Len(t, errors, 1)
+	for msg, count := range errors {
+		assert.Equal(t, int64(4), count)
+		assert.True(t, strings.Contains(msg, "proxyconnect"), msg)
+	}
+}
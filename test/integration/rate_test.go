@@ -0,0 +1,277 @@
+package integration
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOKServer 创建始终返回 200 的测试服务器
+func newOKServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestRateSchedule(t *testing.T) {
+	server := newOKServer()
+	defer server.Close()
+
+	schedule := filepath.Join(t.TempDir(), "schedule.txt")
+	require.NoError(t, os.WriteFile(schedule, []byte("500ms 20\n500ms 100\n"), 0644))
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Concurrency = 4
+	cfg.RateSchedule = schedule
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+
+	assert.Equal(t, time.Second, cfg.Duration, "duration should default to the schedule length")
+	require.Len(t, result.RateSegments, 2)
+	assert.Equal(t, 20.0, result.RateSegments[0].TargetRate)
+	assert.Equal(t, 100.0, result.RateSegments[1].TargetRate)
+	assert.InDelta(t, 20, result.RateSegments[0].AchievedRate, 10)
+	assert.InDelta(t, 100, result.RateSegments[1].AchievedRate, 30)
+	assert.InDelta(t, 60, result.TotalRequests, 20)
+}
+
+func TestRateLimit(t *testing.T) {
+	server := newOKServer()
+	defer server.Close()
+
+	t.Run("duration based", func(t *testing.T) {
+		cfg := newLocalConfig(server.URL, 0)
+		cfg.Concurrency = 4
+		cfg.Duration = 500 * time.Millisecond
+		cfg.RateLimit = 50
+
+		tester, err := engine.NewStressEngine(cfg)
+		require.NoError(t, err)
+		defer tester.Cleanup()
+
+		result := tester.Run()
+		assert.InDelta(t, 25, result.TotalRequests, 5)
+		require.NotNil(t, result.RateLimit)
+		assert.Equal(t, 50.0, result.RateLimit.TargetRate)
+		assert.InDelta(t, 50, result.RateLimit.AchievedRate, 10)
+	})
+
+	t.Run("count based", func(t *testing.T) {
+		cfg := newLocalConfig(server.URL, 20)
+		cfg.Concurrency = 4
+		cfg.RateLimit = 100
+
+		tester, err := engine.NewStressEngine(cfg)
+		require.NoError(t, err)
+		defer tester.Cleanup()
+
+		start := time.Now()
+		result := tester.Run()
+		assert.Equal(t, int64(20), result.SuccessfulRequests)
+		// 首个请求立即发送，其余 19 个按 10ms 间隔发送
+		assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+		require.NotNil(t, result.RateLimit)
+		assert.InDelta(t, 100, result.RateLimit.AchievedRate, 20)
+	})
+}
+
+func TestRampUp(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, earlyMax, peak int
+	var start time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		if time.Since(start) < 80*time.Millisecond {
+			earlyMax = max(earlyMax, inFlight)
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Concurrency = 4
+	cfg.Duration = 600 * time.Millisecond
+	cfg.RampUp = 400 * time.Millisecond
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	start = time.Now()
+	result := tester.Run()
+	assert.Zero(t, result.FailedRequests)
+
+	// 第 i 个工作协程在 100ms*i 之后开始，前 100ms 只有一个协程在发送
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, earlyMax)
+	assert.Equal(t, 4, peak)
+}
+
+func TestThinkTime(t *testing.T) {
+	server := newOKServer()
+	defer server.Close()
+
+	tests := []struct {
+		thinkTime  string
+		minElapsed time.Duration
+	}{
+		{"50ms", 200 * time.Millisecond},
+		{"20ms-40ms", 80 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.thinkTime, func(t *testing.T) {
+			cfg := newLocalConfig(server.URL, 5)
+			cfg.Concurrency = 1
+			cfg.ThinkTime = tt.thinkTime
+
+			tester, err := engine.NewStressEngine(cfg)
+			require.NoError(t, err)
+			defer tester.Cleanup()
+
+			start := time.Now()
+			result := tester.Run()
+			assert.Equal(t, int64(5), result.SuccessfulRequests)
+
+			// 5 个请求之间有 4 次等待，等待不计入响应时间
+			assert.GreaterOrEqual(t, time.Since(start), tt.minElapsed)
+			assert.Less(t, result.GetMaxResponseTime(), 20*time.Millisecond)
+		})
+	}
+}
+
+func TestSpikePreset(t *testing.T) {
+	server := newOKServer()
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Concurrency = 4
+	cfg.Spike = true
+	cfg.SpikeBaselineRate = 20
+	cfg.SpikeRate = 200
+	cfg.SpikeBaselineDuration = 300 * time.Millisecond
+	cfg.SpikeDuration = 200 * time.Millisecond
+	cfg.SpikeRecoveryDuration = 300 * time.Millisecond
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+
+	require.Len(t, result.RateSegments, 3)
+	assert.Equal(t, "baseline", result.RateSegments[0].Label)
+	assert.Equal(t, "spike", result.RateSegments[1].Label)
+	assert.Equal(t, "recovery", result.RateSegments[2].Label)
+	assert.Greater(t, result.RateSegments[1].AchievedRate, result.RateSegments[0].AchievedRate*3)
+	for _, segment := range result.RateSegments {
+		assert.Greater(t, segment.AvgResponseTime, time.Duration(0))
+		assert.GreaterOrEqual(t, segment.P99ResponseTime, segment.P50ResponseTime)
+	}
+}
+
+func TestPoissonArrivals(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 200)
+	cfg.Concurrency = 8
+	cfg.Arrival = "poisson:400"
+	cfg.Seed = 42
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	start := time.Now()
+	result := tester.Run()
+	elapsed := time.Since(start)
+
+	assert.Equal(t, int64(200), result.SuccessfulRequests)
+	assert.InDelta(t, 0.5, elapsed.Seconds(), 0.3, "200 requests at a mean of 400 req/sec")
+	require.NotNil(t, result.Corrected)
+	assert.Equal(t, 20*time.Millisecond, result.Corrected.ExpectedInterval, "8 workers at 400 req/sec")
+
+	// 指数分布的间隔变异系数约为 1，均匀发送时接近 0
+	require.Len(t, arrivals, 200)
+	var sum, sumSquares float64
+	for i := 1; i < len(arrivals); i++ {
+		gap := arrivals[i].Sub(arrivals[i-1]).Seconds()
+		sum += gap
+		sumSquares += gap * gap
+	}
+	n := float64(len(arrivals) - 1)
+	mean := sum / n
+	cv := math.Sqrt(sumSquares/n-mean*mean) / mean
+	assert.Greater(t, cv, 0.6, "inter-arrival gaps should be exponentially distributed")
+}
+
+func TestStartJitter(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		// 响应足够慢，每个工作协程只发出一个请求
+		time.Sleep(400 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 8)
+	cfg.Concurrency = 8
+	cfg.StartJitter = 300 * time.Millisecond
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+	assert.NotZero(t, cfg.Seed, "a seed is picked so the jitter can be reproduced")
+
+	start := time.Now()
+	result := tester.Run()
+	assert.Equal(t, int64(8), result.SuccessfulRequests)
+
+	// 不加延迟时 8 个请求几乎同时到达，加延迟后分散在 300ms 内
+	require.Len(t, arrivals, 8)
+	first, last := arrivals[0], arrivals[0]
+	for _, at := range arrivals {
+		if at.Before(first) {
+			first = at
+		}
+		if at.After(last) {
+			last = at
+		}
+	}
+	assert.Greater(t, last.Sub(first), 50*time.Millisecond)
+	assert.Less(t, last.Sub(start), 300*time.Millisecond+100*time.Millisecond)
+}
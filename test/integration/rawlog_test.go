@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawLog(t *testing.T) {
+	server := newOKServer()
+	defer server.Close()
+
+	// 超过内存中保留的 10000 条明细结果
+	const total = 10050
+	filename := filepath.Join(t.TempDir(), "logs", "requests.ndjson")
+	cfg := newLocalConfig(server.URL, total)
+	cfg.Concurrency = 10
+	cfg.RawLog = filename
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+
+	result := tester.Run()
+	tester.Cleanup()
+	require.Equal(t, int64(total), result.TotalRequests)
+	assert.Len(t, result.DetailedResults, 10000)
+
+	file, err := os.Open(filename)
+	require.NoError(t, err)
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record types.RequestResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, http.StatusOK, record.StatusCode)
+		assert.Positive(t, record.Duration)
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, total, lines)
+}
+
+func TestRawLog_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	filename := filepath.Join(t.TempDir(), "requests.ndjson")
+	cfg := newLocalConfig(server.URL, 3)
+	cfg.RawLog = filename
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	// Run 结束时已刷新，无需等待 Cleanup
+	tester.Run()
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for i := 0; i < 3; i++ {
+		var record types.RequestResult
+		require.NoError(t, decoder.Decode(&record))
+		assert.False(t, record.Success)
+		assert.Equal(t, http.StatusServiceUnavailable, record.StatusCode)
+		assert.NotEmpty(t, record.Error)
+	}
+	assert.False(t, decoder.More())
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedirectServer /loop 在 /a 与 /b 之间循环跳转，/chain/N 跳转 N 次后返回 200
+func newRedirectServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case r.URL.Path == "/b":
+			http.Redirect(w, r, "/a", http.StatusFound)
+		case strings.HasPrefix(r.URL.Path, "/chain/"):
+			n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/chain/"))
+			if n == 0 {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			http.Redirect(w, r, fmt.Sprintf("/chain/%d", n-1), http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestRedirects(t *testing.T) {
+	server := newRedirectServer()
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		path          string
+		follow        bool
+		maxRedirects  int
+		wantSuccess   bool
+		wantStatus    int
+		wantErrorText string
+	}{
+		{name: "loop", path: "/a", follow: true, maxRedirects: 10, wantErrorText: "redirect loop detected after 2 redirects"},
+		{name: "within limit", path: "/chain/3", follow: true, maxRedirects: 5, wantSuccess: true, wantStatus: http.StatusOK},
+		{name: "exceeds limit", path: "/chain/5", follow: true, maxRedirects: 3, wantErrorText: "too many redirects: stopped after 3 redirects"},
+		{name: "not followed", path: "/a", follow: false, wantSuccess: true, wantStatus: http.StatusFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newLocalConfig(server.URL+tt.path, 4)
+			cfg.FollowRedirects = tt.follow
+			cfg.MaxRedirects = tt.maxRedirects
+
+			tester, err := engine.NewStressEngine(cfg)
+			require.NoError(t, err)
+			defer tester.Cleanup()
+
+			result := tester.Run()
+			require.Equal(t, int64(4), result.TotalRequests)
+
+			if tt.wantSuccess {
+				assert.Equal(t, int64(4), result.SuccessfulRequests)
+				assert.Equal(t, int64(4), result.GetStatusCodeCount(tt.wantStatus))
+				return
+			}
+
+			assert.Equal(t, int64(4), result.FailedRequests)
+			assert.Equal(t, int64(4), result.GetErrorCategories()[types.ErrorCategoryRedirectLoop])
+			errors, _ := result.GetSortedErrors()
+			require.Len(t, errors, 1)
+			assert.Equal(t, tt.wantErrorText, errors[0].Error)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVDelay(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	csvFile := filepath.Join(t.TempDir(), "replay.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("path,__delay_ms\na,0\nb,150\nc,\nd,300\n"), 0644))
+
+	cfg := newLocalConfig(server.URL+"/{{path}}", 4)
+	cfg.Concurrency = 1
+	cfg.CSVFile = csvFile
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(4), result.SuccessfulRequests)
+	require.Len(t, arrivals, 4)
+
+	// 等待时间不计入响应时间
+	assert.Less(t, result.MaxResponseTime, 100*time.Millisecond)
+
+	gaps := []time.Duration{arrivals[1].Sub(arrivals[0]), arrivals[2].Sub(arrivals[1]), arrivals[3].Sub(arrivals[2])}
+	assert.InDelta(t, 150*time.Millisecond, gaps[0], float64(50*time.Millisecond))
+	assert.Less(t, gaps[1], 50*time.Millisecond, "empty delay should not wait")
+	assert.InDelta(t, 300*time.Millisecond, gaps[2], float64(50*time.Millisecond))
+}
+
+func TestCSVDelayInvalid(t *testing.T) {
+	csvFile := filepath.Join(t.TempDir(), "replay.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("id,__delay_ms\n1,10\n2,-5\n"), 0644))
+
+	cfg := newLocalConfig("http://localhost", 1)
+	cfg.CSVFile = csvFile
+
+	_, err := engine.NewStressEngine(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid __delay_ms value on row 2: "-5"`)
+}
+
+func TestCSVDelayStop(t *testing.T) {
+	csvFile := filepath.Join(t.TempDir(), "replay.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("id,__delay_ms\n1,60000\n"), 0644))
+
+	cfg := newLocalConfig("http://localhost", 1)
+	cfg.Concurrency = 1
+	cfg.CSVFile = csvFile
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	// 停止测试时不必等待剩余的间隔
+	time.AfterFunc(100*time.Millisecond, tester.Stop)
+	start := time.Now()
+	result := tester.Run()
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int64(0), result.TotalRequests)
+}
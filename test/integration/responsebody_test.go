@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBodyOn(t *testing.T) {
+	successBody := strings.Repeat("x", 64*1024)
+
+	tests := []struct {
+		mode        string
+		successSize int  // 成功响应记录的大小
+		errorDetail bool // 错误信息是否包含响应体
+	}{
+		{types.ReadBodyAll, len(successBody), true},
+		{types.ReadBodyError, len(successBody), true},
+		{types.ReadBodyNone, len(successBody), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			var conns, count int64
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt64(&count, 1)%5 == 0 {
+					w.WriteHeader(http.StatusInternalServerError)
+					w.Write([]byte("database timeout"))
+					return
+				}
+				w.Write([]byte(successBody))
+			}))
+			server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					atomic.AddInt64(&conns, 1)
+				}
+			}
+			server.Start()
+			defer server.Close()
+
+			cfg := newLocalConfig(server.URL, 20)
+			cfg.Concurrency = 1
+			cfg.ReadBodyOn = tt.mode
+
+			tester, err := engine.NewStressEngine(cfg)
+			require.NoError(t, err)
+			defer tester.Cleanup()
+
+			result := tester.Run()
+			require.Equal(t, int64(20), result.TotalRequests)
+			assert.Equal(t, int64(16), result.SuccessfulRequests)
+			assert.Equal(t, int64(4), result.FailedRequests)
+
+			// 丢弃的响应体同样读完，连接始终被复用
+			assert.Equal(t, int64(1), atomic.LoadInt64(&conns))
+
+			for _, r := range result.DetailedResults {
+				if r.Success {
+					assert.Equal(t, tt.successSize, r.ResponseSize)
+				}
+			}
+
+			errors := result.GetErrorCounts()
+			if tt.errorDetail {
+				assert.Equal(t, int64(4), errors["HTTP 500: 500 Internal Server Error - database timeout"])
+			} else {
+				assert.Equal(t, int64(4), errors["HTTP 500: 500 Internal Server Error"])
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryOnStatus(t *testing.T) {
+	// 按到达顺序返回的状态码，之后的请求都成功：第 1 个请求重试后成功，第 2 个请求的 500 不在重试列表中，
+	// 第 3 个请求重试 2 次后仍失败
+	statuses := []int{
+		http.StatusServiceUnavailable, http.StatusOK,
+		http.StatusInternalServerError,
+		http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+	}
+	var count int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := int(atomic.AddInt64(&count, 1)); n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+		}
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 4)
+	cfg.Concurrency = 1
+	cfg.RetryCount = 2
+	cfg.RetryOnStatus = "502,503,504"
+	require.NoError(t, cfg.Validate())
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(4), result.TotalRequests)
+	assert.Equal(t, int64(2), result.SuccessfulRequests)
+	assert.Equal(t, int64(7), atomic.LoadInt64(&count))
+
+	require.NotNil(t, result.Retry)
+	assert.Equal(t, int64(2), result.Retry.RetriedRequests)
+	assert.Equal(t, int64(3), result.Retry.Retries)
+	assert.Equal(t, int64(1), result.Retry.RetriedSucceeded)
+
+	errors := result.GetErrorCounts()
+	assert.Equal(t, int64(1), errors["HTTP 500: 500 Internal Server Error"])
+	assert.Equal(t, int64(1), errors["HTTP 503: 503 Service Unavailable"])
+}
+
+func TestRetryWait(t *testing.T) {
+	// 第 1 次尝试返回 503，重试成功，响应时间包含其间的退避等待
+	var count int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 1)
+	cfg.Concurrency = 1
+	cfg.RetryCount = 1
+	cfg.RetryWait = 300 * time.Millisecond
+	cfg.RetryMaxWait = 400 * time.Millisecond
+	require.NoError(t, cfg.Validate())
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(1), result.SuccessfulRequests)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&count))
+	assert.GreaterOrEqual(t, result.MaxResponseTime, 300*time.Millisecond)
+	assert.Less(t, result.MaxResponseTime, 2*time.Second)
+}
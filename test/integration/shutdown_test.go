@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStopServer 第一个请求稍后返回 500 触发快速失败，其余请求挂起直到被取消
+func newStopServer() *httptest.Server {
+	var count int64
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1) == 1 {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+}
+
+func TestShutdownCanceledRequests(t *testing.T) {
+	server := newStopServer()
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Concurrency = 4
+	cfg.Duration = 10 * time.Second
+	cfg.FailFast = true
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+
+	// 停止时进行中的请求单独归类，默认仍计入失败
+	assert.GreaterOrEqual(t, result.ShutdownCanceled, int64(3))
+	assert.Equal(t, result.ShutdownCanceled+1, result.FailedRequests)
+	assert.Equal(t, result.ShutdownCanceled, result.ErrorCategories[types.ErrorCategoryShutdownCanceled])
+	assert.Equal(t, http.StatusInternalServerError, result.GetFailFast().StatusCode)
+}
+
+func TestShutdownCanceledRequests_Excluded(t *testing.T) {
+	server := newStopServer()
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Concurrency = 4
+	cfg.Duration = 10 * time.Second
+	cfg.FailFast = true
+	cfg.ExcludeShutdownCanceled = true
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+
+	assert.GreaterOrEqual(t, result.ShutdownCanceled, int64(3))
+	assert.Equal(t, int64(1), result.TotalRequests)
+	assert.Equal(t, int64(1), result.FailedRequests)
+	assert.NotContains(t, result.ErrorCategories, types.ErrorCategoryShutdownCanceled)
+}
+
+func TestDrain(t *testing.T) {
+	tests := []struct {
+		name     string
+		latency  time.Duration
+		grace    time.Duration
+		canceled bool // 进行中的请求是否在宽限期结束时被取消
+	}{
+		{"in-flight requests finish within grace", 100 * time.Millisecond, 2 * time.Second, false},
+		{"grace expires", 2 * time.Second, 100 * time.Millisecond, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(tt.latency):
+				}
+			}))
+			defer server.Close()
+
+			cfg := newLocalConfig(server.URL, 0)
+			cfg.Concurrency = 4
+			cfg.Duration = 10 * time.Second
+
+			tester, err := engine.NewStressEngine(cfg)
+			require.NoError(t, err)
+			defer tester.Cleanup()
+
+			time.AfterFunc(250*time.Millisecond, func() { tester.Drain(tt.grace) })
+
+			start := time.Now()
+			result := tester.Run()
+			assert.Less(t, time.Since(start), 3*time.Second)
+			assert.True(t, result.Interrupted)
+			assert.Positive(t, result.TotalRequests)
+
+			if tt.canceled {
+				assert.Equal(t, int64(4), result.ShutdownCanceled)
+			} else {
+				assert.Zero(t, result.ShutdownCanceled)
+				assert.Zero(t, result.FailedRequests)
+			}
+		})
+	}
+}
+
+func TestDrain_OpenModel(t *testing.T) {
+	server := newSlowServer(100 * time.Millisecond)
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Concurrency = 50
+	cfg.RateLimit = 100
+	cfg.Duration = 10 * time.Second
+	cfg.OpenModel = true
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	time.AfterFunc(250*time.Millisecond, func() { tester.Drain(2 * time.Second) })
+
+	// 排空后调度协程不再派发新的到达，在途请求在宽限期内完成，不会被取消
+	start := time.Now()
+	result := tester.Run()
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, result.Interrupted)
+	assert.Positive(t, result.TotalRequests)
+	assert.Zero(t, result.ShutdownCanceled)
+	assert.Zero(t, result.FailedRequests)
+}
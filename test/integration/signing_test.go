@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMACSigning(t *testing.T) {
+	t.Setenv("RST_HMAC_SECRET", "s3cret")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		message := r.Method + "\n" + r.URL.Path + "\n" + r.Header.Get("X-Timestamp") + "\n" + string(body)
+
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(message))
+		if r.Header.Get("X-Signature") != hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL+"/orders", 4)
+	cfg.Method = "POST"
+	cfg.Body = `{"item":"book"}`
+	cfg.HMACHeader = "X-Signature"
+	cfg.HMACAlgorithm = types.DefaultHMACAlgorithm
+	cfg.HMACTemplate = types.DefaultHMACTemplate
+	cfg.HMACEncoding = types.DefaultHMACEncoding
+	cfg.HMACTimestampHeader = types.DefaultHMACTimestampHeader
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(4), result.SuccessfulRequests)
+}
+
+func TestAWSSigV4Signing(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	date := time.Now().UTC().Format("20060102")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"+date+"/us-east-1/execute-api/aws4_request, SignedHeaders=host;x-amz-date, Signature=") ||
+			r.Header.Get("X-Amz-Date") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 4)
+	cfg.AWSSigV4 = "us-east-1:execute-api"
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(4), result.SuccessfulRequests)
+}
+
+func TestAWSSigV4Signing_MissingCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	cfg := newLocalConfig("http://127.0.0.1:1", 1)
+	cfg.AWSSigV4 = "us-east-1:execute-api"
+
+	_, err := engine.NewStressEngine(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AWS_ACCESS_KEY_ID")
+}
@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopOn(t *testing.T) {
+	fast := newOKServer()
+	defer fast.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	run := func(t *testing.T, url string, n int, d time.Duration, stopOn string, setup func(cfg *config.Config)) (*types.StressResult, time.Duration) {
+		cfg := newLocalConfig(url, n)
+		cfg.Duration = d
+		cfg.StopOn = stopOn
+		if setup != nil {
+			setup(cfg)
+		}
+		require.NoError(t, cfg.Validate())
+
+		tester, err := engine.NewStressEngine(cfg)
+		require.NoError(t, err)
+		defer tester.Cleanup()
+
+		start := time.Now()
+		result := tester.Run()
+		return result, time.Since(start)
+	}
+
+	t.Run("first stops on request count", func(t *testing.T) {
+		result, elapsed := run(t, fast.URL, 20, 10*time.Second, types.StopOnFirst, nil)
+		assert.Equal(t, int64(20), result.TotalRequests)
+		assert.Less(t, elapsed, 5*time.Second)
+	})
+
+	t.Run("first stops on duration", func(t *testing.T) {
+		result, elapsed := run(t, slow.URL, 1000000, 300*time.Millisecond, types.StopOnFirst, nil)
+		assert.Less(t, result.TotalRequests, int64(1000000))
+		assert.Less(t, elapsed, 5*time.Second)
+	})
+
+	t.Run("first with rate", func(t *testing.T) {
+		result, elapsed := run(t, fast.URL, 10, 10*time.Second, types.StopOnFirst, func(cfg *config.Config) { cfg.RateLimit = 100 })
+		assert.Equal(t, int64(10), result.TotalRequests)
+		assert.Less(t, elapsed, 5*time.Second)
+	})
+
+	t.Run("both runs for the full duration", func(t *testing.T) {
+		result, elapsed := run(t, fast.URL, 5, 300*time.Millisecond, types.StopOnBoth, nil)
+		assert.Greater(t, result.TotalRequests, int64(5))
+		assert.GreaterOrEqual(t, elapsed, 300*time.Millisecond)
+	})
+
+	t.Run("both sends all requests", func(t *testing.T) {
+		result, _ := run(t, slow.URL, 100, 50*time.Millisecond, types.StopOnBoth, nil)
+		assert.GreaterOrEqual(t, result.TotalRequests, int64(100))
+	})
+}
@@ -1,6 +1,8 @@
 package integration
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -118,3 +120,36 @@ func TestPostRequest(t *testing.T) {
 
 	tester.Cleanup()
 }
+
+func TestCleanupIsIdempotent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tester, err := engine.NewStressEngine(newLocalConfig(server.URL, 5))
+	require.NoError(t, err)
+
+	result := tester.Run()
+	assert.Equal(t, int64(5), result.SuccessfulRequests)
+
+	// 多次清理不应 panic
+	tester.Cleanup()
+	tester.Cleanup()
+}
+
+// newLocalConfig 创建指向本地测试服务器的基础配置
+func newLocalConfig(url string, totalRequests int) *config.Config {
+	return &config.Config{
+		StressConfig: &types.StressConfig{
+			URL:           url,
+			Method:        "GET",
+			TotalRequests: totalRequests,
+			Concurrency:   2,
+			Timeout:       5 * time.Second,
+			KeepAlive:     true,
+
+			FollowRedirects: true,
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuccessGoal(t *testing.T) {
+	// 每 3 个请求中有 1 个失败
+	var count int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&count, 1)%3 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Concurrency = 4
+	cfg.Successes = 30
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(30), result.SuccessfulRequests)
+	assert.Greater(t, result.FailedRequests, int64(0))
+
+	require.NotNil(t, result.SuccessGoal)
+	assert.True(t, result.SuccessGoal.Reached)
+	assert.Equal(t, int64(30), result.SuccessGoal.Successes)
+	assert.Equal(t, result.TotalRequests, result.SuccessGoal.Attempts)
+}
+
+func TestSuccessGoal_AttemptLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 0)
+	cfg.Successes = 5
+	cfg.MaxAttempts = 12
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(12), result.TotalRequests)
+
+	require.NotNil(t, result.SuccessGoal)
+	assert.False(t, result.SuccessGoal.Reached)
+	assert.Equal(t, int64(0), result.SuccessGoal.Successes)
+}
@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRoutingServer 创建按路径返回不同状态码的测试服务器
+func newRoutingServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"ok"}`)
+	})
+	mux.HandleFunc("/created", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestTargetExpectations(t *testing.T) {
+	server := newRoutingServer()
+	defer server.Close()
+
+	cfg := newLocalConfig("", 60)
+	cfg.Targets = []types.TargetSpec{
+		// 期望 404 的目标，404 视为通过
+		{Name: "missing", URL: server.URL + "/missing", Weight: 1, ExpectStatus: []int{404}},
+		// 期望 200 但实际返回 201，视为失败
+		{Name: "created", URL: server.URL + "/created", Weight: 1, Method: "POST", ExpectStatus: []int{200}},
+		// 响应体断言
+		{Name: "ok", URL: server.URL + "/ok", Weight: 1, ExpectBodyContains: `"status":"ok"`},
+	}
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Len(t, result.Targets, 3)
+
+	byName := make(map[string]types.TargetStats)
+	for _, stats := range result.Targets {
+		byName[stats.Name] = stats
+	}
+
+	assert.Greater(t, byName["missing"].TotalRequests, int64(0))
+	assert.Equal(t, byName["missing"].TotalRequests, byName["missing"].SuccessfulRequests)
+	assert.Greater(t, byName["created"].TotalRequests, int64(0))
+	assert.Equal(t, byName["created"].TotalRequests, byName["created"].FailedRequests)
+	assert.Equal(t, byName["ok"].TotalRequests, byName["ok"].SuccessfulRequests)
+	assert.Equal(t, int64(60), byName["missing"].TotalRequests+byName["created"].TotalRequests+byName["ok"].TotalRequests)
+
+	errorList, _ := result.GetSortedErrors()
+	require.NotEmpty(t, errorList)
+	assert.Contains(t, errorList[0].Error, "unexpected status (expected 200)")
+}
+
+func TestTargetPools(t *testing.T) {
+	server := newRoutingServer()
+	defer server.Close()
+
+	cfg := newLocalConfig("", 40)
+	cfg.Concurrency = 4
+	cfg.TargetPools = true
+	cfg.Targets = []types.TargetSpec{
+		{Name: "critical", URL: server.URL + "/ok", Weight: 3},
+		{Name: "background", URL: server.URL + "/created", Weight: 1},
+	}
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Len(t, result.Targets, 2)
+
+	// 工作协程和请求数都按 3:1 分配
+	assert.Equal(t, "critical", result.Targets[0].Name)
+	assert.Equal(t, 3, result.Targets[0].Concurrency)
+	assert.Equal(t, int64(30), result.Targets[0].TotalRequests)
+	assert.Equal(t, 1, result.Targets[1].Concurrency)
+	assert.Equal(t, int64(10), result.Targets[1].TotalRequests)
+	assert.Equal(t, int64(40), result.SuccessfulRequests)
+}
+
+func TestCompareEndpoints(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/v2", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cfg := newLocalConfig("", 100)
+	cfg.CompareEndpoints = server.URL + "/v1," + server.URL + "/v2"
+	cfg.CompareSplit = types.DefaultCompareSplit
+	require.NoError(t, cfg.ApplyCompareEndpoints())
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.NotNil(t, result.Comparison)
+
+	c := result.Comparison
+	assert.Equal(t, int64(100), c.A.TotalRequests+c.B.TotalRequests)
+	assert.Greater(t, c.B.P50ResponseTime, c.A.P50ResponseTime)
+	assert.True(t, c.Significant)
+	assert.Equal(t, "B is significantly slower than A", c.Verdict())
+}
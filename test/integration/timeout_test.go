@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutAs(t *testing.T) {
+	tests := []struct {
+		mode   string
+		passed bool // 错误率门限是否通过
+	}{
+		{types.TimeoutAsFail, false},
+		{types.TimeoutAsWarn, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			// 每 4 个请求中有 1 个超时
+			var count int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt64(&count, 1)%4 == 0 {
+					select {
+					case <-time.After(time.Second):
+					case <-r.Context().Done():
+					}
+				}
+			}))
+			defer server.Close()
+
+			cfg := newLocalConfig(server.URL, 8)
+			cfg.Concurrency = 1
+			cfg.Timeout = 100 * time.Millisecond
+			cfg.TimeoutAs = tt.mode
+
+			tester, err := engine.NewStressEngine(cfg)
+			require.NoError(t, err)
+			defer tester.Cleanup()
+
+			result := tester.Run()
+			require.Equal(t, int64(8), result.TotalRequests)
+			assert.Equal(t, int64(2), result.FailedRequests)
+			assert.Equal(t, int64(2), result.Timeouts)
+			assert.Equal(t, 25.0, result.GetTimeoutRate())
+			assert.Equal(t, int64(2), result.ErrorCategories[types.ErrorCategoryTimeout])
+
+			// 超时计入门限时错误率为 25%，超过默认的 10%；只作提示时为 0
+			slo := result.EvaluateSLOs(cfg.SLOThresholds())
+			assert.Equal(t, tt.passed, slo.Passed)
+		})
+	}
+}
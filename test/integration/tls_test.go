@@ -0,0 +1,271 @@
+package integration
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSSettings(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		modify   func(cfg *types.StressConfig)
+		expected types.TLSNegotiation
+	}{
+		{
+			name: "cipher suite",
+			modify: func(cfg *types.StressConfig) {
+				cfg.TLSMaxVersion = "1.2"
+				cfg.CipherSuites = "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"
+			},
+			expected: types.TLSNegotiation{Version: "TLS 1.2", CipherSuite: "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384", Requests: 5},
+		},
+		{
+			name: "tls 1.3",
+			modify: func(cfg *types.StressConfig) {
+				cfg.TLSMinVersion = "1.3"
+			},
+			expected: types.TLSNegotiation{Version: "TLS 1.3", CipherSuite: tls.CipherSuiteName(tls.TLS_AES_128_GCM_SHA256), Requests: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newLocalConfig(server.URL, 5)
+			cfg.Insecure = true // httptest 使用自签名证书
+			tt.modify(cfg.StressConfig)
+
+			tester, err := engine.NewStressEngine(cfg)
+			require.NoError(t, err)
+			defer tester.Cleanup()
+
+			result := tester.Run()
+			require.Equal(t, int64(5), result.SuccessfulRequests)
+			assert.Equal(t, []types.TLSNegotiation{tt.expected}, result.TLS)
+		})
+	}
+}
+
+func TestTLSVersionRejected(t *testing.T) {
+	// 服务端只支持 TLS 1.3，客户端最高 1.2 时握手失败
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MinVersion: tls.VersionTLS13}
+	server.StartTLS()
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL, 2)
+	cfg.Insecure = true // httptest 使用自签名证书
+	cfg.TLSMaxVersion = "1.2"
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(2), result.FailedRequests)
+	assert.Empty(t, result.TLS)
+}
+
+func TestTLSSessionResumption(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 每个请求都新建连接，首次完整握手，之后的连接恢复缓存的会话
+	cfg := newLocalConfig(server.URL, 5)
+	cfg.Insecure = true // httptest 使用自签名证书
+	cfg.Concurrency = 1
+	cfg.MaxRequestsPerConnection = 1
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(5), result.SuccessfulRequests)
+	require.NotNil(t, result.TLSResumption)
+	assert.Equal(t, int64(5), result.TLSResumption.Handshakes)
+	assert.Equal(t, int64(1), result.TLSResumption.FullHandshakes)
+	assert.Equal(t, int64(4), result.TLSResumption.Resumed)
+	assert.InDelta(t, 80.0, result.TLSResumption.ResumptionRate, 0.01)
+}
+
+func TestDisableKeepAliveReuse(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 每个请求都新建连接且不恢复会话，全部为完整握手
+	cfg := newLocalConfig(server.URL, 6)
+	cfg.Insecure = true // httptest 使用自签名证书
+	cfg.DisableKeepAliveReuse = true
+	cfg.LatencyBreakdown = true
+	require.NoError(t, cfg.Validate())
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(6), result.SuccessfulRequests)
+	require.NotNil(t, result.TLSResumption)
+	assert.Equal(t, int64(6), result.TLSResumption.FullHandshakes)
+	assert.Zero(t, result.TLSResumption.Resumed)
+	require.NotNil(t, result.LatencyBreakdown)
+	assert.Equal(t, int64(6), result.LatencyBreakdown.Connect.Count)
+}
+
+func TestTLSSessionResumption_PlainHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tester, err := engine.NewStressEngine(newLocalConfig(server.URL, 2))
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Nil(t, result.TLSResumption)
+}
+
+func TestTLSHandshakeTiming(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// 设置阈值后追踪每个请求的 TLS 握手耗时
+	cfg := newLocalConfig(server.URL, 4)
+	cfg.Insecure = true // httptest 使用自签名证书
+	cfg.Concurrency = 1
+	cfg.MinTLSLatency = "30%"
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	require.Equal(t, int64(4), result.SuccessfulRequests)
+	assert.Equal(t, int64(1), result.NewConnections)
+
+	overhead := result.DetectTLSOverhead(0.001)
+	require.NotNil(t, overhead)
+	assert.Greater(t, overhead.AvgHandshake, time.Duration(0))
+	assert.Less(t, overhead.AvgHandshake, overhead.AvgResponse)
+}
+
+func TestTLSVerification(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 默认校验服务端证书，自签名证书握手失败
+	tester, err := engine.NewStressEngine(newLocalConfig(server.URL, 2))
+	require.NoError(t, err)
+	result := tester.Run()
+	tester.Cleanup()
+	assert.Equal(t, int64(2), result.FailedRequests)
+	assert.Equal(t, int64(2), result.ErrorCategories[types.ErrorCategoryTLSError])
+
+	// 指定 -ca-cert 后信任该证书
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	writePEM(t, caFile, "CERTIFICATE", server.Certificate().Raw)
+	cfg := newLocalConfig(server.URL, 2)
+	cfg.CACert = caFile
+	tester, err = engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+	result = tester.Run()
+	assert.Equal(t, int64(2), result.SuccessfulRequests)
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	clientCert := writeClientCert(t, certFile, keyFile)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		cert, key string
+		succeeded int64
+	}{
+		{"without client certificate", "", "", 0},
+		{"with client certificate", certFile, keyFile, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newLocalConfig(server.URL, 3)
+			cfg.Insecure = true // httptest 使用自签名证书
+			cfg.ClientCert, cfg.ClientKey = tt.cert, tt.key
+			require.NoError(t, cfg.Validate())
+
+			tester, err := engine.NewStressEngine(cfg)
+			require.NoError(t, err)
+			defer tester.Cleanup()
+
+			result := tester.Run()
+			assert.Equal(t, tt.succeeded, result.SuccessfulRequests)
+		})
+	}
+}
+
+// writeClientCert 生成自签名的客户端证书和私钥并写入 PEM 文件
+func writeClientCert(t *testing.T, certFile, keyFile string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "rst-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+// writePEM 将 DER 数据按 PEM 格式写入文件
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+}
@@ -0,0 +1,128 @@
+package integration
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/budyaya/resty-stress-tester/internal/engine"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUploadAbortServer 只读取请求体的前 64KB，随后重置连接
+func newUploadAbortServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		io.CopyN(io.Discard, buf, 64*1024)
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			tcp.SetLinger(0)
+		}
+		conn.Close()
+	}))
+}
+
+func TestUploadError(t *testing.T) {
+	server := newUploadAbortServer()
+	defer server.Close()
+
+	const bodySize = 32 * 1024 * 1024
+	cfg := newLocalConfig(server.URL, 3)
+	cfg.Method = "POST"
+	cfg.Body = strings.Repeat("x", bodySize)
+	cfg.Concurrency = 1
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(3), result.FailedRequests)
+	assert.Equal(t, int64(3), result.GetErrorCategories()[types.ErrorCategoryUploadError])
+
+	for _, r := range result.DetailedResults {
+		assert.True(t, strings.HasPrefix(r.Error, "upload failed: "), r.Error)
+		assert.Greater(t, r.BytesSent, int64(0))
+		assert.Less(t, r.BytesSent, int64(bodySize))
+	}
+}
+
+func TestUploadError_ResponseSideFailureNotUpload(t *testing.T) {
+	// 请求体已完整发送、在响应阶段断开的请求不属于上传错误
+	server := newUnstableServer()
+	defer server.Close()
+
+	cfg := newLocalConfig(server.URL+"/drop", 3)
+	cfg.Method = "POST"
+	cfg.Body = "small payload"
+	cfg.Concurrency = 1
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(3), result.FailedRequests)
+	assert.Zero(t, result.GetErrorCategories()[types.ErrorCategoryUploadError])
+	assert.Equal(t, int64(3), result.GetErrorCategories()[types.ErrorCategoryConnectionDropped])
+}
+
+func TestMultipartForm(t *testing.T) {
+	var received int64
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received += r.ContentLength
+		mu.Unlock()
+
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("avatar")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		if r.FormValue("kind") != "profile" || r.FormValue("id") != "7" || header.Filename != "avatar.png" || string(content) != "PNGDATA" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "avatar.png")
+	require.NoError(t, os.WriteFile(path, []byte("PNGDATA"), 0644))
+
+	cfg := newLocalConfig(server.URL, 6)
+	cfg.Method = http.MethodPost
+	cfg.FormFields = []string{"kind=profile", "id={{randInt 7 7}}"}
+	cfg.FormFiles = []string{"avatar=@" + path}
+	require.NoError(t, cfg.Validate())
+
+	tester, err := engine.NewStressEngine(cfg)
+	require.NoError(t, err)
+	defer tester.Cleanup()
+
+	result := tester.Run()
+	assert.Equal(t, int64(6), result.SuccessfulRequests)
+	assert.Empty(t, result.GetErrorCounts())
+	assert.Equal(t, received, result.TotalBytesSent)
+	assert.Greater(t, result.GetBytesSentPerSecond(), 0.0)
+}
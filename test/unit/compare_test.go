@@ -0,0 +1,79 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newComparisonResult 按给定响应时间为 A、B 两个目标生成结果
+func newComparisonResult(a, b []time.Duration) *types.StressResult {
+	result := types.NewStressResult()
+	result.RegisterTargets([]string{types.CompareTargetA, types.CompareTargetB})
+	result.EnableComparison(types.CompareTargetA, types.CompareTargetB)
+	for _, d := range a {
+		result.AddResult(&types.RequestResult{Duration: d, Success: true, StatusCode: 200, Target: types.CompareTargetA})
+	}
+	for _, d := range b {
+		result.AddResult(&types.RequestResult{Duration: d, Success: true, StatusCode: 200, Target: types.CompareTargetB})
+	}
+	result.EndTime = result.StartTime.Add(time.Second)
+	result.CalculateMetrics()
+	return result
+}
+
+func TestComparison_Significant(t *testing.T) {
+	var a, b []time.Duration
+	for i := 0; i < 50; i++ {
+		a = append(a, time.Duration(10+i%5)*time.Millisecond)
+		b = append(b, time.Duration(13+i%5)*time.Millisecond)
+	}
+
+	c := newComparisonResult(a, b).Comparison
+	require.NotNil(t, c)
+	assert.Equal(t, int64(50), c.A.TotalRequests)
+	assert.Equal(t, 12*time.Millisecond, c.A.AvgResponseTime)
+	assert.Equal(t, 15*time.Millisecond, c.B.AvgResponseTime)
+	assert.InDelta(t, 25.0, c.AvgDelta, 0.01)
+	assert.True(t, c.Significant)
+	assert.Less(t, c.Z, 0.0)
+	assert.Less(t, c.PValue, 0.001)
+	assert.Equal(t, "B is significantly slower than A", c.Verdict())
+}
+
+func TestComparison_NoDifference(t *testing.T) {
+	var a, b []time.Duration
+	for i := 0; i < 50; i++ {
+		a = append(a, time.Duration(10+i%5)*time.Millisecond)
+		b = append(b, time.Duration(10+(i+2)%5)*time.Millisecond)
+	}
+
+	c := newComparisonResult(a, b).Comparison
+	require.NotNil(t, c)
+	assert.False(t, c.Significant)
+	assert.InDelta(t, 1.0, c.PValue, 0.001)
+	assert.Equal(t, "no significant difference", c.Verdict())
+}
+
+func TestConfig_ApplyCompareEndpoints(t *testing.T) {
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	cfg.CompareEndpoints = "http://a.example/v1, http://b.example/v2"
+	cfg.CompareSplit = 80
+
+	require.NoError(t, cfg.ApplyCompareEndpoints())
+	require.Len(t, cfg.Targets, 2)
+	assert.Equal(t, types.TargetSpec{Name: "A", URL: "http://a.example/v1", Weight: 80}, cfg.Targets[0])
+	assert.Equal(t, types.TargetSpec{Name: "B", URL: "http://b.example/v2", Weight: 20}, cfg.Targets[1])
+
+	cfg = &config.Config{StressConfig: types.DefaultConfig()}
+	cfg.CompareEndpoints = "http://a.example/v1"
+	assert.Error(t, cfg.ApplyCompareEndpoints())
+
+	cfg.CompareEndpoints = "http://a.example/v1,http://b.example/v2"
+	cfg.CompareSplit = 100
+	assert.Error(t, cfg.ApplyCompareEndpoints())
+}
@@ -0,0 +1,166 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	hdrhistogram "github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/internal/reporter"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeHdr 用 HdrHistogram 库解码导出的直方图，返回直方图和每个非零槽位的最小值及计数
+func decodeHdr(t *testing.T, encoded string) (*hdrhistogram.Histogram, map[int64]int64) {
+	histogram, err := hdrhistogram.Decode([]byte(encoded))
+	require.NoError(t, err)
+
+	values := make(map[int64]int64)
+	for _, bar := range histogram.Distribution() {
+		if bar.Count > 0 {
+			values[bar.From] = bar.Count
+		}
+	}
+	return histogram, values
+}
+
+func TestWriteHistogram(t *testing.T) {
+	result := types.NewStressResult()
+	result.StartTime = time.Unix(1700000000, 0)
+	for _, d := range []time.Duration{500 * time.Microsecond, 500 * time.Microsecond, 1500 * time.Microsecond, 100 * time.Millisecond} {
+		result.AddResult(&types.RequestResult{Duration: d, Success: true, StatusCode: 200})
+	}
+	// 失败的请求不计入直方图
+	result.AddResult(&types.RequestResult{Duration: time.Second, StatusCode: 500, Error: "HTTP 500"})
+	result.EndTime = result.StartTime.Add(30 * time.Second)
+
+	filename := filepath.Join(t.TempDir(), "latency.hlog")
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	require.NoError(t, reporter.NewReporter(cfg).WriteHistogram(result, filename))
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "#[Histogram log format version 1.3]", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "#[StartTime: 1700000000.000 (seconds since epoch)"))
+
+	fields := strings.Split(lines[3], ",")
+	require.Len(t, fields, 4)
+	// Interval_Max 为最大值所在槽位的上限
+	assert.Equal(t, []string{"0.000", "30.000", "100.031"}, fields[:3])
+	assert.True(t, strings.HasPrefix(fields[3], "HISTFAAA"))
+
+	histogram, values := decodeHdr(t, fields[3])
+	assert.Equal(t, int64(3), histogram.SignificantFigures())
+	assert.Equal(t, int64(1), histogram.LowestTrackableValue())
+	assert.Equal(t, int64(time.Hour/time.Microsecond), histogram.HighestTrackableValue())
+	assert.Equal(t, int64(4), histogram.TotalCount())
+
+	// 数值单位为微秒，100ms 落在精度为 64µs 的桶中
+	assert.Equal(t, map[int64]int64{500: 2, 1500: 1, 99968: 1}, values)
+}
+
+func TestWriteHistogram_AllRequests(t *testing.T) {
+	// 请求数超过详细记录的上限（10000），直方图仍包含全部请求
+	result := types.NewStressResult()
+	for i := 0; i < 5000; i++ {
+		result.AddResult(&types.RequestResult{Duration: 200 * time.Millisecond, Success: true, StatusCode: 200})
+	}
+	for i := 0; i < 15000; i++ {
+		result.AddResult(&types.RequestResult{Duration: time.Millisecond, Success: true, StatusCode: 200})
+	}
+	result.EndTime = result.StartTime.Add(time.Minute)
+
+	filename := filepath.Join(t.TempDir(), "latency.hlog")
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	require.NoError(t, reporter.NewReporter(cfg).WriteHistogram(result, filename))
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	fields := strings.Split(strings.Split(strings.TrimSpace(string(content)), "\n")[3], ",")
+	histogram, values := decodeHdr(t, fields[3])
+	assert.Equal(t, int64(20000), histogram.TotalCount())
+	assert.Equal(t, int64(15000), values[1000])
+	assert.Equal(t, int64(200), histogram.Max()/1000)
+}
+
+func TestWriteHistogram_Empty(t *testing.T) {
+	result := types.NewStressResult()
+	result.EndTime = result.StartTime
+
+	filename := filepath.Join(t.TempDir(), "latency.hlog")
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	require.NoError(t, reporter.NewReporter(cfg).WriteHistogram(result, filename))
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	fields := strings.Split(strings.Split(strings.TrimSpace(string(content)), "\n")[3], ",")
+	_, values := decodeHdr(t, fields[3])
+	assert.Empty(t, values)
+}
+
+func TestWriteCDF(t *testing.T) {
+	result := types.NewStressResult()
+	for _, d := range []time.Duration{500 * time.Microsecond, 500 * time.Microsecond, 1500 * time.Microsecond, 100 * time.Millisecond} {
+		result.AddResult(&types.RequestResult{Duration: d, Success: true, StatusCode: 200})
+	}
+	// 失败的请求不计入
+	result.AddResult(&types.RequestResult{Duration: time.Second, StatusCode: 500, Error: "HTTP 500"})
+
+	filename := filepath.Join(t.TempDir(), "cdf.csv")
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	require.NoError(t, reporter.NewReporter(cfg).WriteCDF(result, filename))
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	// 每个点为槽位的上限，100ms 所在槽位的精度为 64µs
+	assert.Equal(t, "latency_ms,cumulative_fraction\n"+
+		"0.500,0.500000\n"+
+		"1.500,0.750000\n"+
+		"100.031,1.000000\n", string(content))
+}
+
+func TestWriteCDF_AllRequests(t *testing.T) {
+	// 最早的 5000 个请求较慢，已不在保留的最近 10000 条详细记录中，CDF 仍包含它们
+	result := types.NewStressResult()
+	for i := 0; i < 5000; i++ {
+		result.AddResult(&types.RequestResult{Duration: 100 * time.Millisecond, Success: true, StatusCode: 200})
+	}
+	for i := 0; i < 15000; i++ {
+		result.AddResult(&types.RequestResult{Duration: time.Millisecond, Success: true, StatusCode: 200})
+	}
+
+	filename := filepath.Join(t.TempDir(), "cdf.csv")
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	require.NoError(t, reporter.NewReporter(cfg).WriteCDF(result, filename))
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "latency_ms,cumulative_fraction\n"+
+		"1.000,0.750000\n"+
+		"100.031,1.000000\n", string(content))
+}
+
+func TestWriteCDF_SlotPrecision(t *testing.T) {
+	result := types.NewStressResult()
+	// 2048µs 以上槽位宽度为 2µs，2049µs 与 2048µs 落在同一槽位，3000µs 所在槽位的上限为 3001µs
+	for _, d := range []time.Duration{2048 * time.Microsecond, 2049 * time.Microsecond, 3 * time.Millisecond} {
+		result.AddResult(&types.RequestResult{Duration: d, Success: true, StatusCode: 200})
+	}
+
+	filename := filepath.Join(t.TempDir(), "cdf.csv")
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	require.NoError(t, reporter.NewReporter(cfg).WriteCDF(result, filename))
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "latency_ms,cumulative_fraction\n"+
+		"2.049,0.666667\n"+
+		"3.001,1.000000\n", string(content))
+}
@@ -0,0 +1,246 @@
+package unit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_ConcurrentRotation(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "stress.log")
+
+	logger, err := util.NewLoggerWithOptions(util.LoggerOptions{
+		LogFile:       logFile,
+		BufferSize:    256,
+		FlushInterval: 10 * time.Millisecond,
+		MaxFileSize:   2048,
+	})
+	require.NoError(t, err)
+
+	// 多个协程同时大量写日志，强制频繁轮转
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				logger.Info("worker %d message %d %s", id, j, strings.Repeat("x", 32))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	require.NoError(t, logger.Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var backups int
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "stress.log.") {
+			backups++
+			assert.True(t, strings.HasSuffix(entry.Name(), ".gz"), "backup %s should be compressed", entry.Name())
+		}
+	}
+	assert.Greater(t, backups, 0, "expected rotated backup files")
+	assert.FileExists(t, logFile)
+}
+
+func TestLogger_CompressAndPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "stress.log")
+
+	logger, err := util.NewLoggerWithOptions(util.LoggerOptions{
+		LogFile:     logFile,
+		BufferSize:  256,
+		MaxFileSize: 1024,
+		MaxBackups:  2,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 300; i++ {
+		logger.Info("message %d %s", i, strings.Repeat("y", 64))
+	}
+	require.NoError(t, logger.Close())
+
+	backups, err := filepath.Glob(logFile + ".*")
+	require.NoError(t, err)
+	require.NotEmpty(t, backups)
+	assert.LessOrEqual(t, len(backups), 2)
+
+	// 备份应为有效的 gzip 文件
+	for _, backup := range backups {
+		require.True(t, strings.HasSuffix(backup, ".gz"))
+
+		f, err := os.Open(backup)
+		require.NoError(t, err)
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		content, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "[INFO] message")
+		gz.Close()
+		f.Close()
+	}
+}
+
+func TestLogger_FlushWritesPendingLogs(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "stress.log")
+
+	logger, err := util.NewLoggerWithOptions(util.LoggerOptions{
+		LogFile:       logFile,
+		FlushInterval: time.Hour, // 避免定期刷新干扰
+	})
+	require.NoError(t, err)
+	defer logger.Close()
+
+	for i := 0; i < 100; i++ {
+		logger.Info("pending message %d", i)
+	}
+	logger.Flush()
+
+	content, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, 100, strings.Count(string(content), "pending message"))
+	assert.Contains(t, string(content), "pending message 99")
+}
+
+func TestLogger_ConsoleClearsProgress(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := util.NewLoggerWithOptions(util.LoggerOptions{Verbose: true, Output: &out})
+	require.NoError(t, err)
+	defer logger.Close()
+
+	logger.Progress(5, 10, time.Now().Add(-time.Second), 5, 0)
+	_, err = io.WriteString(logger.Console(), "REPORT\n")
+	require.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "Progress: 5/10")
+	// 报告之前的进度行已被空格覆盖，报告从行首开始
+	assert.True(t, strings.HasSuffix(output, "\rREPORT\n"), "report should start on a cleared line: %q", output)
+
+	// 没有进度行时不会输出多余的清除字符
+	out.Reset()
+	logger.ClearProgress()
+	_, err = io.WriteString(logger.Console(), "DONE\n")
+	require.NoError(t, err)
+	assert.Equal(t, "DONE\n", out.String())
+}
+
+func TestLogger_ConcurrentProgressAndReport(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := util.NewLoggerWithOptions(util.LoggerOptions{Verbose: true, Output: &out})
+	require.NoError(t, err)
+	defer logger.Close()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			logger.Progress(int64(i), 1000, start, 0, 0)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		console := logger.Console()
+		for i := 0; i < 200; i++ {
+			fmt.Fprintf(console, "report-line-%d\n", i)
+		}
+	}()
+	wg.Wait()
+
+	// 每一行报告都完整且从行首开始，没有被进度输出打断
+	output := out.String()
+	for i := 0; i < 200; i++ {
+		line := fmt.Sprintf("report-line-%d\n", i)
+		assert.True(t,
+			strings.HasPrefix(output, line) ||
+				strings.Contains(output, "\r"+line) ||
+				strings.Contains(output, "\n"+line),
+			"line %d was interleaved", i)
+	}
+}
+
+func TestLogger_ProgressWhenRedirected(t *testing.T) {
+	// 管道不是终端，进度按行输出且不含回车
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	defer reader.Close()
+	assert.True(t, util.IsRedirected(writer))
+	assert.False(t, util.IsRedirected(&bytes.Buffer{}))
+
+	logger, err := util.NewLoggerWithOptions(util.LoggerOptions{Verbose: true, Output: writer})
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Second)
+	logger.Progress(1, 10, start, 1, 0)
+	logger.Progress(5, 10, start, 5, 0) // 间隔内的更新被跳过
+	logger.Progress(10, 10, start, 10, 0)
+	require.NoError(t, logger.Close())
+	require.NoError(t, writer.Close())
+
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.NotContains(t, string(output), "\r")
+	assert.Contains(t, string(output), "Progress: 1/10")
+	assert.NotContains(t, string(output), "Progress: 5/10")
+	assert.Contains(t, string(output), "Progress: 10/10")
+	assert.Equal(t, 2, strings.Count(string(output), "\n"))
+}
+
+func TestLogger_Panel(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := util.NewLoggerWithOptions(util.LoggerOptions{Output: &out})
+	require.NoError(t, err)
+	defer logger.Close()
+
+	assert.True(t, logger.Panel([]string{"line 1", "line 2", "line 3"}))
+	assert.Equal(t, "line 1\nline 2\nline 3", out.String())
+
+	// 重绘时光标回到面板首行并清除旧内容
+	out.Reset()
+	assert.True(t, logger.Panel([]string{"line A", "line B"}))
+	assert.Equal(t, "\x1b[2A\r\x1b[Jline A\nline B", out.String())
+
+	// 报告输出前清除面板
+	out.Reset()
+	_, err = io.WriteString(logger.Console(), "REPORT\n")
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b[1A\r\x1b[JREPORT\n", out.String())
+}
+
+func TestLogger_PanelFallbackWhenRedirected(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	defer reader.Close()
+
+	// 未开启 verbose，Progress 选项使回退的进度输出仍然显示
+	logger, err := util.NewLoggerWithOptions(util.LoggerOptions{Output: writer, Progress: true})
+	require.NoError(t, err)
+
+	assert.False(t, logger.Panel([]string{"line 1", "line 2"}))
+	logger.Progress(10, 10, time.Now().Add(-time.Second), 10, 0)
+	require.NoError(t, logger.Close())
+	require.NoError(t, writer.Close())
+
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.NotContains(t, string(output), "\x1b[")
+	assert.NotContains(t, string(output), "line 1")
+	assert.Contains(t, string(output), "Progress: 10/10")
+}
@@ -0,0 +1,119 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/internal/reporter"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRunnerReport 模拟一台压测机生成的 JSON 报告
+func writeRunnerReport(t *testing.T, start time.Time, results ...*types.RequestResult) string {
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	cfg.URL = "http://example.com"
+	cfg.Concurrency = 5
+	cfg.TotalRequests = len(results)
+	cfg.ReportFormat = "json"
+	cfg.OutputFile = filepath.Join(t.TempDir(), "report.json")
+
+	result := types.NewStressResult()
+	result.StartTime = start
+	for _, r := range results {
+		result.AddResult(r)
+	}
+	result.EndTime = start.Add(10 * time.Second)
+	result.CalculateMetrics()
+
+	require.NoError(t, reporter.NewReporter(cfg).GenerateReport(result))
+	return cfg.OutputFile
+}
+
+func TestMergeReports(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var fast, slow []*types.RequestResult
+	for i := 0; i < 100; i++ {
+		fast = append(fast, &types.RequestResult{Duration: 10 * time.Millisecond, Success: true, StatusCode: 200})
+		slow = append(slow, &types.RequestResult{Duration: 200 * time.Millisecond, Success: true, StatusCode: 200})
+	}
+	slow = append(slow,
+		&types.RequestResult{Duration: time.Second, StatusCode: 503, Error: "HTTP 503", ErrorCategory: "http_error"},
+		&types.RequestResult{Duration: time.Second, StatusCode: 503, Error: "HTTP 503", ErrorCategory: "http_error"},
+	)
+
+	a := writeRunnerReport(t, start, fast...)
+	b := writeRunnerReport(t, start.Add(5*time.Second), slow...)
+
+	cfg, result, err := reporter.MergeReports([]string{a, b})
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, cfg.Concurrency)
+	assert.Equal(t, 202, cfg.TotalRequests)
+	assert.Equal(t, "http://example.com", cfg.URL)
+
+	assert.Equal(t, int64(202), result.TotalRequests)
+	assert.Equal(t, int64(200), result.SuccessfulRequests)
+	assert.Equal(t, int64(2), result.FailedRequests)
+	assert.Equal(t, int64(200), result.GetStatusCodeCount(200))
+	assert.Equal(t, map[string]int64{"HTTP 503": 2}, result.Errors)
+	assert.Equal(t, map[string]int64{"http_error": 2}, result.ErrorCategories)
+	assert.Contains(t, result.ErrorExamples, "HTTP 503")
+
+	// 两台机器的运行时间重叠，合并后的时长从最早开始到最晚结束
+	assert.Equal(t, 15*time.Second, result.TotalDuration)
+	assert.Equal(t, 10*time.Millisecond, result.GetMinResponseTime())
+	assert.Equal(t, time.Second, result.GetMaxResponseTime())
+	assert.Equal(t, (100*10*time.Millisecond+100*200*time.Millisecond+2*time.Second)/202, result.GetAverageResponseTime())
+
+	// 分位数由两份报告的样本合并后重新计算
+	assert.Equal(t, 105*time.Millisecond, result.P50ResponseTime)
+	assert.Equal(t, 200*time.Millisecond, result.P99ResponseTime)
+}
+
+func TestMergeReports_RejectsTimeUnit(t *testing.T) {
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	cfg.ReportFormat = "json"
+	cfg.TimeUnit = "ms"
+	cfg.OutputFile = filepath.Join(t.TempDir(), "report.json")
+
+	result := types.NewStressResult()
+	result.AddResult(&types.RequestResult{Duration: time.Millisecond, Success: true, StatusCode: 200})
+	result.CalculateMetrics()
+	require.NoError(t, reporter.NewReporter(cfg).GenerateReport(result))
+
+	plain := writeRunnerReport(t, time.Now(), &types.RequestResult{Duration: time.Millisecond, Success: true, StatusCode: 200})
+
+	_, _, err := reporter.MergeReports([]string{plain, cfg.OutputFile})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "-time-unit")
+}
+
+func TestMergeReports_WeightsByRequestCount(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// 两台机器的请求数都超过详细记录的上限（10000），请求数较多的一台应占更大的权重
+	repeat := func(n int, d time.Duration) []*types.RequestResult {
+		results := make([]*types.RequestResult, n)
+		for i := range results {
+			results[i] = &types.RequestResult{Duration: d, Success: true, StatusCode: 200}
+		}
+		return results
+	}
+	a := writeRunnerReport(t, start, repeat(36000, 10*time.Millisecond)...)
+	b := writeRunnerReport(t, start, repeat(12000, 100*time.Millisecond)...)
+
+	_, result, err := reporter.MergeReports([]string{a, b})
+	require.NoError(t, err)
+	assert.Equal(t, int64(48000), result.SuccessfulRequests)
+	assert.Equal(t, int64(48000), result.LatencyHistogram().TotalCount())
+
+	// 75% 的请求为 10ms：按保留的样本各取 10000 条会得到介于两者之间的 P50
+	assert.InEpsilon(t, 10*time.Millisecond, result.P50ResponseTime, 0.01)
+	assert.InEpsilon(t, 100*time.Millisecond, result.P90ResponseTime, 0.01)
+	assert.InEpsilon(t, 100*time.Millisecond, result.P99ResponseTime, 0.01)
+}
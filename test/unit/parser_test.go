@@ -2,9 +2,15 @@ package unit
 
 import (
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/budyaya/resty-stress-tester/internal/parser"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -51,6 +57,79 @@ func TestCSVParser(t *testing.T) {
 	assert.ElementsMatch(t, expectedHeaders, headers)
 }
 
+func TestCSVParser_Coverage(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("id\n")
+	for i := 1; i <= 15; i++ {
+		content.WriteString(strconv.Itoa(i) + "\n")
+	}
+	csvFile := filepath.Join(t.TempDir(), "users.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte(content.String()), 0644))
+
+	csvParser, err := parser.NewCSVParser(csvFile)
+	require.NoError(t, err)
+	assert.Nil(t, csvParser.Coverage())
+
+	csvParser.EnableCoverage()
+	// 第 1 行使用两次（第 16 个请求循环回到第 1 行），第 2、3 行各一次
+	for _, index := range []int{0, 1, 2, 15} {
+		csvParser.GetRow(index)
+	}
+
+	coverage := csvParser.Coverage()
+	require.NotNil(t, coverage)
+	assert.Equal(t, 15, coverage.Rows)
+	assert.Equal(t, 3, coverage.Used)
+	assert.Equal(t, 12, coverage.Unused)
+	assert.Equal(t, 1, coverage.Reused)
+	// 只列出前 10 个未使用的行号
+	assert.Equal(t, []int{4, 5, 6, 7, 8, 9, 10, 11, 12, 13}, coverage.UnusedRows)
+}
+
+func TestCSVParser_Mode(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("id\n")
+	for i := 1; i <= 20; i++ {
+		content.WriteString(strconv.Itoa(i) + "\n")
+	}
+	csvFile := filepath.Join(t.TempDir(), "users.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte(content.String()), 0644))
+
+	ids := func(mode string, seed int64, n int) []string {
+		csvParser, err := parser.NewCSVParser(csvFile)
+		require.NoError(t, err)
+		require.NoError(t, csvParser.SetMode(mode, seed))
+		var ids []string
+		for i := 0; i < n; i++ {
+			ids = append(ids, csvParser.GetRow(i)["id"])
+		}
+		return ids
+	}
+
+	sequential := ids(types.CSVModeSequential, 1, 20)
+	assert.Equal(t, "1", sequential[0])
+	assert.Equal(t, "20", sequential[19])
+
+	// 打乱后每行恰好使用一次，第二轮重复同样的顺序
+	shuffled := ids(types.CSVModeShuffleOnce, 42, 40)
+	assert.ElementsMatch(t, sequential, shuffled[:20])
+	assert.NotEqual(t, sequential, shuffled[:20])
+	assert.Equal(t, shuffled[:20], shuffled[20:])
+	assert.Equal(t, shuffled, ids(types.CSVModeShuffleOnce, 42, 40), "same seed, same order")
+
+	// 随机选取不按顺序，且可以复现
+	random := ids(types.CSVModeRandom, 7, 40)
+	assert.NotEqual(t, append(sequential, sequential...), random)
+	assert.Equal(t, random, ids(types.CSVModeRandom, 7, 40))
+	for _, id := range random {
+		assert.Contains(t, sequential, id)
+	}
+
+	csvParser, err := parser.NewCSVParser(csvFile)
+	require.NoError(t, err)
+	assert.ErrorContains(t, csvParser.SetMode("reverse", 1), "invalid CSV mode")
+}
+
 func TestCSVParser_EmptyFile(t *testing.T) {
 	// 创建空文件
 	tmpFile, err := os.CreateTemp("", "empty*.csv")
@@ -129,3 +208,109 @@ func TestTemplateParser_Validation(t *testing.T) {
 	err = tmplParser.ValidateTemplate(invalidTemplate2)
 	assert.Error(t, err)
 }
+
+func TestTemplateParser_Functions(t *testing.T) {
+	tmplParser := parser.NewTemplateParser(nil)
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	// 没有 CSV 数据时每次调用都重新求值
+	uuids := make(map[string]bool)
+	strs := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := tmplParser.Process("{{uuid}}", nil)
+		assert.Regexp(t, uuidPattern, id)
+		uuids[id] = true
+
+		s := tmplParser.Process("{{randString 8}}", nil)
+		assert.Len(t, s, 8)
+		strs[s] = true
+
+		n, err := strconv.Atoi(tmplParser.Process("{{randInt 1 100}}", nil))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, n, 1)
+		assert.LessOrEqual(t, n, 100)
+	}
+	assert.Len(t, uuids, 1000)
+	assert.Len(t, strs, 1000)
+
+	// 同一模板中的多次出现各自求值
+	parts := strings.Split(tmplParser.Process("{{uuid}} {{ uuid }}", nil), " ")
+	require.Len(t, parts, 2)
+	assert.NotEqual(t, parts[0], parts[1])
+
+	ts, err := strconv.ParseInt(tmplParser.Process("{{timestamp}}", nil), 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Unix(), ts, 2)
+
+	// 与 CSV 列混用，同名 CSV 列优先
+	data := map[string]string{"id": "123", "uuid": "fixed"}
+	assert.Equal(t, "123-fixed", tmplParser.Process("{{id}}-{{uuid}}", data))
+
+	// 请求头和 JSON 请求体同样生效
+	headers := tmplParser.ProcessHeaders(map[string]string{"X-Request-ID": "{{uuid}}"}, nil)
+	assert.Regexp(t, uuidPattern, headers["X-Request-ID"])
+	body, err := tmplParser.ProcessJSON(`{"n": {{randInt 5 5}}}`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"n": float64(5)}, body)
+
+	// 参数无效时保持原样，并由 ValidateTemplate 报告
+	for _, tmpl := range []string{"{{randInt 10 1}}", "{{randString x}}", "{{uuid 1}}", "{{missing}}"} {
+		assert.Equal(t, tmpl, tmplParser.Process(tmpl, nil))
+	}
+	assert.Error(t, tmplParser.ValidateTemplate("{{randInt 10 1}}"))
+	assert.NoError(t, tmplParser.ValidateTemplate("{{randInt 1 10}} {{name}}"))
+}
+
+func TestExtractJSONNumber(t *testing.T) {
+	body := []byte(`{"data": {"queue_depth": 42, "depth_str": "7.5", "items": [{"id": 1}, {"id": 2}]}, "name": "x"}`)
+
+	value, err := parser.ExtractJSONNumber(body, "data.queue_depth")
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, value)
+
+	value, err = parser.ExtractJSONNumber(body, "$.data.depth_str")
+	require.NoError(t, err)
+	assert.Equal(t, 7.5, value)
+
+	value, err = parser.ExtractJSONNumber(body, "data.items[1].id")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, value)
+
+	_, err = parser.ExtractJSONNumber(body, "data.missing")
+	assert.Error(t, err)
+
+	_, err = parser.ExtractJSONNumber(body, "name")
+	assert.Error(t, err)
+
+	_, err = parser.ExtractJSONNumber([]byte("not json"), "data")
+	assert.Error(t, err)
+}
+
+func TestParseRateSchedule(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "schedule.txt")
+	require.NoError(t, os.WriteFile(valid, []byte("# warmup\n60s 100\n\n30s 500\n1m 50.5\n"), 0644))
+
+	steps, err := parser.ParseRateSchedule(valid)
+	require.NoError(t, err)
+	require.Len(t, steps, 3)
+	assert.Equal(t, 60*time.Second, steps[0].Duration)
+	assert.Equal(t, 100.0, steps[0].Rate)
+	assert.Equal(t, 50.5, steps[2].Rate)
+	assert.Equal(t, 150*time.Second, types.TotalScheduleDuration(steps))
+
+	invalid := map[string]string{
+		"missing rate":  "60s\n",
+		"bad duration":  "sixty 100\n",
+		"zero rate":     "60s 0\n",
+		"negative rate": "60s -5\n",
+		"empty":         "# only comments\n",
+	}
+	for name, content := range invalid {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "_"))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		_, err := parser.ParseRateSchedule(path)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCombineRuns(t *testing.T) {
+	newRun := func(durations ...time.Duration) *types.StressResult {
+		result := types.NewStressResult()
+		for _, d := range durations {
+			result.AddResult(&types.RequestResult{Duration: d, Success: true, StatusCode: 200})
+		}
+		result.CalculateMetrics()
+		return result
+	}
+
+	// 一次运行全部很快，另一次运行一半请求很慢
+	fast := make([]time.Duration, 100)
+	slow := make([]time.Duration, 100)
+	for i := range fast {
+		fast[i] = 10 * time.Millisecond
+		slow[i] = 10 * time.Millisecond
+		if i >= 50 {
+			slow[i] = 200 * time.Millisecond
+		}
+	}
+
+	summary := types.CombineRuns([]*types.StressResult{newRun(fast...), newRun(slow...)})
+	require.Len(t, summary.Runs, 2)
+	assert.Equal(t, 10*time.Millisecond, summary.Runs[0].P99ResponseTime)
+	assert.Equal(t, 200*time.Millisecond, summary.Runs[1].P99ResponseTime)
+	assert.Equal(t, int64(200), summary.TotalRequests)
+	assert.Equal(t, int64(200), summary.SuccessfulRequests)
+
+	// 合并后 25% 的请求很慢，P90 和 P99 都落在慢请求上，而各次 P99 的平均值低估了尾部延迟
+	assert.Equal(t, 105*time.Millisecond, summary.MeanP99)
+	assert.Equal(t, 10*time.Millisecond, summary.P50ResponseTime)
+	assert.Equal(t, 200*time.Millisecond, summary.P90ResponseTime)
+	assert.Equal(t, 200*time.Millisecond, summary.P99ResponseTime)
+}
+
+func TestCombineRuns_AllRequests(t *testing.T) {
+	newRun := func(n int, d time.Duration) *types.StressResult {
+		result := types.NewStressResult()
+		for i := 0; i < n; i++ {
+			result.AddResult(&types.RequestResult{Duration: d, Success: true, StatusCode: 200})
+		}
+		result.CalculateMetrics()
+		return result
+	}
+
+	// 两次运行的请求数都超过详细记录的上限（10000），合并分位数按各次的全部请求加权
+	summary := types.CombineRuns([]*types.StressResult{newRun(36000, 10*time.Millisecond), newRun(12000, 100*time.Millisecond)})
+	assert.Equal(t, int64(48000), summary.SuccessfulRequests)
+	assert.InEpsilon(t, 10*time.Millisecond, summary.P50ResponseTime, 0.01)
+	assert.InEpsilon(t, 100*time.Millisecond, summary.P90ResponseTime, 0.01)
+	assert.InEpsilon(t, 100*time.Millisecond, summary.P99ResponseTime, 0.01)
+}
+
+func TestCombineRuns_Empty(t *testing.T) {
+	summary := types.CombineRuns(nil)
+	assert.Empty(t, summary.Runs)
+	assert.Zero(t, summary.P99ResponseTime)
+}
@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/budyaya/resty-stress-tester/internal/config"
+	"github.com/budyaya/resty-stress-tester/internal/reporter"
+	"github.com/budyaya/resty-stress-tester/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSLOResult(failed int) *types.StressResult {
+	result := types.NewStressResult()
+	for i := 1; i <= 100; i++ {
+		result.AddResult(&types.RequestResult{
+			Duration:   time.Duration(i) * time.Millisecond,
+			Success:    i > failed,
+			StatusCode: 200,
+			Error:      "boom",
+		})
+	}
+	result.CalculateMetrics()
+	return result
+}
+
+func TestEvaluateSLOs_DefaultErrorRateOnly(t *testing.T) {
+	report := newSLOResult(5).EvaluateSLOs(types.SLOThresholds{})
+
+	assert.True(t, report.Passed)
+	require.Len(t, report.Gates, 1)
+	assert.Equal(t, "error rate", report.Gates[0].Name)
+	assert.Equal(t, "5.00%", report.Gates[0].Actual)
+	assert.Equal(t, "<= 10.00%", report.Gates[0].Threshold)
+
+	assert.Equal(t, types.ExitStatus{Reason: types.ExitReasonOK, Code: 0}, report.ExitStatus())
+
+	report = newSLOResult(20).EvaluateSLOs(types.SLOThresholds{})
+	assert.False(t, report.Passed)
+	assert.Len(t, report.FailedGates(), 1)
+	assert.Equal(t, types.ExitStatus{Reason: "error_rate_exceeded", Code: 1}, report.ExitStatus())
+}
+
+func TestEvaluateSLOs_LatencyGates(t *testing.T) {
+	result := newSLOResult(0)
+
+	report := result.EvaluateSLOs(types.SLOThresholds{
+		MaxP50: 100 * time.Millisecond,
+		MaxP99: 50 * time.Millisecond,
+	})
+
+	assert.False(t, report.Passed)
+	require.Len(t, report.Gates, 3, "error rate plus the two configured percentile gates")
+
+	p50, p99 := report.Gates[1], report.Gates[2]
+	assert.Equal(t, "p50 response time", p50.Name)
+	assert.True(t, p50.Passed)
+	assert.Equal(t, "p99 response time", p99.Name)
+	assert.False(t, p99.Passed)
+	assert.Equal(t, result.P99ResponseTime.String(), p99.Actual)
+	assert.Equal(t, "<= 50ms", p99.Threshold)
+
+	failed := report.FailedGates()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "p99 response time", failed[0].Name)
+	assert.Equal(t, types.ExitStatus{Reason: "p99_exceeded", Code: 1}, report.ExitStatus())
+}
+
+func TestEvaluateSLOs_AvgGate(t *testing.T) {
+	// 1ms 到 100ms 各一个，平均 50.5ms
+	result := newSLOResult(0)
+
+	report := result.EvaluateSLOs(types.SLOThresholds{MaxAvg: 50 * time.Millisecond, MaxP99: time.Second})
+	require.Len(t, report.Gates, 3)
+	avg := report.Gates[1]
+	assert.Equal(t, "avg response time", avg.Name)
+	assert.Equal(t, "50.5ms", avg.Actual)
+	assert.Equal(t, "<= 50ms", avg.Threshold)
+	assert.False(t, avg.Passed)
+	assert.True(t, report.Gates[2].Passed)
+	assert.Equal(t, types.ExitStatus{Reason: "avg_exceeded", Code: 1}, report.ExitStatus())
+
+	report = result.EvaluateSLOs(types.SLOThresholds{MaxAvg: 60 * time.Millisecond})
+	assert.True(t, report.Passed)
+
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	cfg.URL = "http://example.com"
+	cfg.MaxAvg = -time.Millisecond
+	assert.ErrorContains(t, cfg.Validate(), "latency thresholds cannot be negative")
+}
+
+func TestFailureThreshold(t *testing.T) {
+	cfg := &config.Config{StressConfig: types.DefaultConfig()}
+	cfg.URL = "http://example.com"
+	cfg.FailureThreshold = 1
+	require.NoError(t, cfg.Validate())
+
+	// 2% 的错误率低于默认的 10%，但超过配置的 1%
+	result := newSLOResult(2)
+	assert.False(t, result.ShouldFail())
+	result.SetFailureThreshold(cfg.FailureThreshold)
+	assert.True(t, result.ShouldFail())
+
+	report := result.EvaluateSLOs(cfg.SLOThresholds())
+	assert.False(t, report.Passed)
+	assert.Equal(t, "<= 1.00%", report.Gates[0].Threshold)
+	assert.Equal(t, types.ExitStatus{Reason: "error_rate_exceeded", Code: 1}, report.ExitStatus())
+
+	var buf strings.Builder
+	r := reporter.NewReporter(cfg)
+	r.SetOutput(&buf)
+	r.ConsoleReport(result)
+	assert.Contains(t, buf.String(), "High failure rate (2.0%) detected! (threshold 1%)")
+
+	for _, invalid := range []float64{-1, 100.5} {
+		cfg.FailureThreshold = invalid
+		assert.ErrorContains(t, cfg.Validate(), "fail-threshold")
+	}
+}